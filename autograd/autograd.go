@@ -0,0 +1,299 @@
+// Package autograd computes gradients of scalar functions of matrices by reverse-mode automatic
+// differentiation. Operations on Variables are recorded on a Tape as they are performed; calling
+// Backward on a 1 x 1 result replays the tape in reverse, leaving the gradient of the result with
+// respect to every Variable it depends on in that Variable's Grad.
+//
+// A typical optimization loop builds its leaves once and re-records the loss on each step:
+//
+//	tape := autograd.NewTape[float64]()
+//	X, y, w := tape.Var(Xm), tape.Var(ym), tape.Var(pa.Empty[float64](p, 1))
+//	for step := 0; step < steps; step++ {
+//		tape.Reset()
+//		r := X.Mul(w).Sub(y)
+//		loss := r.T().Mul(r).Product(0.5 / float64(n))
+//		if err := loss.Backward(); err != nil {
+//			return err
+//		}
+//		w.SetValue(w.Value().Sub(w.Grad().Product(rate)))
+//	}
+package autograd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kipukun/pa"
+)
+
+// Tape records the Variables created by operations in the order they were performed.
+type Tape[T pa.Float] struct {
+	vars   []*Variable[T]
+	leaves int // vars[:leaves] were created by Var, the rest by operations
+}
+
+// NewTape returns an empty tape.
+func NewTape[T pa.Float]() *Tape[T] {
+	return new(Tape[T])
+}
+
+// Var returns a leaf Variable holding m. m is not copied, so changes to it are seen by operations recorded afterwards.
+func (t *Tape[T]) Var(m *pa.Matrix[T]) *Variable[T] {
+	v := &Variable[T]{value: m, tape: t}
+	// keep the leaves ahead of the operations so that Reset can drop the operations alone
+	t.vars = append(t.vars, nil)
+	copy(t.vars[t.leaves+1:], t.vars[t.leaves:])
+	t.vars[t.leaves] = v
+	t.leaves++
+	return v
+}
+
+// Reset forgets every operation recorded on t and the gradients computed from them,
+// keeping the leaf Variables so they can be used to record the next computation.
+func (t *Tape[T]) Reset() {
+	for _, v := range t.vars[t.leaves:] {
+		v.tape = nil
+	}
+	t.vars = t.vars[:t.leaves]
+	for _, v := range t.vars {
+		v.grad = nil
+	}
+}
+
+// record appends the result of an operation to t. back propagates the gradient of the result into its inputs.
+func (t *Tape[T]) record(value *pa.Matrix[T], back func(grad *pa.Matrix[T])) *Variable[T] {
+	v := &Variable[T]{value: value, tape: t, back: back}
+	t.vars = append(t.vars, v)
+	return v
+}
+
+// Variable is a matrix whose gradient is tracked by a Tape.
+type Variable[T pa.Float] struct {
+	value *pa.Matrix[T]
+	grad  *pa.Matrix[T]
+	tape  *Tape[T]
+	back  func(grad *pa.Matrix[T])
+	err   error
+}
+
+// Value returns the matrix held by v.
+func (v *Variable[T]) Value() *pa.Matrix[T] {
+	return v.value
+}
+
+// SetValue replaces the matrix held by the leaf v, such as with a step taken by an optimizer.
+// Operations recorded before the call keep the value they were computed from.
+func (v *Variable[T]) SetValue(m *pa.Matrix[T]) {
+	v.value = m
+}
+
+// Grad returns the gradient of the output of the last call to Backward with respect to v.
+// It is zero if the output did not depend on v.
+func (v *Variable[T]) Grad() *pa.Matrix[T] {
+	if v.grad == nil {
+		r, c := v.value.Size()
+		return pa.Empty[T](r, c)
+	}
+	return v.grad
+}
+
+// Err returns the first error encountered while computing v or any Variable it depends on.
+func (v *Variable[T]) Err() error {
+	if v.err != nil {
+		return v.err
+	}
+	return v.value.Err()
+}
+
+// accumulate adds g to the gradient of v.
+func (v *Variable[T]) accumulate(g *pa.Matrix[T]) {
+	if v.grad == nil {
+		v.grad = g
+		return
+	}
+	v.grad = v.grad.Add(g)
+}
+
+// check returns an errored Variable if v or any of the operands cannot take part in an operation, or nil.
+func (v *Variable[T]) check(op string, operands ...*Variable[T]) *Variable[T] {
+	if err := v.Err(); err != nil {
+		return &Variable[T]{value: v.value, err: err}
+	}
+	if v.tape == nil {
+		return &Variable[T]{value: v.value, err: fmt.Errorf("Variable.%s: variable belongs to a tape that has been reset", op)}
+	}
+	for _, b := range operands {
+		if err := b.Err(); err != nil {
+			return &Variable[T]{value: b.value, err: err}
+		}
+		if b.tape != v.tape {
+			return &Variable[T]{value: v.value, err: fmt.Errorf("Variable.%s: variables belong to different tapes", op)}
+		}
+	}
+	return nil
+}
+
+// Add returns v + b.
+func (v *Variable[T]) Add(b *Variable[T]) *Variable[T] {
+	if e := v.check("Add", b); e != nil {
+		return e
+	}
+	return v.tape.record(v.value.Add(b.value), func(g *pa.Matrix[T]) {
+		v.accumulate(g)
+		b.accumulate(g)
+	})
+}
+
+// Sub returns v - b.
+func (v *Variable[T]) Sub(b *Variable[T]) *Variable[T] {
+	if e := v.check("Sub", b); e != nil {
+		return e
+	}
+	return v.tape.record(v.value.Sub(b.value), func(g *pa.Matrix[T]) {
+		v.accumulate(g)
+		b.accumulate(g.Product(-1))
+	})
+}
+
+// Mul returns the matrix product vb.
+func (v *Variable[T]) Mul(b *Variable[T]) *Variable[T] {
+	if e := v.check("Mul", b); e != nil {
+		return e
+	}
+	x, y := v.value, b.value
+	return v.tape.record(x.Mul(y), func(g *pa.Matrix[T]) {
+		v.accumulate(g.Mul(y.T()))
+		b.accumulate(x.T().Mul(g))
+	})
+}
+
+// T returns the transpose of v.
+func (v *Variable[T]) T() *Variable[T] {
+	if e := v.check("T"); e != nil {
+		return e
+	}
+	return v.tape.record(v.value.T(), func(g *pa.Matrix[T]) {
+		v.accumulate(g.T())
+	})
+}
+
+// Product returns v scaled by s.
+func (v *Variable[T]) Product(s T) *Variable[T] {
+	if e := v.check("Product"); e != nil {
+		return e
+	}
+	return v.tape.record(v.value.Product(s), func(g *pa.Matrix[T]) {
+		v.accumulate(g.Product(s))
+	})
+}
+
+// Apply returns f applied to each element of v. df is the derivative of f, which Backward needs
+// to carry gradients through f.
+func (v *Variable[T]) Apply(f, df func(T) T) *Variable[T] {
+	if e := v.check("Apply"); e != nil {
+		return e
+	}
+	x := v.value
+	return v.tape.record(x.Apply(f), func(g *pa.Matrix[T]) {
+		d := x.Apply(df)
+		r, c := d.Size()
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				d.Set(i, j, d.At(i, j)*g.At(i, j))
+			}
+		}
+		v.accumulate(d)
+	})
+}
+
+// Sum returns the sums of v along ax, laid out as pa.Matrix.Sum lays them out: a 1 x 1 matrix for pa.All,
+// and otherwise a row vector with an element per row or column.
+func (v *Variable[T]) Sum(ax pa.Axis) *Variable[T] {
+	if e := v.check("Sum"); e != nil {
+		return e
+	}
+	if ax != pa.Row && ax != pa.Column && ax != pa.All {
+		return &Variable[T]{value: v.value, err: fmt.Errorf("Variable.Sum: unknown axis %d", ax)}
+	}
+	r, c := v.value.Size()
+	return v.tape.record(v.value.Sum(ax), func(g *pa.Matrix[T]) {
+		v.accumulate(broadcast(g, r, c, ax, 1))
+	})
+}
+
+// Mean returns the means of v along ax, laid out as Sum lays out sums.
+func (v *Variable[T]) Mean(ax pa.Axis) *Variable[T] {
+	if e := v.check("Mean"); e != nil {
+		return e
+	}
+	r, c := v.value.Size()
+	var n int
+	switch ax {
+	case pa.Row:
+		n = c
+	case pa.Column:
+		n = r
+	case pa.All:
+		n = r * c
+	default:
+		return &Variable[T]{value: v.value, err: fmt.Errorf("Variable.Mean: unknown axis %d", ax)}
+	}
+	return v.tape.record(v.value.Mean(ax), func(g *pa.Matrix[T]) {
+		v.accumulate(broadcast(g, r, c, ax, 1/T(n)))
+	})
+}
+
+// broadcast spreads the gradient g of a reduction along ax of an r x c matrix back over that shape, scaled by s.
+func broadcast[T pa.Float](g *pa.Matrix[T], r, c int, ax pa.Axis, s T) *pa.Matrix[T] {
+	out := pa.Empty[T](r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			var gij T
+			switch ax {
+			case pa.Row:
+				gij = g.At(0, i)
+			case pa.Column:
+				gij = g.At(0, j)
+			case pa.All:
+				gij = g.At(0, 0)
+			}
+			out.Set(i, j, s*gij)
+		}
+	}
+	return out
+}
+
+// Backward computes the gradient of v, which must be 1 x 1, with respect to every Variable recorded
+// on its tape before it, replacing the gradients left by any earlier call.
+func (v *Variable[T]) Backward() error {
+	if err := v.Err(); err != nil {
+		return fmt.Errorf("Variable.Backward: %w", err)
+	}
+	if v.tape == nil {
+		return errors.New("Variable.Backward: variable belongs to a tape that has been reset")
+	}
+	if r, c := v.value.Size(); r != 1 || c != 1 {
+		return fmt.Errorf("Variable.Backward: output must be 1 x 1, got (%d x %d)", r, c)
+	}
+	vars := v.tape.vars
+	end := len(vars) - 1
+	for vars[end] != v {
+		end--
+	}
+	for _, u := range vars {
+		u.grad = nil
+	}
+	v.grad = pa.Filled[T](1, 1, 1)
+	for i := end; i >= v.tape.leaves; i-- {
+		u := vars[i]
+		if u.grad == nil {
+			continue
+		}
+		u.back(u.grad)
+	}
+	for _, u := range vars[:end+1] {
+		if u.grad != nil && u.grad.Err() != nil {
+			return fmt.Errorf("Variable.Backward: %w", u.grad.Err())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package autograd
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kipukun/pa"
+)
+
+func random(rng *rand.Rand, r, c int) *pa.Matrix[float64] {
+	m := pa.Empty[float64](r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rng.NormFloat64())
+		}
+	}
+	return m
+}
+
+func square(x float64) float64  { return x * x }
+func dsquare(x float64) float64 { return 2 * x }
+
+func TestBackward(t *testing.T) {
+	// each output against central differences in every element of every input
+	tests := []struct {
+		name   string
+		shapes [][2]int
+		f      func(in []*Variable[float64]) *Variable[float64]
+	}{
+		{"add", [][2]int{{2, 3}, {2, 3}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Add(in[1]).Apply(square, dsquare).Sum(pa.All)
+		}},
+		{"sub", [][2]int{{2, 3}, {2, 3}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Sub(in[1]).Apply(square, dsquare).Mean(pa.All)
+		}},
+		{"mul", [][2]int{{2, 3}, {3, 4}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Mul(in[1]).Apply(math.Sin, math.Cos).Sum(pa.All)
+		}},
+		{"product and transpose", [][2]int{{3, 2}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].T().Mul(in[0]).Product(-0.5).Apply(math.Exp, math.Exp).Mean(pa.All)
+		}},
+		{"sum of rows", [][2]int{{3, 4}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Apply(math.Tanh, func(x float64) float64 { return 1 - math.Pow(math.Tanh(x), 2) }).
+				Sum(pa.Row).Apply(square, dsquare).Sum(pa.All)
+		}},
+		{"mean of columns", [][2]int{{3, 4}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Apply(square, dsquare).Mean(pa.Column).Apply(math.Sin, math.Cos).Sum(pa.All)
+		}},
+		{"reused input", [][2]int{{2, 2}}, func(in []*Variable[float64]) *Variable[float64] {
+			return in[0].Mul(in[0]).Add(in[0]).Mean(pa.Row).Apply(square, dsquare).Mean(pa.All)
+		}},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tape := NewTape[float64]()
+			in := make([]*Variable[float64], len(tt.shapes))
+			for k, s := range tt.shapes {
+				in[k] = tape.Var(random(rng, s[0], s[1]))
+			}
+			out := tt.f(in)
+			if err := out.Backward(); err != nil {
+				t.Fatalf("Backward() error: %v", err)
+			}
+			for k, v := range in {
+				grad := v.Grad()
+				m := v.Value()
+				r, c := m.Size()
+				for i := 0; i < r; i++ {
+					for j := 0; j < c; j++ {
+						const h = 1e-6
+						x := m.At(i, j)
+						m.Set(i, j, x+h)
+						up := tt.f(in).Value().At(0, 0)
+						m.Set(i, j, x-h)
+						down := tt.f(in).Value().At(0, 0)
+						m.Set(i, j, x)
+						if num := (up - down) / (2 * h); math.Abs(num-grad.At(i, j)) > 1e-6 {
+							t.Errorf("input %d: gradient (%d, %d) = %v, central difference %v", k, i, j, grad.At(i, j), num)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBackwardErrors(t *testing.T) {
+	tape := NewTape[float64]()
+	a, b := tape.Var(pa.Empty[float64](2, 3)), tape.Var(pa.Empty[float64](2, 2))
+
+	if err := a.Sum(pa.Column).Backward(); err == nil {
+		t.Error("Backward() of a 1 x 3 output returned nil error")
+	}
+	bad := tape.Var(pa.Empty[float64](2, 3)).Mul(tape.Var(pa.Empty[float64](2, 3)))
+	if bad.Err() == nil {
+		t.Fatal("Mul() of mismatched shapes returned nil error")
+	}
+	if err := bad.Sum(pa.All).Backward(); err == nil {
+		t.Error("Backward() through a failed operation returned nil error")
+	}
+	if err := a.Add(NewTape[float64]().Var(pa.Empty[float64](2, 3))).Err(); err == nil {
+		t.Error("Add() across tapes returned nil error")
+	}
+	if err := a.Mean(pa.Axis(9)).Err(); err == nil {
+		t.Error("Mean() with an unknown axis returned nil error")
+	}
+
+	loss := a.Mean(pa.All)
+	tape.Reset()
+	if err := loss.Backward(); err == nil {
+		t.Error("Backward() after Reset returned nil error")
+	}
+	if g := b.Grad(); g.At(1, 1) != 0 {
+		t.Errorf("Grad() of an unused variable = %v, want zeros", g)
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	// gradient descent on a re-recorded tape recovers the coefficients of an exact linear model
+	rng := rand.New(rand.NewSource(2))
+	Xm := random(rng, 50, 3)
+	want := pa.NewMatrix([][]float64{{1}, {-2}, {0.5}}, nil)
+	tape := NewTape[float64]()
+	X, y, w := tape.Var(Xm), tape.Var(Xm.Mul(want)), tape.Var(pa.Empty[float64](3, 1))
+	for step := 0; step < 500; step++ {
+		tape.Reset()
+		loss := X.Mul(w).Sub(y).Apply(square, dsquare).Mean(pa.All)
+		if err := loss.Backward(); err != nil {
+			t.Fatalf("Backward() error: %v", err)
+		}
+		w.SetValue(w.Value().Sub(w.Grad().Product(0.1)))
+	}
+	for i := 0; i < 3; i++ {
+		if got := w.Value().At(i, 0); math.Abs(got-want.At(i, 0)) > 1e-6 {
+			t.Errorf("w[%d] = %v, want %v", i, got, want.At(i, 0))
+		}
+	}
+	if g := X.Grad(); g.Err() != nil {
+		t.Errorf("Grad() of X error: %v", g.Err())
+	}
+}
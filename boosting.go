@@ -0,0 +1,618 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Objective selects the loss a GradientBoostingClassifier minimizes.
+type Objective int
+
+const (
+	// ObjectiveAuto uses ObjectiveLogistic for two classes and ObjectiveSoftmax for more.
+	ObjectiveAuto Objective = iota
+	// ObjectiveLogistic is the log loss of a binary classification, fitting a single tree in each round.
+	ObjectiveLogistic
+	// ObjectiveSoftmax is the cross-entropy of a classification with any number of classes,
+	// fitting one tree per class in each round.
+	ObjectiveSoftmax
+)
+
+// binner maps the values of each feature to a small number of ordered bins, so split finding
+// only needs a histogram per feature. NaN values fall in a bin of their own after the others.
+type binner struct {
+	// edges holds, per feature, the largest value of each bin but the last
+	edges [][]float64
+}
+
+// newBinner chooses up to maxBins bins per feature from the quantiles of its values in X.
+func newBinner(X *Matrix[float64], maxBins int) binner {
+	b := binner{edges: make([][]float64, X.cols)}
+	values := make([]float64, 0, X.rows)
+	for j := range b.edges {
+		values = values[:0]
+		for i := 0; i < X.rows; i++ {
+			if v := X.at(i, j); !math.IsNaN(v) {
+				values = append(values, v)
+			}
+		}
+		sort.Float64s(values)
+		distinct := values[:0:0]
+		for i, v := range values {
+			if i == 0 || v != values[i-1] {
+				distinct = append(distinct, v)
+			}
+		}
+		var edges []float64
+		if len(distinct) <= maxBins {
+			for i := 1; i < len(distinct); i++ {
+				edges = append(edges, distinct[i-1]+(distinct[i]-distinct[i-1])/2)
+			}
+		} else {
+			for k := 1; k < maxBins; k++ {
+				e := values[k*len(values)/maxBins]
+				if len(edges) == 0 || e > edges[len(edges)-1] {
+					edges = append(edges, e)
+				}
+			}
+		}
+		b.edges[j] = edges
+	}
+	return b
+}
+
+// bins returns the number of bins of feature j, counting the bin for NaN.
+func (b binner) bins(j int) int {
+	return len(b.edges[j]) + 2
+}
+
+// bin returns the bin of value v of feature j.
+func (b binner) bin(j int, v float64) int {
+	if math.IsNaN(v) {
+		return len(b.edges[j]) + 1
+	}
+	return sort.SearchFloat64s(b.edges[j], v)
+}
+
+// transform returns the bins of the elements of X, row-major.
+func (b binner) transform(X *Matrix[float64]) []uint16 {
+	out := make([]uint16, 0, X.rows*X.cols)
+	for i := 0; i < X.rows; i++ {
+		for j, v := range X.row(i) {
+			out = append(out, uint16(b.bin(j, v)))
+		}
+	}
+	return out
+}
+
+// histBin accumulates the gradients, hessians and count of the samples in a bin.
+type histBin struct {
+	g, h float64
+	n    int
+}
+
+// boostNode is a node of a boostTree. Leaves have a negative feature.
+type boostNode struct {
+	feature     int
+	threshold   float64
+	missingLeft bool
+	left, right int
+	value       float64
+}
+
+// boostTree is a regression tree fitted to the gradients of a boosting round.
+type boostTree struct {
+	nodes []boostNode
+}
+
+// predict returns the value of the leaf x falls into. NaN features follow the branch chosen for them in training.
+func (t *boostTree) predict(x []float64) float64 {
+	n := t.nodes[0]
+	for n.feature >= 0 {
+		v := x[n.feature]
+		if math.IsNaN(v) && n.missingLeft || v <= n.threshold {
+			n = t.nodes[n.left]
+		} else {
+			n = t.nodes[n.right]
+		}
+	}
+	return n.value
+}
+
+// boostBuilder grows a boostTree on binned samples.
+type boostBuilder struct {
+	binner
+	binned   []uint16
+	p        int
+	g, h     []float64
+	features []int
+	maxDepth int
+	minLeaf  int
+	lambda   float64
+	eta      float64
+	gains    []float64
+	nodes    []boostNode
+}
+
+// histogram accumulates the gradients of rows into a histogram of each candidate feature.
+func (b *boostBuilder) histogram(rows []int) [][]histBin {
+	hist := make([][]histBin, b.p)
+	for _, f := range b.features {
+		hist[f] = make([]histBin, b.bins(f))
+	}
+	for _, i := range rows {
+		codes := b.binned[i*b.p : (i+1)*b.p]
+		for _, f := range b.features {
+			hb := &hist[f][codes[f]]
+			hb.g += b.g[i]
+			hb.h += b.h[i]
+			hb.n++
+		}
+	}
+	return hist
+}
+
+// score is the reduction in loss a leaf with gradient sum g and hessian sum h achieves, up to a factor of two.
+func (b *boostBuilder) score(g, h float64) float64 {
+	return g * g / (h + b.lambda)
+}
+
+// grow adds a node for rows, whose histogram is hist, and its subtrees, returning the node's index.
+func (b *boostBuilder) grow(rows []int, hist [][]histBin, depth int) int {
+	var total histBin
+	for _, hb := range hist[b.features[0]] {
+		total.g, total.h, total.n = total.g+hb.g, total.h+hb.h, total.n+hb.n
+	}
+	id := len(b.nodes)
+	b.nodes = append(b.nodes, boostNode{feature: -1, left: -1, right: -1, value: -b.eta * total.g / (total.h + b.lambda)})
+	if depth >= b.maxDepth || total.n < 2*b.minLeaf {
+		return id
+	}
+
+	bestGain, feature, split, missingLeft := 1e-12, -1, 0, false
+	parent := b.score(total.g, total.h)
+	for _, f := range b.features {
+		h := hist[f]
+		missing := h[len(h)-1]
+		var left histBin
+		for t := 0; t < len(h)-1; t++ {
+			left.g, left.h, left.n = left.g+h[t].g, left.h+h[t].h, left.n+h[t].n
+			for _, ml := range []bool{false, true} {
+				l := left
+				if ml {
+					if missing.n == 0 {
+						continue
+					}
+					l.g, l.h, l.n = l.g+missing.g, l.h+missing.h, l.n+missing.n
+				}
+				nr := total.n - l.n
+				if l.n < b.minLeaf || nr < b.minLeaf {
+					continue
+				}
+				if gain := b.score(l.g, l.h) + b.score(total.g-l.g, total.h-l.h) - parent; gain > bestGain {
+					bestGain, feature, split, missingLeft = gain, f, t, ml
+				}
+			}
+		}
+	}
+	if feature < 0 {
+		return id
+	}
+	b.gains[feature] += bestGain
+
+	nl := 0
+	missingBin := b.bins(feature) - 1
+	for i, r := range rows {
+		code := int(b.binned[r*b.p+feature])
+		if code == missingBin && missingLeft || code != missingBin && code <= split {
+			rows[nl], rows[i] = rows[i], rows[nl]
+			nl++
+		}
+	}
+	// histogram the smaller child and get the larger one's by subtraction
+	small, large := rows[:nl], rows[nl:]
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	smallHist := b.histogram(small)
+	for _, f := range b.features {
+		for t := range hist[f] {
+			hist[f][t].g -= smallHist[f][t].g
+			hist[f][t].h -= smallHist[f][t].h
+			hist[f][t].n -= smallHist[f][t].n
+		}
+	}
+	leftHist, rightHist := smallHist, hist
+	if nl > len(rows)-nl {
+		leftHist, rightHist = hist, smallHist
+	}
+	left := b.grow(rows[:nl], leftHist, depth+1)
+	right := b.grow(rows[nl:], rightHist, depth+1)
+	threshold := math.Inf(1)
+	if split < len(b.edges[feature]) {
+		threshold = b.edges[feature][split]
+	}
+	n := &b.nodes[id]
+	n.feature, n.threshold, n.missingLeft, n.left, n.right = feature, threshold, missingLeft, left, right
+	return id
+}
+
+// boostConfig is the validated settings of a boosting fit.
+type boostConfig struct {
+	rounds, depth, minLeaf, maxBins int
+	eta, l2, subsample, colSample   float64
+	seed                            int64
+}
+
+// newBoostConfig validates the settings shared by the gradient boosting estimators, filling in defaults.
+func newBoostConfig(rounds int, eta float64, depth, minLeaf, maxBins int, l2, subsample, colSample float64, seed int64) (boostConfig, error) {
+	cfg := boostConfig{rounds: rounds, depth: depth, minLeaf: minLeaf, maxBins: maxBins,
+		eta: eta, l2: l2, subsample: subsample, colSample: colSample, seed: seed}
+	if cfg.rounds == 0 {
+		cfg.rounds = 100
+	}
+	if cfg.eta == 0 {
+		cfg.eta = 0.1
+	}
+	if cfg.depth == 0 {
+		cfg.depth = 6
+	}
+	if cfg.minLeaf == 0 {
+		cfg.minLeaf = 1
+	}
+	if cfg.maxBins == 0 {
+		cfg.maxBins = 255
+	}
+	if cfg.subsample == 0 {
+		cfg.subsample = 1
+	}
+	if cfg.colSample == 0 {
+		cfg.colSample = 1
+	}
+	switch {
+	case cfg.rounds < 0 || cfg.eta < 0 || cfg.depth < 0 || cfg.minLeaf < 0 || cfg.l2 < 0:
+		return cfg, errors.New("NEstimators, LearningRate, MaxDepth, MinSamplesLeaf and L2 must not be negative")
+	case cfg.maxBins < 2 || cfg.maxBins > math.MaxUint16-1:
+		return cfg, fmt.Errorf("MaxBins must be in [2, %d], got %d", math.MaxUint16-1, cfg.maxBins)
+	case cfg.subsample < 0 || cfg.subsample > 1 || cfg.colSample < 0 || cfg.colSample > 1:
+		return cfg, errors.New("Subsample and ColSample must be in (0, 1]")
+	}
+	return cfg, nil
+}
+
+// checkBoostData checks that X has samples and y is a column vector of one target per sample.
+func checkBoostData[T Number](X, y *Matrix[T]) error {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if X.rows == 0 {
+		return errors.New("X has no samples")
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return fmt.Errorf("y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	return nil
+}
+
+// boostGradient sets g and h to the gradient and hessian of the loss of sample i
+// with respect to its raw scores z, one element per output.
+type boostGradient func(i int, z, g, h []float64)
+
+// booster is the ensemble shared by the gradient boosting estimators. The raw scores of a sample are,
+// for each output, its base score plus the sum of the predictions of that output's trees.
+type booster struct {
+	p           int
+	base        []float64
+	trees       [][]boostTree // per round, one tree per output
+	importances []float64
+}
+
+// boost fits the trees on the samples in the rows of xf, starting every sample from the scores base
+// and fitting each round to the gradients gradient computes at the scores so far.
+func (bs *booster) boost(xf *Matrix[float64], base []float64, gradient boostGradient, cfg boostConfig) {
+	n, k := xf.rows, len(base)
+	bins := newBinner(xf, cfg.maxBins)
+	b := &boostBuilder{binner: bins, binned: bins.transform(xf), p: xf.cols, maxDepth: cfg.depth, minLeaf: cfg.minLeaf,
+		lambda: cfg.l2, eta: cfg.eta, gains: make([]float64, xf.cols)}
+	if b.lambda == 0 {
+		// keeps leaves whose hessians vanish, as confident classifications do, finite
+		b.lambda = 1e-12
+	}
+	scores := Empty[float64](n, k)
+	for i := 0; i < n; i++ {
+		copy(scores.row(i), base)
+	}
+	grads, hess := make([][]float64, k), make([][]float64, k)
+	for c := range grads {
+		grads[c], hess[c] = make([]float64, n), make([]float64, n)
+	}
+	g, h := make([]float64, k), make([]float64, k)
+	rng := rand.New(rand.NewSource(cfg.seed))
+	nRows := int(math.Max(1, math.Round(cfg.subsample*float64(n))))
+	nCols := int(math.Max(1, math.Round(cfg.colSample*float64(xf.cols))))
+	bs.trees = make([][]boostTree, 0, cfg.rounds)
+	for round := 0; round < cfg.rounds; round++ {
+		for i := 0; i < n; i++ {
+			gradient(i, scores.row(i), g, h)
+			for c := range g {
+				grads[c][i], hess[c][i] = g[c], h[c]
+			}
+		}
+		rows := rng.Perm(n)[:nRows]
+		b.features = rng.Perm(xf.cols)[:nCols]
+		sort.Ints(b.features)
+		trees := make([]boostTree, k)
+		for c := range trees {
+			b.g, b.h, b.nodes = grads[c], hess[c], nil
+			r := append([]int(nil), rows...)
+			b.grow(r, b.histogram(r), 0)
+			trees[c] = boostTree{nodes: b.nodes}
+			for i := 0; i < n; i++ {
+				scores.set(i, c, scores.at(i, c)+trees[c].predict(xf.row(i)))
+			}
+		}
+		bs.trees = append(bs.trees, trees)
+	}
+	bs.p, bs.base = xf.cols, base
+	bs.importances = b.gains
+	if total := sum(bs.importances); total > 0 {
+		scale(1/total, bs.importances)
+	}
+}
+
+// decision returns the raw scores of the rows of X, one column per output.
+func (bs *booster) decision(X *Matrix[float64]) (*Matrix[float64], error) {
+	if bs.trees == nil {
+		return nil, errors.New("model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != bs.p {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, bs.p)
+	}
+	scores := Empty[float64](X.rows, len(bs.base))
+	for i := 0; i < X.rows; i++ {
+		row := scores.row(i)
+		copy(row, bs.base)
+		for _, trees := range bs.trees {
+			for c := range trees {
+				row[c] += trees[c].predict(X.row(i))
+			}
+		}
+	}
+	return scores, nil
+}
+
+// FeatureImportances returns the share of the total loss reduction achieved by the splits on each feature.
+func (bs *booster) FeatureImportances() []float64 {
+	return bs.importances
+}
+
+// GradientBoostingRegressor is an ensemble of regression trees fitted in rounds, each to the residuals of
+// the ones before, as in XGBoost and LightGBM. Split finding works on histograms of binned features,
+// so it scales to many samples. NaN features are treated as missing: each split learns which side they go.
+type GradientBoostingRegressor[T Float] struct {
+	// NEstimators is the number of boosting rounds. If zero, 100 is used.
+	NEstimators int
+	// LearningRate shrinks the contribution of each tree. If zero, 0.1 is used.
+	LearningRate float64
+	// MaxDepth limits the depth of each tree. If zero, 6 is used.
+	MaxDepth int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// MaxBins is the largest number of bins a feature is divided into, at most 65534. If zero, 255 is used.
+	MaxBins int
+	// L2 is the L2 penalty on the values of the leaves.
+	L2 float64
+	// Subsample is the fraction of samples, drawn without replacement, each round is fitted on. If zero, 1 is used.
+	Subsample float64
+	// ColSample is the fraction of features, drawn without replacement, each tree may split on. If zero, 1 is used.
+	ColSample float64
+	// Seed seeds the row and column subsampling, so fits are reproducible.
+	Seed int64
+
+	booster
+}
+
+// Fit boosts trees on the samples in the rows of X to minimize the squared error of the targets in the column vector y.
+func (gb *GradientBoostingRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	if err := checkBoostData(X, y); err != nil {
+		return fmt.Errorf("GradientBoostingRegressor.Fit: %w", err)
+	}
+	cfg, err := newBoostConfig(gb.NEstimators, gb.LearningRate, gb.MaxDepth, gb.MinSamplesLeaf, gb.MaxBins,
+		gb.L2, gb.Subsample, gb.ColSample, gb.Seed)
+	if err != nil {
+		return fmt.Errorf("GradientBoostingRegressor.Fit: %w", err)
+	}
+	targets := make([]float64, y.rows)
+	for i := range targets {
+		targets[i] = float64(y.at(i, 0))
+	}
+	gb.boost(convert[float64](X), []float64{mean(targets)}, func(i int, z, g, h []float64) {
+		g[0], h[0] = z[0]-targets[i], 1
+	}, cfg)
+	return nil
+}
+
+// Predict returns the predicted value of each row of X as a column vector.
+func (gb *GradientBoostingRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	scores, err := gb.decision(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("GradientBoostingRegressor.Predict: %w", err)
+	}
+	return convert[T](scores), nil
+}
+
+func (gb *GradientBoostingRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := gb.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// GradientBoostingClassifier is an ensemble of regression trees fitted in rounds, each to the gradient of the
+// log loss of the ones before, as in XGBoost and LightGBM. Split finding works on histograms of binned features,
+// so it scales to many samples. NaN features are treated as missing: each split learns which side they go.
+type GradientBoostingClassifier[T Float] struct {
+	// Objective is the loss to minimize. The zero value is ObjectiveAuto, which picks it from the number of classes.
+	Objective Objective
+	// NEstimators is the number of boosting rounds. If zero, 100 is used.
+	NEstimators int
+	// LearningRate shrinks the contribution of each tree. If zero, 0.1 is used.
+	LearningRate float64
+	// MaxDepth limits the depth of each tree. If zero, 6 is used.
+	MaxDepth int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// MaxBins is the largest number of bins a feature is divided into, at most 65534. If zero, 255 is used.
+	MaxBins int
+	// L2 is the L2 penalty on the values of the leaves.
+	L2 float64
+	// Subsample is the fraction of samples, drawn without replacement, each round is fitted on. If zero, 1 is used.
+	Subsample float64
+	// ColSample is the fraction of features, drawn without replacement, each tree may split on. If zero, 1 is used.
+	ColSample float64
+	// Seed seeds the row and column subsampling, so fits are reproducible.
+	Seed int64
+
+	booster
+	classes []T
+}
+
+// Fit boosts trees on the samples in the rows of X, whose labels are in the column vector y.
+func (gb *GradientBoostingClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if err := checkBoostData(X, y); err != nil {
+		return fmt.Errorf("GradientBoostingClassifier.Fit: %w", err)
+	}
+	cfg, err := newBoostConfig(gb.NEstimators, gb.LearningRate, gb.MaxDepth, gb.MinSamplesLeaf, gb.MaxBins,
+		gb.L2, gb.Subsample, gb.ColSample, gb.Seed)
+	if err != nil {
+		return fmt.Errorf("GradientBoostingClassifier.Fit: %w", err)
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("GradientBoostingClassifier.Fit: need at least two classes, got %d", len(classes))
+	}
+	objective := gb.Objective
+	switch objective {
+	case ObjectiveAuto:
+		objective = ObjectiveSoftmax
+		if len(classes) == 2 {
+			objective = ObjectiveLogistic
+		}
+	case ObjectiveLogistic:
+		if len(classes) != 2 {
+			return fmt.Errorf("GradientBoostingClassifier.Fit: ObjectiveLogistic needs two classes, got %d", len(classes))
+		}
+	case ObjectiveSoftmax:
+	default:
+		return fmt.Errorf("GradientBoostingClassifier.Fit: unknown objective %d", gb.Objective)
+	}
+
+	if objective == ObjectiveLogistic {
+		// a single score, the log-odds of the second class
+		var positives float64
+		for _, l := range labels {
+			positives += float64(l)
+		}
+		freq := math.Min(math.Max(positives/float64(len(labels)), 1e-12), 1-1e-12)
+		gb.boost(convert[float64](X), []float64{math.Log(freq / (1 - freq))}, func(i int, z, g, h []float64) {
+			p := sigmoid(z[0])
+			g[0], h[0] = p-float64(labels[i]), p*(1-p)
+		}, cfg)
+	} else {
+		base := make([]float64, len(classes))
+		for _, l := range labels {
+			base[l]++
+		}
+		for c := range base {
+			base[c] = math.Log(math.Max(base[c]/float64(len(labels)), 1e-12))
+		}
+		gb.boost(convert[float64](X), base, func(i int, z, g, h []float64) {
+			lse := logSumExp(z)
+			for c := range z {
+				p := math.Exp(z[c] - lse)
+				g[c], h[c] = p, p*(1-p)
+			}
+			g[labels[i]]--
+		}, cfg)
+	}
+	gb.classes = classes
+	return nil
+}
+
+// DecisionFunction returns the raw score of each row of X, before the link function:
+// the log-odds of the second class for ObjectiveLogistic,
+// and one column of unnormalized log-probabilities per class for ObjectiveSoftmax.
+func (gb *GradientBoostingClassifier[T]) DecisionFunction(X *Matrix[T]) (*Matrix[T], error) {
+	scores, err := gb.decision(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("GradientBoostingClassifier.DecisionFunction: %w", err)
+	}
+	return convert[T](scores), nil
+}
+
+// proba returns the class probabilities of the rows of X.
+func (gb *GradientBoostingClassifier[T]) proba(X *Matrix[T]) (*Matrix[float64], error) {
+	scores, err := gb.decision(convert[float64](X))
+	if err != nil {
+		return nil, err
+	}
+	proba := Empty[float64](scores.rows, len(gb.classes))
+	for i := 0; i < scores.rows; i++ {
+		row, out := scores.row(i), proba.row(i)
+		if len(row) == 1 {
+			out[1] = sigmoid(row[0])
+			out[0] = 1 - out[1]
+			continue
+		}
+		lse := logSumExp(row)
+		for c, v := range row {
+			out[c] = math.Exp(v - lse)
+		}
+	}
+	return proba, nil
+}
+
+// PredictProba returns the probability of each class for the rows of X, with columns in the order of Classes.
+func (gb *GradientBoostingClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	proba, err := gb.proba(X)
+	if err != nil {
+		return nil, fmt.Errorf("GradientBoostingClassifier.PredictProba: %w", err)
+	}
+	return convert[T](proba), nil
+}
+
+// Predict returns the most probable class of each row of X.
+func (gb *GradientBoostingClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	proba, err := gb.proba(X)
+	if err != nil {
+		return nil, fmt.Errorf("GradientBoostingClassifier.Predict: %w", err)
+	}
+	yh := Empty[T](proba.rows, 1)
+	for i := 0; i < proba.rows; i++ {
+		yh.set(i, 0, gb.classes[argmax(proba.row(i))])
+	}
+	return yh, nil
+}
+
+func (gb *GradientBoostingClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := gb.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+// Classes returns the classes the model was fitted to in ascending order.
+func (gb *GradientBoostingClassifier[T]) Classes() []T {
+	return gb.classes
+}
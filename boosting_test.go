@@ -0,0 +1,169 @@
+package pa
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestGradientBoostingRegression(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	X, y := Empty[float64](300, 3), Empty[float64](300, 1)
+	for i := 0; i < X.rows; i++ {
+		for j := range X.row(i) {
+			X.set(i, j, rng.Float64()*4-2)
+		}
+		y.set(i, 0, math.Sin(2*X.at(i, 0))+X.at(i, 1)*X.at(i, 1))
+	}
+	tests := []struct {
+		name string
+		gb   *GradientBoostingRegressor[float64]
+		min  float64
+	}{
+		{"default", &GradientBoostingRegressor[float64]{}, 0.98},
+		{"subsampled", &GradientBoostingRegressor[float64]{Subsample: 0.7, ColSample: 0.67, Seed: 2}, 0.95},
+		{"coarse", &GradientBoostingRegressor[float64]{MaxBins: 16, MaxDepth: 3, L2: 1, NEstimators: 200}, 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.gb.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			r2, err := tt.gb.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if r2 < tt.min {
+				t.Errorf("Score() = %v, want at least %v", r2, tt.min)
+			}
+			if imp := tt.gb.FeatureImportances(); imp[2] > imp[0] || imp[2] > imp[1] {
+				t.Errorf("FeatureImportances() = %v, want the least weight on the unused feature 2", imp)
+			}
+		})
+	}
+
+	for _, gb := range []*GradientBoostingRegressor[float64]{{LearningRate: -1}, {MaxBins: 1}, {Subsample: 1.5}} {
+		if err := gb.Fit(X, y); err == nil {
+			t.Errorf("Fit() with %+v returned nil error", *gb)
+		}
+	}
+	if _, err := new(GradientBoostingRegressor[float64]).Predict(X); err == nil {
+		t.Error("Predict() before Fit returned nil error")
+	}
+}
+
+func TestGradientBoostingClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		objective Objective
+		centres   [][]float64
+	}{
+		{"auto binary", ObjectiveAuto, [][]float64{{0, 0}, {3, 3}}},
+		{"auto multiclass", ObjectiveAuto, [][]float64{{0, 0}, {4, 0}, {0, 4}}},
+		{"logistic", ObjectiveLogistic, [][]float64{{0, 0}, {3, 3}}},
+		{"softmax binary", ObjectiveSoftmax, [][]float64{{0, 0}, {3, 3}}},
+		{"softmax", ObjectiveSoftmax, [][]float64{{0, 0}, {4, 0}, {0, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := blobs(tt.centres, 50)
+			gb := &GradientBoostingClassifier[float64]{Objective: tt.objective, NEstimators: 50, MaxDepth: 3}
+			if err := gb.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := gb.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.95 {
+				t.Errorf("Score() = %v, want at least 0.95", acc)
+			}
+			proba, err := gb.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			if proba.cols != len(tt.centres) {
+				t.Fatalf("PredictProba() has %d columns, want %d", proba.cols, len(tt.centres))
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+		})
+	}
+
+	X, y := blobs([][]float64{{0, 0}, {4, 0}, {0, 4}}, 10)
+	if err := (&GradientBoostingClassifier[float64]{Objective: ObjectiveLogistic}).Fit(X, y); err == nil {
+		t.Error("Fit() with ObjectiveLogistic on three classes returned nil error")
+	}
+	if err := (&GradientBoostingClassifier[float64]{Objective: 7}).Fit(X, y); err == nil {
+		t.Error("Fit() with an unknown objective returned nil error")
+	}
+
+	// the zero value classifies: its predictions are labels and its score an accuracy
+	var c Classifier[float64] = &GradientBoostingClassifier[float64]{}
+	if err := c.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if got := c.Classes(); len(got) != 3 {
+		t.Errorf("Classes() = %v, want three classes", got)
+	}
+	yh, err := c.Predict(X)
+	if err != nil {
+		t.Fatalf("Predict() error: %v", err)
+	}
+	for i := 0; i < yh.rows; i++ {
+		if v := yh.at(i, 0); v != 0 && v != 1 && v != 2 {
+			t.Fatalf("Predict() row %d = %v, want a label", i, v)
+		}
+	}
+	if acc, _ := c.Score(X, y); acc < 0.95 {
+		t.Errorf("Score() = %v, want an accuracy of at least 0.95", acc)
+	}
+}
+
+func TestGradientBoostingMissing(t *testing.T) {
+	// the class is whether feature 0 is missing; its value otherwise carries no information
+	rng := rand.New(rand.NewSource(1))
+	X, y := Empty[float64](200, 2), Empty[float64](200, 1)
+	for i := 0; i < X.rows; i++ {
+		X.set(i, 0, rng.Float64())
+		X.set(i, 1, rng.Float64())
+		if i%2 == 1 {
+			X.set(i, 0, math.NaN())
+			y.set(i, 0, 1)
+		}
+	}
+	gb := &GradientBoostingClassifier[float64]{NEstimators: 10, MaxDepth: 1}
+	if err := gb.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if acc, _ := gb.Score(X, y); acc != 1 {
+		t.Errorf("Score() = %v, want 1", acc)
+	}
+}
+
+func TestGradientBoostingAgaricus(t *testing.T) {
+	f, err := os.Open("agaricus.txt.train")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	X, y, err := ReadLibSVM(f)
+	if err != nil {
+		t.Fatalf("ReadLibSVM() error: %v", err)
+	}
+	gb := &GradientBoostingClassifier[float64]{NEstimators: 10, MaxDepth: 2, LearningRate: 1}
+	if err := gb.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	acc, err := gb.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if acc < 0.99 {
+		t.Errorf("Score() = %v, want at least 0.99", acc)
+	}
+}
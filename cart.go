@@ -0,0 +1,488 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Criterion selects the impurity a decision tree minimizes when it splits a node.
+type Criterion int
+
+const (
+	// CriterionAuto uses CriterionGini for classification and CriterionMSE for regression.
+	CriterionAuto Criterion = iota
+	// CriterionGini is the Gini impurity, the chance that two samples drawn from the node differ in class.
+	CriterionGini
+	// CriterionEntropy is the Shannon entropy of the classes in the node, in bits.
+	CriterionEntropy
+	// CriterionMSE is the variance of the targets in the node, summed over targets.
+	CriterionMSE
+)
+
+// cartStats accumulates the targets of the samples in a node. Class labels are one-hot rows,
+// so sum holds the count of each class.
+type cartStats struct {
+	n   float64
+	sum []float64
+	sq  float64
+}
+
+func (s *cartStats) add(y []float64, sign float64) {
+	s.n += sign
+	axpy(sign, y, s.sum)
+	s.sq += sign * dotf(y, y)
+}
+
+// impurity returns the impurity of the node summarized by s.
+func (c Criterion) impurity(s *cartStats) float64 {
+	if s.n == 0 {
+		return 0
+	}
+	var imp float64
+	switch c {
+	case CriterionGini:
+		imp = 1
+		for _, v := range s.sum {
+			imp -= (v / s.n) * (v / s.n)
+		}
+	case CriterionEntropy:
+		for _, v := range s.sum {
+			if v > 0 {
+				imp -= v / s.n * math.Log2(v/s.n)
+			}
+		}
+	case CriterionMSE:
+		imp = s.sq / s.n
+		for _, v := range s.sum {
+			imp -= (v / s.n) * (v / s.n)
+		}
+	}
+	return math.Max(imp, 0)
+}
+
+// cartConfig is the validated settings of a tree.
+type cartConfig struct {
+	criterion         Criterion
+	maxDepth          int
+	minSplit, minLeaf int
+	ccpAlpha          float64
+	// maxFeatures, if positive, is the number of features drawn at random as candidates for each split.
+	maxFeatures int
+	// random draws the threshold of each candidate feature uniformly between its extremes in the node,
+	// instead of searching for the best one, as extremely randomized trees do.
+	random bool
+	rng    *rand.Rand
+}
+
+// newCARTConfig validates the settings shared by the decision trees, filling in defaults.
+func newCARTConfig(criterion Criterion, maxDepth, minSplit, minLeaf int, ccpAlpha float64) (cartConfig, error) {
+	cfg := cartConfig{criterion: criterion, maxDepth: maxDepth, minSplit: minSplit, minLeaf: minLeaf, ccpAlpha: ccpAlpha}
+	if maxDepth < 0 {
+		return cfg, fmt.Errorf("MaxDepth must not be negative, got %d", maxDepth)
+	}
+	if cfg.minSplit == 0 {
+		cfg.minSplit = 2
+	}
+	if cfg.minSplit < 2 {
+		return cfg, fmt.Errorf("MinSamplesSplit must be at least 2, got %d", minSplit)
+	}
+	if cfg.minLeaf == 0 {
+		cfg.minLeaf = 1
+	}
+	if cfg.minLeaf < 1 {
+		return cfg, fmt.Errorf("MinSamplesLeaf must be at least 1, got %d", minLeaf)
+	}
+	if ccpAlpha < 0 {
+		return cfg, fmt.Errorf("CCPAlpha must not be negative, got %v", ccpAlpha)
+	}
+	return cfg, nil
+}
+
+// treeNode is a node of a cart. Leaves have a negative feature.
+type treeNode struct {
+	feature     int
+	threshold   float64
+	left, right int
+	impurity    float64
+	samples     int
+	value       []float64
+}
+
+// cart is a binary decision tree grown by CART: each node splits on the feature and threshold
+// that most reduce the impurity of its targets. Nodes are stored in depth-first order with the root first.
+type cart struct {
+	nodes       []treeNode
+	p           int
+	columns     []string
+	importances []float64
+}
+
+// cartBuilder holds the state of growing a cart.
+type cartBuilder struct {
+	cfg      cartConfig
+	X        *Matrix[float64]
+	targets  *Matrix[float64]
+	nodes    []treeNode
+	order    []int
+	features []int
+}
+
+// build grows the tree on the rows of X listed in idx, whose targets are the rows of targets, then prunes it.
+// A row listed twice counts as two samples. If idx is nil, every row is used once.
+// Classification targets are one-hot rows.
+func (t *cart) build(X, targets *Matrix[float64], idx []int, cfg cartConfig) {
+	if idx == nil {
+		idx = make([]int, X.rows)
+		for i := range idx {
+			idx[i] = i
+		}
+	} else {
+		idx = append([]int(nil), idx...)
+	}
+	b := &cartBuilder{cfg: cfg, X: X, targets: targets, order: make([]int, len(idx)), features: make([]int, X.cols)}
+	for f := range b.features {
+		b.features[f] = f
+	}
+	b.grow(idx, 0)
+	t.nodes, t.p, t.columns = b.nodes, X.cols, X.columns
+	if cfg.ccpAlpha > 0 {
+		t.prune(cfg.ccpAlpha)
+	}
+	t.importances = t.featureImportances()
+}
+
+// grow adds a node for the samples idx and, unless it becomes a leaf, its subtrees. It returns the node's index.
+func (b *cartBuilder) grow(idx []int, depth int) int {
+	stats := b.stats(idx)
+	value := append([]float64(nil), stats.sum...)
+	scale(1/stats.n, value)
+	id := len(b.nodes)
+	b.nodes = append(b.nodes, treeNode{feature: -1, left: -1, right: -1, impurity: b.cfg.criterion.impurity(stats), samples: len(idx), value: value})
+	if b.cfg.maxDepth > 0 && depth >= b.cfg.maxDepth || len(idx) < b.cfg.minSplit || len(idx) < 2*b.cfg.minLeaf || b.nodes[id].impurity <= 1e-12 {
+		return id
+	}
+	feature, threshold, ok := b.split(idx, stats)
+	if !ok {
+		return id
+	}
+	nl := 0
+	for i, s := range idx {
+		if b.X.at(s, feature) <= threshold {
+			idx[nl], idx[i] = idx[i], idx[nl]
+			nl++
+		}
+	}
+	left := b.grow(idx[:nl], depth+1)
+	right := b.grow(idx[nl:], depth+1)
+	n := &b.nodes[id]
+	n.feature, n.threshold, n.left, n.right = feature, threshold, left, right
+	return id
+}
+
+func (b *cartBuilder) stats(idx []int) *cartStats {
+	s := &cartStats{sum: make([]float64, b.targets.cols)}
+	for _, i := range idx {
+		s.add(b.targets.row(i), 1)
+	}
+	return s
+}
+
+// split returns the feature and threshold that minimize the weighted impurity of the children of the
+// node holding the samples idx, among those leaving at least minLeaf samples on each side.
+// ok is false if no candidate feature separates the samples.
+func (b *cartBuilder) split(idx []int, total *cartStats) (feature int, threshold float64, ok bool) {
+	best := math.Inf(1)
+	left := &cartStats{sum: make([]float64, len(total.sum))}
+	right := &cartStats{sum: make([]float64, len(total.sum))}
+	for _, f := range b.candidates() {
+		var imp, thr float64
+		var found bool
+		if b.cfg.random {
+			imp, thr, found = b.randomSplit(idx, f, total, left, right)
+		} else {
+			imp, thr, found = b.bestSplit(idx, f, total, left, right)
+		}
+		if found && imp < best {
+			best, feature, threshold, ok = imp, f, thr, true
+		}
+	}
+	return feature, threshold, ok
+}
+
+// candidates returns the features to consider for a split: all of them,
+// or maxFeatures drawn without replacement.
+func (b *cartBuilder) candidates() []int {
+	k := b.cfg.maxFeatures
+	if k <= 0 || k >= len(b.features) {
+		return b.features
+	}
+	for i := 0; i < k; i++ {
+		j := i + b.cfg.rng.Intn(len(b.features)-i)
+		b.features[i], b.features[j] = b.features[j], b.features[i]
+	}
+	return b.features[:k]
+}
+
+// resetStats makes left empty and right a copy of total.
+func resetStats(total, left, right *cartStats) {
+	left.n, left.sq, right.n, right.sq = 0, 0, total.n, total.sq
+	for c := range left.sum {
+		left.sum[c], right.sum[c] = 0, total.sum[c]
+	}
+}
+
+// bestSplit returns the threshold on feature f that minimizes the weighted impurity of the children,
+// and that impurity.
+func (b *cartBuilder) bestSplit(idx []int, f int, total, left, right *cartStats) (imp, threshold float64, ok bool) {
+	imp = math.Inf(1)
+	order := b.order[:len(idx)]
+	copy(order, idx)
+	sort.Slice(order, func(i, j int) bool { return b.X.at(order[i], f) < b.X.at(order[j], f) })
+	resetStats(total, left, right)
+	for i := 0; i < len(order)-1; i++ {
+		y := b.targets.row(order[i])
+		left.add(y, 1)
+		right.add(y, -1)
+		lo, hi := b.X.at(order[i], f), b.X.at(order[i+1], f)
+		nl := i + 1
+		if lo == hi || nl < b.cfg.minLeaf || len(order)-nl < b.cfg.minLeaf {
+			continue
+		}
+		if v := left.n*b.cfg.criterion.impurity(left) + right.n*b.cfg.criterion.impurity(right); v < imp {
+			imp, ok = v, true
+			// the midpoint can round up to hi when the values are adjacent floats
+			if threshold = lo + (hi-lo)/2; threshold >= hi {
+				threshold = lo
+			}
+		}
+	}
+	return imp, threshold, ok
+}
+
+// randomSplit draws a threshold on feature f uniformly between its smallest and largest value in the node,
+// and returns it with the weighted impurity of the children.
+func (b *cartBuilder) randomSplit(idx []int, f int, total, left, right *cartStats) (imp, threshold float64, ok bool) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, i := range idx {
+		lo, hi = math.Min(lo, b.X.at(i, f)), math.Max(hi, b.X.at(i, f))
+	}
+	if lo == hi {
+		return 0, 0, false
+	}
+	if threshold = lo + b.cfg.rng.Float64()*(hi-lo); threshold >= hi {
+		threshold = lo
+	}
+	resetStats(total, left, right)
+	for _, i := range idx {
+		if b.X.at(i, f) <= threshold {
+			y := b.targets.row(i)
+			left.add(y, 1)
+			right.add(y, -1)
+		}
+	}
+	if nl := int(left.n); nl < b.cfg.minLeaf || len(idx)-nl < b.cfg.minLeaf {
+		return 0, 0, false
+	}
+	return left.n*b.cfg.criterion.impurity(left) + right.n*b.cfg.criterion.impurity(right), threshold, true
+}
+
+// prune applies minimal cost-complexity pruning: while some subtree adds no more than alpha
+// of impurity per leaf it saves, the subtree whose removal costs least per leaf is collapsed into a leaf.
+func (t *cart) prune(alpha float64) {
+	total := float64(t.nodes[0].samples)
+	// cost is the weighted impurity a node contributes as a leaf
+	cost := func(n treeNode) float64 { return n.impurity * float64(n.samples) / total }
+	for {
+		weakest, weakestAlpha := -1, math.Inf(1)
+		var visit func(id int) (leaves int, leafCost float64)
+		visit = func(id int) (int, float64) {
+			n := t.nodes[id]
+			if n.feature < 0 {
+				return 1, cost(n)
+			}
+			ll, lc := visit(n.left)
+			rl, rc := visit(n.right)
+			leaves, leafCost := ll+rl, lc+rc
+			if a := (cost(n) - leafCost) / float64(leaves-1); a < weakestAlpha {
+				weakest, weakestAlpha = id, a
+			}
+			return leaves, leafCost
+		}
+		visit(0)
+		if weakest < 0 || weakestAlpha > alpha {
+			break
+		}
+		n := &t.nodes[weakest]
+		n.feature, n.threshold, n.left, n.right = -1, 0, -1, -1
+	}
+	// renumber the remaining nodes in depth-first order
+	var nodes []treeNode
+	var copyNode func(id int) int
+	copyNode = func(id int) int {
+		n := t.nodes[id]
+		at := len(nodes)
+		nodes = append(nodes, n)
+		if n.feature >= 0 {
+			left := copyNode(n.left)
+			right := copyNode(n.right)
+			nodes[at].left, nodes[at].right = left, right
+		}
+		return at
+	}
+	copyNode(0)
+	t.nodes = nodes
+}
+
+// featureImportances returns the total impurity decrease brought by the splits on each feature,
+// weighted by the samples reaching them and normalized to sum to 1.
+func (t *cart) featureImportances() []float64 {
+	imp := make([]float64, t.p)
+	for _, n := range t.nodes {
+		if n.feature < 0 {
+			continue
+		}
+		l, r := t.nodes[n.left], t.nodes[n.right]
+		imp[n.feature] += float64(n.samples)*n.impurity - float64(l.samples)*l.impurity - float64(r.samples)*r.impurity
+	}
+	if total := sum(imp); total > 0 {
+		scale(1/total, imp)
+	}
+	return imp
+}
+
+// leaf returns the leaf that x falls into.
+func (t *cart) leaf(x []float64) treeNode {
+	n := t.nodes[0]
+	for n.feature >= 0 {
+		if x[n.feature] <= n.threshold {
+			n = t.nodes[n.left]
+		} else {
+			n = t.nodes[n.right]
+		}
+	}
+	return n
+}
+
+// predict returns the value of the leaf each row of X falls into.
+func (t *cart) predict(X *Matrix[float64]) (*Matrix[float64], error) {
+	if t.nodes == nil {
+		return nil, fmt.Errorf("model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != t.p {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, t.p)
+	}
+	out := Empty[float64](X.rows, len(t.nodes[0].value))
+	for i := 0; i < X.rows; i++ {
+		copy(out.row(i), t.leaf(X.row(i)).value)
+	}
+	return out, nil
+}
+
+// TreeNode describes a node of a fitted decision tree.
+type TreeNode struct {
+	// ID is the index of the node in depth-first order, with the root at 0.
+	ID int
+	// Depth is the number of splits between the root and the node.
+	Depth int
+	// Feature is the feature the node splits on, or -1 at a leaf.
+	Feature int
+	// Threshold sends samples whose Feature is at most Threshold to Left, and the others to Right.
+	Threshold float64
+	// Left and Right are the IDs of the children, or -1 at a leaf.
+	Left, Right int
+	// Impurity is the impurity of the training samples that reached the node.
+	Impurity float64
+	// Samples is the number of training samples that reached the node.
+	Samples int
+	// Value is the prediction of the node: the fraction of its samples in each class for a classifier,
+	// or their mean targets for a regressor.
+	Value []float64
+}
+
+// Leaf reports whether n is a leaf.
+func (n TreeNode) Leaf() bool {
+	return n.Feature < 0
+}
+
+// Walk calls fn for each node of the tree depth first, parents before children and left before right.
+// If fn returns false, the children of that node are skipped.
+func (t *cart) Walk(fn func(TreeNode) bool) {
+	if t.nodes == nil {
+		return
+	}
+	var visit func(id, depth int)
+	visit = func(id, depth int) {
+		n := t.nodes[id]
+		tn := TreeNode{ID: id, Depth: depth, Feature: n.feature, Threshold: n.threshold, Left: n.left, Right: n.right,
+			Impurity: n.impurity, Samples: n.samples, Value: append([]float64(nil), n.value...)}
+		if fn(tn) && n.feature >= 0 {
+			visit(n.left, depth+1)
+			visit(n.right, depth+1)
+		}
+	}
+	visit(0, 0)
+}
+
+// FeatureImportances returns the share of the impurity decrease achieved by the splits on each feature,
+// weighted by the training samples reaching them. The importances sum to 1 unless the tree is a single leaf.
+func (t *cart) FeatureImportances() []float64 {
+	return t.importances
+}
+
+// Depth returns the largest number of splits between the root and a leaf.
+func (t *cart) Depth() int {
+	var depth int
+	t.Walk(func(n TreeNode) bool {
+		if n.Depth > depth {
+			depth = n.Depth
+		}
+		return true
+	})
+	return depth
+}
+
+// Leaves returns the number of leaves in the tree.
+func (t *cart) Leaves() int {
+	var leaves int
+	for _, n := range t.nodes {
+		if n.feature < 0 {
+			leaves++
+		}
+	}
+	return leaves
+}
+
+// exportText renders the tree as indented rules, naming features after the columns of the training data
+// and describing leaves with label.
+func (t *cart) exportText(label func(value []float64) string) string {
+	var sb strings.Builder
+	name := func(f int) string {
+		if f < len(t.columns) && t.columns[f] != "" {
+			return t.columns[f]
+		}
+		return fmt.Sprintf("x[%d]", f)
+	}
+	var visit func(id, depth int)
+	visit = func(id, depth int) {
+		n := t.nodes[id]
+		indent := strings.Repeat("|   ", depth)
+		if n.feature < 0 {
+			fmt.Fprintf(&sb, "%s|--- %s\n", indent, label(n.value))
+			return
+		}
+		fmt.Fprintf(&sb, "%s|--- %s <= %g\n", indent, name(n.feature), n.threshold)
+		visit(n.left, depth+1)
+		fmt.Fprintf(&sb, "%s|--- %s >  %g\n", indent, name(n.feature), n.threshold)
+		visit(n.right, depth+1)
+	}
+	if t.nodes != nil {
+		visit(0, 0)
+	}
+	return sb.String()
+}
@@ -0,0 +1,80 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCriterionImpurity(t *testing.T) {
+	tests := []struct {
+		name      string
+		criterion Criterion
+		stats     cartStats
+		want      float64
+	}{
+		{"gini pure", CriterionGini, cartStats{n: 4, sum: []float64{4, 0}}, 0},
+		{"gini even", CriterionGini, cartStats{n: 4, sum: []float64{2, 2}}, 0.5},
+		{"gini three classes", CriterionGini, cartStats{n: 4, sum: []float64{2, 1, 1}}, 1 - 0.25 - 1.0/16 - 1.0/16},
+		{"entropy even", CriterionEntropy, cartStats{n: 4, sum: []float64{2, 2}}, 1},
+		{"entropy four classes", CriterionEntropy, cartStats{n: 4, sum: []float64{1, 1, 1, 1}}, 2},
+		// targets 1, 2, 3 have variance 2/3
+		{"mse", CriterionMSE, cartStats{n: 3, sum: []float64{6}, sq: 14}, 2.0 / 3},
+		// the variances of two targets add up
+		{"mse two targets", CriterionMSE, cartStats{n: 2, sum: []float64{2, 0}, sq: 1 + 1 + 1 + 1}, 1},
+		{"empty", CriterionGini, cartStats{sum: []float64{0, 0}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.criterion.impurity(&tt.stats); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("impurity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCARTPrune(t *testing.T) {
+	// the root's Gini impurity is 1 - (3/4)² - (1/4)² = 0.375 and one split makes both leaves pure,
+	// so the split is worth exactly 0.375 per extra leaf
+	X := NewMatrix([][]float64{{0}, {1}, {2}, {3}}, nil)
+	targets := NewMatrix([][]float64{{1, 0}, {1, 0}, {1, 0}, {0, 1}}, nil)
+	tests := []struct {
+		alpha  float64
+		leaves int
+	}{
+		{0, 2},
+		{0.37, 2},
+		{0.375, 1},
+		{0.38, 1},
+	}
+	for _, tt := range tests {
+		cfg, err := newCARTConfig(CriterionGini, 0, 0, 0, tt.alpha)
+		if err != nil {
+			t.Fatalf("newCARTConfig() error: %v", err)
+		}
+		var tree cart
+		tree.build(X, targets, nil, cfg)
+		if got := tree.Leaves(); got != tt.leaves {
+			t.Errorf("with alpha %v Leaves() = %d, want %d", tt.alpha, got, tt.leaves)
+		}
+	}
+}
+
+func TestCARTConfigErrors(t *testing.T) {
+	tests := []struct {
+		name                        string
+		maxDepth, minSplit, minLeaf int
+		alpha                       float64
+	}{
+		{"negative depth", -1, 0, 0, 0},
+		{"split of one", 0, 1, 0, 0},
+		{"negative leaf", 0, 0, -1, 0},
+		{"negative alpha", 0, 0, 0, -0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newCARTConfig(CriterionGini, tt.maxDepth, tt.minSplit, tt.minLeaf, tt.alpha); err == nil {
+				t.Error("newCARTConfig() returned nil error")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotPositiveDefinite is returned when a Cholesky factorization is attempted on a matrix
+// that is not symmetric positive-definite.
+var ErrNotPositiveDefinite = errors.New("matrix is not symmetric positive-definite")
+
+// cholDecomp is a Cholesky decomposition A = LLᵀ, with L lower triangular.
+type cholDecomp[T Number] struct {
+	l *Matrix[T]
+}
+
+func (c *cholDecomp[T]) factor(m *Matrix[T]) error {
+	if m.err != nil {
+		return m.err
+	}
+	if !m.Square() {
+		return fmt.Errorf("FactorCholesky: Cholesky decomposition of a non-square (%d x %d) matrix is undefined", m.rows, m.cols)
+	}
+	n := m.rows
+	tol := epsilon[T]() * float64(n) * float64(maxAbs(m))
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(float64(m.at(i, j)-m.at(j, i))) > tol {
+				return fmt.Errorf("FactorCholesky: %w: element (%d, %d) differs from (%d, %d)", ErrNotPositiveDefinite, i, j, j, i)
+			}
+		}
+	}
+
+	l := Empty[T](n, n)
+	for j := 0; j < n; j++ {
+		lj := l.row(j)
+		d := m.at(j, j)
+		for k := 0; k < j; k++ {
+			d -= lj[k] * lj[k]
+		}
+		if float64(d) <= tol {
+			return fmt.Errorf("FactorCholesky: %w: pivot %d is %v", ErrNotPositiveDefinite, j, d)
+		}
+		lj[j] = T(math.Sqrt(float64(d)))
+		for i := j + 1; i < n; i++ {
+			li := l.row(i)
+			v := m.at(i, j)
+			for k := 0; k < j; k++ {
+				v -= li[k] * lj[k]
+			}
+			li[j] = v / lj[j]
+		}
+	}
+	c.l = l
+	return nil
+}
+
+// FactorCholesky computes the Cholesky decomposition of the symmetric positive-definite matrix m.
+// It returns an error wrapping ErrNotPositiveDefinite if m is not SPD.
+func FactorCholesky[T Number](m *Matrix[T]) (factored[T], error) {
+	c := new(cholDecomp[T])
+	if err := c.factor(m); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *cholDecomp[T]) Det() T {
+	var det T = 1
+	for i := 0; i < c.l.rows; i++ {
+		det *= c.l.at(i, i)
+	}
+	return det * det
+}
+
+// Solve returns x such that Ax = b, where A is the factored matrix. b may have several columns.
+func (c *cholDecomp[T]) Solve(b *Matrix[T]) *Matrix[T] {
+	if b.err != nil {
+		return b
+	}
+	n := c.l.rows
+	if b.rows != n {
+		return errored[T](fmt.Errorf("Solve: right hand side has %d rows, want %d", b.rows, n))
+	}
+
+	x := Empty[T](n, b.cols)
+	y := make([]T, n)
+	for col := 0; col < b.cols; col++ {
+		// forward substitution with L
+		for i := 0; i < n; i++ {
+			v := b.at(i, col)
+			row := c.l.row(i)
+			for j := 0; j < i; j++ {
+				v -= row[j] * y[j]
+			}
+			y[i] = v / row[i]
+		}
+		// back substitution with Lᵀ
+		for i := n - 1; i >= 0; i-- {
+			v := y[i]
+			for j := i + 1; j < n; j++ {
+				v -= c.l.at(j, i) * y[j]
+			}
+			y[i] = v / c.l.at(i, i)
+		}
+		for i := 0; i < n; i++ {
+			x.set(i, col, y[i])
+		}
+	}
+	return x
+}
+
+func (c *cholDecomp[T]) Inverse(m *Matrix[T]) *Matrix[T] {
+	return c.Solve(NewIdentity[T](c.l.rows))
+}
@@ -0,0 +1,47 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFactorCholesky(t *testing.T) {
+	m := NewMatrix([][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}, nil)
+	f, err := FactorCholesky(m)
+	if err != nil {
+		t.Fatalf("FactorCholesky() error: %v", err)
+	}
+	l := NewMatrix([][]float64{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}}, nil)
+	if got := f.(*cholDecomp[float64]).l; !approxEqual(got, l, 1e-12) {
+		t.Errorf("\nL:\n%s\nwant:\n%s", got, l)
+	}
+	if got := f.Det(); math.Abs(got-36) > 1e-9 {
+		t.Errorf("Det() = %v, want 36", got)
+	}
+	x := NewMatrix([][]float64{{1}, {0}, {0.5}}, nil)
+	if got := f.Solve(m.Mul(x)); !approxEqual(got, x, 1e-9) {
+		t.Errorf("\nSolve(b):\n%s\nwant:\n%s", got, x)
+	}
+	if got := m.Mul(f.Inverse(m)); !approxEqual(got, NewIdentity[float64](3), 1e-9) {
+		t.Errorf("\nm.Mul(Inverse()):\n%s\nwant identity", got)
+	}
+}
+
+func TestFactorCholeskyNotSPD(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix[float64]
+	}{
+		{"asymmetric", NewMatrix([][]float64{{2, 1}, {0, 2}}, nil)},
+		{"indefinite", NewMatrix([][]float64{{1, 2}, {2, 1}}, nil)},
+		{"semidefinite", NewMatrix([][]float64{{1, 1}, {1, 1}}, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FactorCholesky(tt.m); !errors.Is(err, ErrNotPositiveDefinite) {
+				t.Errorf("FactorCholesky() error = %v, want ErrNotPositiveDefinite", err)
+			}
+		})
+	}
+}
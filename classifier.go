@@ -1,44 +1,155 @@
 package pa
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
+// Regressor is a supervised estimator of a continuous target.
+// Samples are the rows of X and targets the rows of y.
+type Regressor[T Number] interface {
+	Fit(X, y *Matrix[T]) (err error)
+	Predict(X *Matrix[T]) (y_hat *Matrix[T], err error)
+	// Score returns the coefficient of determination R² of the predictions for X,
+	// averaged over the columns of y when there are several.
+	Score(X, y_true *Matrix[T]) (float64, error)
+}
+
+// Classifier is a supervised estimator of a discrete label.
+// Samples are the rows of X and labels the elements of the column vector y.
 type Classifier[T Number] interface {
 	Fit(X, y *Matrix[T]) (err error)
 	Predict(X *Matrix[T]) (y_hat *Matrix[T], err error)
+	// PredictProba returns one row of class probabilities per sample, with columns in the order of Classes.
+	PredictProba(X *Matrix[T]) (*Matrix[T], error)
+	// Classes returns the distinct labels seen by Fit, in ascending order.
+	Classes() []T
+	// Score returns the mean accuracy of the predictions for X.
 	Score(X, y_true *Matrix[T]) (float64, error)
 }
 
+// OnlineRegressor is a Regressor that can also learn from a stream of mini-batches.
+type OnlineRegressor[T Number] interface {
+	Regressor[T]
+	// PartialFit updates the model with one mini-batch, keeping what it learned from earlier ones.
+	PartialFit(X, y *Matrix[T]) (err error)
+}
+
+// OnlineClassifier is a Classifier that can also learn from a stream of mini-batches.
+type OnlineClassifier[T Number] interface {
+	Classifier[T]
+	// PartialFit updates the model with one mini-batch, keeping what it learned from earlier ones.
+	// Since a single batch need not contain every label, the first call must list all of them in classes.
+	PartialFit(X, y *Matrix[T], classes []T) (err error)
+}
+
+// Transformer is an unsupervised estimator that maps samples to a new representation.
+type Transformer[T Number] interface {
+	Fit(X *Matrix[T]) (err error)
+	Transform(X *Matrix[T]) (*Matrix[T], error)
+	// FitTransform is equivalent to Fit followed by Transform on the same X.
+	FitTransform(X *Matrix[T]) (*Matrix[T], error)
+}
+
+// Clusterer is an unsupervised estimator that groups samples.
+type Clusterer[T Number] interface {
+	Fit(X *Matrix[T]) (err error)
+	// Predict returns the cluster of each row of X as a column vector.
+	Predict(X *Matrix[T]) (labels *Matrix[T], err error)
+}
+
+var (
+	_ Regressor[float64]   = (*LinearRegression[float64])(nil)
+	_ Regressor[float64]   = (*Ridge[float64])(nil)
+	_ Regressor[float64]   = (*Lasso[float64])(nil)
+	_ Regressor[float64]   = (*ElasticNet[float64])(nil)
+	_ Regressor[float64]   = (*KNeighborsRegressor[float64])(nil)
+	_ Regressor[float64]   = (*DecisionTreeRegressor[float64])(nil)
+	_ Regressor[float64]   = (*RandomForestRegressor[float64])(nil)
+	_ Regressor[float64]   = (*ExtraTreesRegressor[float64])(nil)
+	_ Regressor[float64]   = (*GradientBoostingRegressor[float64])(nil)
+	_ Regressor[float64]   = (*GLM[float64])(nil)
+	_ Classifier[float64]  = (*LogisticRegression[float64])(nil)
+	_ Classifier[float64]  = (*SVC[float64])(nil)
+	_ Classifier[float64]  = (*LinearSVC[float64])(nil)
+	_ Classifier[float64]  = (*KNeighborsClassifier[float64])(nil)
+	_ Classifier[float64]  = (*DecisionTreeClassifier[float64])(nil)
+	_ Classifier[float64]  = (*RandomForestClassifier[float64])(nil)
+	_ Classifier[float64]  = (*ExtraTreesClassifier[float64])(nil)
+	_ Classifier[float64]  = (*GradientBoostingClassifier[float64])(nil)
+	_ Transformer[float64] = (*StandardScaler[float64])(nil)
+
+	_ OnlineRegressor[float64]  = (*PassiveAggressiveRegressor[float64])(nil)
+	_ OnlineClassifier[float64] = (*PassiveAggressiveClassifier[float64])(nil)
+	_ OnlineRegressor[float64]  = (*SGDRegressor[float64])(nil)
+	_ OnlineClassifier[float64] = (*SGDClassifier[float64])(nil)
+	_ OnlineClassifier[float64] = (*GaussianNB[float64])(nil)
+	_ OnlineClassifier[float64] = (*MultinomialNB[float64])(nil)
+	_ OnlineClassifier[float64] = (*BernoulliNB[float64])(nil)
+	_ OnlineClassifier[float64] = (*CategoricalNB[float64])(nil)
+	_ OnlineRegressor[float64]  = (*MLPRegressor[float64])(nil)
+	_ OnlineClassifier[float64] = (*MLPClassifier[float64])(nil)
+)
+
+// Solver selects how a linear model solves for its coefficients.
+type Solver int
+
+const (
+	// SolveCholesky solves the normal equations XᵀXb = Xᵀy by Cholesky factorization.
+	// It is the fastest solver, but squares the condition number of X.
+	SolveCholesky Solver = iota
+	// SolveQR solves the least squares problem from a QR decomposition of X, without forming XᵀX.
+	// Prefer it when features are close to collinear.
+	SolveQR
+	// SolveSVD computes the minimum-norm least squares solution from the pseudoinverse of X.
+	// It is the slowest solver, but the only one that accepts rank-deficient X.
+	SolveSVD
+)
+
+// Penalty selects the regularization term of a linear model.
+type Penalty int
+
+const (
+	// PenaltyL2 adds Alpha‖w‖²/2, shrinking all weights smoothly towards zero.
+	PenaltyL2 Penalty = iota
+	// PenaltyL1 adds Alpha‖w‖₁, driving uninformative weights to exactly zero.
+	PenaltyL1
+	// PenaltyElasticNet adds Alpha·L1Ratio‖w‖₁ + Alpha·(1 - L1Ratio)‖w‖²/2, mixing the other two.
+	PenaltyElasticNet
+)
+
 type LinearRegression[T Number] struct {
+	// Solver is the method used by Fit. The zero value is SolveCholesky.
+	Solver Solver
+
 	bhat *Matrix[T]
+	// fit keeps what Summary needs of the data, so it can compute the summary on first use
+	fit        *linearFit[T]
+	summary    *RegressionSummary
+	summaryErr error
 }
 
 func (lr *LinearRegression[T]) Fit(X, y *Matrix[T]) (err error) {
-	data := make([][]T, len(X.data))
-	for i, row := range X.data {
-		data[i] = append([]T{1}, row...)
-	}
-	X = NewMatrix(data, nil)
-	inner := X.T().Mul(X)
-	if inner.Err() != nil {
-		return err
-	}
-	factored, err := Factor(inner)
+	Xi := withIntercept(X)
+	var factor factored[T]
+	lr.bhat, factor, err = solveLinearFactor(Xi, y, 0, lr.Solver)
+	lr.fit, lr.summary, lr.summaryErr = nil, nil, nil
 	if err != nil {
 		return err
 	}
-	lr.bhat = factored.Inverse(inner).Mul(X.T().Mul(y))
-	if lr.bhat.Err() != nil {
-		return err
+	// a fit whose statistics are undefined, such as a rank-deficient one by SolveSVD, is still a fit;
+	// Summary reports why instead
+	if y.cols != 1 {
+		lr.summaryErr = fmt.Errorf("need a single target column, got %d", y.cols)
+		return nil
 	}
+	lr.fit = newLinearFit(Xi, y, lr.bhat, factor, X.columns)
 	return nil
 }
 
 func (lr *LinearRegression[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
-	data := make([][]T, len(X.data))
-	for i, row := range X.data {
-		data[i] = append([]T{1}, row...)
-	}
-	X = NewMatrix(data, nil)
+	X = withIntercept(X)
 	yh := X.Mul(lr.bhat)
 	if yh.Err() != nil {
 		return nil, yh.Err()
@@ -50,16 +161,128 @@ func (lr *LinearRegression[T]) Coefficients() *Matrix[T] {
 	return lr.bhat
 }
 
+// Summary returns the standard errors, tests and fit diagnostics of the coefficients found by Fit.
+// It is computed on the first call after each Fit, reusing the factorization of XᵀX made by SolveCholesky.
+// It fails if the model is not fitted, y had several columns, or the coefficients are not identified,
+// that is when there are no more samples than coefficients or the features are collinear.
+func (lr *LinearRegression[T]) Summary() (*RegressionSummary, error) {
+	if lr.bhat == nil {
+		return nil, errors.New("LinearRegression.Summary: model is not fitted")
+	}
+	if lr.summary == nil && lr.summaryErr == nil {
+		lr.summary, lr.summaryErr = lr.fit.summarize(lr.bhat)
+	}
+	if lr.summaryErr != nil {
+		return nil, fmt.Errorf("LinearRegression.Summary: %w", lr.summaryErr)
+	}
+	return lr.summary, nil
+}
+
 func (lr *LinearRegression[T]) Score(X, y *Matrix[T]) (float64, error) {
 	yh, err := lr.Predict(X)
 	if err != nil {
 		return -1, err
 	}
-	ybar := y.Mean(Column)
-	yc, yr := y.Size()
-	ybar = Filled(yc, yr, ybar.data[0][0])
-	fmt.Println(ybar)
-	rss := yh.Sub(ybar).Apply(func(t T) T { return t * t }).Sum(Column)
-	tss := y.Sub(ybar).Apply(func(t T) T { return t * t }).Sum(Column)
-	return float64(1 - (rss.data[0][0] / tss.data[0][0])), nil
+	return r2Score(y, yh), nil
+}
+
+// r2Score returns the coefficient of determination of the predictions yh of y.
+// When y has several columns it is the mean of their coefficients, each column weighted equally.
+func r2Score[T Number](y, yh *Matrix[T]) float64 {
+	n, k := y.Size()
+	var total float64
+	for c := 0; c < k; c++ {
+		var ybar float64
+		for i := 0; i < n; i++ {
+			ybar += float64(y.at(i, c))
+		}
+		ybar /= float64(n)
+		var rss, tss float64
+		for i := 0; i < n; i++ {
+			r, d := float64(y.at(i, c))-float64(yh.at(i, c)), float64(y.at(i, c))-ybar
+			rss += r * r
+			tss += d * d
+		}
+		total += 1 - rss/tss
+	}
+	return total / float64(k)
+}
+
+// solveLinear returns b minimising ‖Xb - y‖² + alpha‖b₁‖², where X carries a leading intercept column
+// and b₁ is b without the intercept, so the intercept is never penalised.
+func solveLinear[T Number](X, y *Matrix[T], alpha T, solver Solver) (*Matrix[T], error) {
+	bhat, _, err := solveLinearFactor(X, y, alpha, solver)
+	return bhat, err
+}
+
+// solveLinearFactor is solveLinear, also returning the Cholesky factorization of XᵀX + alpha·I₁
+// when solver is SolveCholesky, and nil for the other solvers.
+func solveLinearFactor[T Number](X, y *Matrix[T], alpha T, solver Solver) (*Matrix[T], factored[T], error) {
+	if X.Err() != nil {
+		return nil, nil, X.Err()
+	}
+	var bhat *Matrix[T]
+	var factor factored[T]
+	switch solver {
+	case SolveQR, SolveSVD:
+		if alpha != 0 {
+			// the penalty is the residual of the extra rows √α·I₁b = 0
+			X, y = ridgeAugment(X, y, alpha)
+		}
+		if solver == SolveQR {
+			bhat = LeastSquares(X, y)
+		} else {
+			bhat = X.Pinv().Mul(y)
+		}
+	case SolveCholesky:
+		inner := X.T().Mul(X)
+		if inner.Err() != nil {
+			return nil, nil, inner.Err()
+		}
+		for i := 1; i < inner.rows; i++ {
+			inner.set(i, i, inner.at(i, i)+alpha)
+		}
+		// XᵀX is symmetric positive-definite whenever X has full column rank,
+		// so solve the normal equations by Cholesky rather than inverting XᵀX.
+		var err error
+		if factor, err = FactorCholesky(inner); err != nil {
+			return nil, nil, err
+		}
+		bhat = factor.Solve(X.T().Mul(y))
+	default:
+		return nil, nil, fmt.Errorf("unknown solver %d", solver)
+	}
+	if bhat.Err() != nil {
+		return nil, nil, bhat.Err()
+	}
+	return bhat, factor, nil
+}
+
+// ridgeAugment appends √alpha times the identity, less its intercept row, beneath X and zeros beneath y.
+func ridgeAugment[T Number](X, y *Matrix[T], alpha T) (*Matrix[T], *Matrix[T]) {
+	r, c := X.Size()
+	root := T(math.Sqrt(float64(alpha)))
+	xa, ya := Empty[T](r+c-1, c), Empty[T](r+c-1, y.cols)
+	for i := 0; i < r; i++ {
+		copy(xa.row(i), X.row(i))
+		copy(ya.row(i), y.row(i))
+	}
+	for j := 1; j < c; j++ {
+		xa.set(r+j-1, j, root)
+	}
+	return xa, ya
+}
+
+// withIntercept returns a copy of X with a leading column of ones, or X's error if it has one.
+func withIntercept[T Number](X *Matrix[T]) *Matrix[T] {
+	if X.err != nil {
+		return errored[T](X.err)
+	}
+	r, c := X.Size()
+	data := make([]T, 0, r*(c+1))
+	for i := 0; i < r; i++ {
+		data = append(data, 1)
+		data = append(data, X.row(i)...)
+	}
+	return newMatrix(r, c+1, data, nil)
 }
@@ -1 +1,68 @@
 package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLinearRegression(t *testing.T) {
+	// y = 1 + 2a - 3b exactly
+	X := NewMatrix([][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {2, 3}, {4, 1}}, nil)
+	y := NewMatrix([][]float64{{1}, {3}, {-2}, {0}, {-4}, {6}}, nil)
+	want := NewMatrix([][]float64{{1}, {2}, {-3}}, nil)
+
+	lr := new(LinearRegression[float64])
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if got := lr.Coefficients(); !approxEqual(got, want, 1e-9) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", got, want)
+	}
+	r2, err := lr.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if math.Abs(r2-1) > 1e-9 {
+		t.Errorf("Score() = %v, want 1", r2)
+	}
+}
+
+func TestLinearRegressionQR(t *testing.T) {
+	// the second feature differs from the first by at most 1e-6, so XᵀX is numerically singular
+	var rows, ys [][]float64
+	for i := 0; i < 20; i++ {
+		a := float64(i)
+		b := a + 1e-6*math.Sin(float64(i*i))
+		rows = append(rows, []float64{a, b})
+		ys = append(ys, []float64{2 + 3*a - 4*b})
+	}
+	X, y := NewMatrix(rows, nil), NewMatrix(ys, nil)
+	want := NewMatrix([][]float64{{2}, {3}, {-4}}, nil)
+
+	lr := &LinearRegression[float64]{Solver: SolveQR}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if got := lr.Coefficients(); !approxEqual(got, want, 1e-6) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLinearRegressionSVD(t *testing.T) {
+	// the two features are identical, so only their sum is identified;
+	// the minimum-norm solution splits the weight evenly between them
+	X := NewMatrix([][]float64{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, nil)
+	y := NewMatrix([][]float64{{1}, {5}, {9}, {13}}, nil)
+	want := NewMatrix([][]float64{{1}, {2}, {2}}, nil)
+
+	lr := &LinearRegression[float64]{Solver: SolveSVD}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if got := lr.Coefficients(); !approxEqual(got, want, 1e-9) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", got, want)
+	}
+	if err := new(LinearRegression[float64]).Fit(X, y); err == nil {
+		t.Error("Fit() with SolveCholesky on rank-deficient X returned nil error")
+	}
+}
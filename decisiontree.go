@@ -0,0 +1,183 @@
+package pa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DecisionTreeClassifier predicts the most common class among the training samples in the leaf
+// of a CART decision tree that a sample falls into.
+type DecisionTreeClassifier[T Number] struct {
+	// Criterion is the impurity splits minimize, CriterionGini or CriterionEntropy.
+	// The zero value is CriterionAuto, which uses CriterionGini.
+	Criterion Criterion
+	// MaxDepth limits the depth of the tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of minimal cost-complexity pruning: after growing, every subtree
+	// that reduces the weighted impurity by at most CCPAlpha per leaf it adds is collapsed. If zero, the tree is not pruned.
+	CCPAlpha float64
+
+	cart
+	classes []T
+}
+
+// Fit grows the tree on the samples in the rows of X, whose labels are in the column vector y.
+func (dt *DecisionTreeClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if X.rows == 0 {
+		return fmt.Errorf("DecisionTreeClassifier.Fit: X has no samples")
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return fmt.Errorf("DecisionTreeClassifier.Fit: y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	criterion := dt.Criterion
+	if criterion == CriterionAuto {
+		criterion = CriterionGini
+	}
+	if criterion != CriterionGini && criterion != CriterionEntropy {
+		return fmt.Errorf("DecisionTreeClassifier.Fit: unsupported criterion %d", dt.Criterion)
+	}
+	cfg, err := newCARTConfig(criterion, dt.MaxDepth, dt.MinSamplesSplit, dt.MinSamplesLeaf, dt.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("DecisionTreeClassifier.Fit: %w", err)
+	}
+	classes, labels := encodeLabels(y)
+	targets := Empty[float64](len(labels), len(classes))
+	for i, c := range labels {
+		targets.set(i, c, 1)
+	}
+	dt.classes = classes
+	dt.build(convert[float64](X), targets, nil, cfg)
+	return nil
+}
+
+// PredictProba returns the fraction of the training samples in each class in the leaf each row of X falls into,
+// with columns in the order of Classes.
+func (dt *DecisionTreeClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	proba, err := dt.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("DecisionTreeClassifier.PredictProba: %w", err)
+	}
+	return convert[T](proba), nil
+}
+
+// Predict returns the most common class in the leaf each row of X falls into, breaking ties in favour of the smaller class.
+func (dt *DecisionTreeClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	proba, err := dt.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("DecisionTreeClassifier.Predict: %w", err)
+	}
+	yh := Empty[T](proba.rows, 1)
+	for i := 0; i < proba.rows; i++ {
+		yh.set(i, 0, dt.classes[argmax(proba.row(i))])
+	}
+	return yh, nil
+}
+
+func (dt *DecisionTreeClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := dt.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (dt *DecisionTreeClassifier[T]) Classes() []T {
+	return dt.classes
+}
+
+// ExportText renders the tree as indented rules, one line per branch, with the predicted class at each leaf.
+// Features are named after the columns of the training data, or x[j] if it had none.
+func (dt *DecisionTreeClassifier[T]) ExportText() string {
+	return dt.exportText(func(value []float64) string {
+		return fmt.Sprintf("class: %v", dt.classes[argmax(value)])
+	})
+}
+
+// DecisionTreeRegressor predicts the mean target of the training samples in the leaf
+// of a CART decision tree that a sample falls into. y may have several columns.
+type DecisionTreeRegressor[T Number] struct {
+	// Criterion is the impurity splits minimize. Only CriterionMSE is supported,
+	// and the zero value CriterionAuto selects it.
+	Criterion Criterion
+	// MaxDepth limits the depth of the tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of minimal cost-complexity pruning: after growing, every subtree
+	// that reduces the weighted impurity by at most CCPAlpha per leaf it adds is collapsed. If zero, the tree is not pruned.
+	CCPAlpha float64
+
+	cart
+}
+
+// Fit grows the tree on the samples in the rows of X, whose targets are the rows of y.
+func (dt *DecisionTreeRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if X.rows == 0 {
+		return fmt.Errorf("DecisionTreeRegressor.Fit: X has no samples")
+	}
+	if y.rows != X.rows {
+		return fmt.Errorf("DecisionTreeRegressor.Fit: y has %d rows, X has %d", y.rows, X.rows)
+	}
+	if dt.Criterion != CriterionAuto && dt.Criterion != CriterionMSE {
+		return fmt.Errorf("DecisionTreeRegressor.Fit: unsupported criterion %d", dt.Criterion)
+	}
+	cfg, err := newCARTConfig(CriterionMSE, dt.MaxDepth, dt.MinSamplesSplit, dt.MinSamplesLeaf, dt.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("DecisionTreeRegressor.Fit: %w", err)
+	}
+	dt.build(convert[float64](X), convert[float64](y), nil, cfg)
+	return nil
+}
+
+// Predict returns the mean targets of the training samples in the leaf each row of X falls into.
+func (dt *DecisionTreeRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	means, err := dt.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("DecisionTreeRegressor.Predict: %w", err)
+	}
+	yh := Empty[T](means.rows, means.cols)
+	for i := 0; i < means.rows; i++ {
+		for c, v := range means.row(i) {
+			yh.set(i, c, fromFloat[T](v))
+		}
+	}
+	return yh, nil
+}
+
+func (dt *DecisionTreeRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := dt.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// ExportText renders the tree as indented rules, one line per branch, with the predicted targets at each leaf.
+// Features are named after the columns of the training data, or x[j] if it had none.
+func (dt *DecisionTreeRegressor[T]) ExportText() string {
+	return dt.exportText(func(value []float64) string {
+		vs := make([]string, len(value))
+		for c, v := range value {
+			vs[c] = fmt.Sprintf("%g", v)
+		}
+		return "value: " + strings.Join(vs, ", ")
+	})
+}
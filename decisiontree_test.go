@@ -0,0 +1,164 @@
+package pa
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDecisionTreeClassifier(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {5, 0}, {0, 5}}, 30)
+	tests := []struct {
+		name string
+		dt   *DecisionTreeClassifier[float64]
+		min  float64
+	}{
+		{"default", &DecisionTreeClassifier[float64]{}, 1},
+		{"entropy", &DecisionTreeClassifier[float64]{Criterion: CriterionEntropy}, 1},
+		{"shallow", &DecisionTreeClassifier[float64]{MaxDepth: 2}, 0.9},
+		{"large leaves", &DecisionTreeClassifier[float64]{MinSamplesLeaf: 10}, 0.9},
+		{"pruned", &DecisionTreeClassifier[float64]{CCPAlpha: 0.01}, 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dt.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := tt.dt.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < tt.min {
+				t.Errorf("Score() = %v, want at least %v", acc, tt.min)
+			}
+			if tt.dt.MaxDepth > 0 && tt.dt.Depth() > tt.dt.MaxDepth {
+				t.Errorf("Depth() = %d, want at most %d", tt.dt.Depth(), tt.dt.MaxDepth)
+			}
+			proba, err := tt.dt.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+			tt.dt.Walk(func(n TreeNode) bool {
+				if n.Leaf() && n.Samples < tt.dt.MinSamplesLeaf {
+					t.Errorf("leaf %d has %d samples, want at least %d", n.ID, n.Samples, tt.dt.MinSamplesLeaf)
+				}
+				return true
+			})
+			if s := sum(tt.dt.FeatureImportances()); math.Abs(s-1) > 1e-12 {
+				t.Errorf("FeatureImportances() sum to %v, want 1", s)
+			}
+		})
+	}
+
+	if err := (&DecisionTreeClassifier[float64]{Criterion: CriterionMSE}).Fit(X, y); err == nil {
+		t.Error("Fit() with CriterionMSE returned nil error")
+	}
+	if _, err := (&DecisionTreeClassifier[float64]{}).Predict(X); err == nil {
+		t.Error("Predict() before Fit() returned nil error")
+	}
+}
+
+func TestDecisionTreeXOR(t *testing.T) {
+	// no single split reduces the impurity of XOR, but two levels separate it
+	X := NewMatrix([][]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}}, []string{"a", "b"})
+	y := NewMatrix([][]int{{0}, {1}, {1}, {0}}, nil)
+	dt := &DecisionTreeClassifier[int]{}
+	if err := dt.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if acc, _ := dt.Score(X, y); acc != 1 {
+		t.Errorf("Score() = %v, want 1", acc)
+	}
+	if dt.Depth() != 2 || dt.Leaves() != 4 {
+		t.Errorf("Depth() = %d and Leaves() = %d, want 2 and 4", dt.Depth(), dt.Leaves())
+	}
+	want := strings.Join([]string{
+		"|--- a <= 0.5",
+		"|   |--- b <= 0.5",
+		"|   |   |--- class: 0",
+		"|   |--- b >  0.5",
+		"|   |   |--- class: 1",
+		"|--- a >  0.5",
+		"|   |--- b <= 0.5",
+		"|   |   |--- class: 1",
+		"|   |--- b >  0.5",
+		"|   |   |--- class: 0",
+	}, "\n") + "\n"
+	if got := dt.ExportText(); got != want {
+		t.Errorf("ExportText() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Walk visits parents before children and can skip subtrees
+	var ids []int
+	dt.Walk(func(n TreeNode) bool {
+		ids = append(ids, n.ID)
+		return n.Depth < 1
+	})
+	if len(ids) != 3 || ids[0] != 0 {
+		t.Errorf("Walk() stopping at depth 1 visited %v, want the root and its two children", ids)
+	}
+}
+
+func TestDecisionTreeRegressor(t *testing.T) {
+	// a step function of the first feature; the second feature is noise
+	var rows, ys [][]float64
+	for i := 0; i < 40; i++ {
+		x := float64(i)
+		rows = append(rows, []float64{x, float64((i * 7) % 5)})
+		step := 0.0
+		if i >= 20 {
+			step = 10
+		}
+		ys = append(ys, []float64{step, -step})
+	}
+	X, y := NewMatrix(rows, nil), NewMatrix(ys, nil)
+	dt := &DecisionTreeRegressor[float64]{}
+	if err := dt.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if dt.Leaves() != 2 {
+		t.Errorf("Leaves() = %d, want 2", dt.Leaves())
+	}
+	yh, err := dt.Predict(NewMatrix([][]float64{{3, 0}, {30, 0}}, nil))
+	if err != nil {
+		t.Fatalf("Predict() error: %v", err)
+	}
+	if want := NewMatrix([][]float64{{0, 0}, {10, -10}}, nil); !approxEqual(yh, want, 1e-12) {
+		t.Errorf("Predict() = %v, want %v", yh, want)
+	}
+	if imp := dt.FeatureImportances(); imp[0] != 1 || imp[1] != 0 {
+		t.Errorf("FeatureImportances() = %v, want [1 0]", imp)
+	}
+	if got, want := dt.ExportText(), "|--- x[0] <= 19.5\n|   |--- value: 0, 0\n|--- x[0] >  19.5\n|   |--- value: 10, -10\n"; got != want {
+		t.Errorf("ExportText() = %q, want %q", got, want)
+	}
+
+	// pruning trades leaves for impurity on a noisy curve
+	noisy := make([][]float64, 40)
+	for i := range noisy {
+		noisy[i] = []float64{math.Sin(float64(i)/4) + 0.1*math.Sin(float64(i*i))}
+	}
+	yn := NewMatrix(noisy, nil)
+	full, pruned := &DecisionTreeRegressor[float64]{}, &DecisionTreeRegressor[float64]{CCPAlpha: 0.01}
+	if err := full.Fit(X, yn); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if err := pruned.Fit(X, yn); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if pruned.Leaves() >= full.Leaves() {
+		t.Errorf("pruned tree has %d leaves, unpruned %d", pruned.Leaves(), full.Leaves())
+	}
+	if r2, _ := full.Score(X, yn); r2 != 1 {
+		t.Errorf("unpruned Score() on the training data = %v, want 1", r2)
+	}
+
+	if err := (&DecisionTreeRegressor[float64]{Criterion: CriterionGini}).Fit(X, y); err == nil {
+		t.Error("Fit() with CriterionGini returned nil error")
+	}
+}
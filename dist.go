@@ -0,0 +1,96 @@
+package pa
+
+import "math"
+
+// normalSF returns P(Z > z) for a standard normal Z.
+func normalSF(z float64) float64 {
+	return math.Erfc(z/math.Sqrt2) / 2
+}
+
+// studentTSF returns P(T > t) for T following Student's t distribution with df degrees of freedom.
+func studentTSF(t, df float64) float64 {
+	tail := regIncBeta(df/2, 0.5, df/(df+t*t)) / 2
+	if t < 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTISF returns the t with P(T > t) = p for T following Student's t distribution with df degrees of freedom.
+func studentTISF(p, df float64) float64 {
+	if p > 0.5 {
+		return -studentTISF(1-p, df)
+	}
+	// the tail is decreasing in t, so bracket the quantile and bisect
+	lo, hi := 0.0, 1.0
+	for studentTSF(hi, df) > p && hi < 1e300 {
+		lo, hi = hi, 2*hi
+	}
+	for i := 0; i < 200 && hi-lo > 1e-14*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentTSF(mid, df) > p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// fisherSF returns P(F > f) for F following the F distribution with d1 and d2 degrees of freedom.
+func fisherSF(f, d1, d2 float64) float64 {
+	if f <= 0 {
+		return 1
+	}
+	return regIncBeta(d2/2, d1/2, d2/(d2+d1*f))
+}
+
+// regIncBeta returns the regularized incomplete beta function I_x(a, b).
+func regIncBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+	// the continued fraction converges quickly only on this side of the mode; use the symmetry otherwise
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction for the incomplete beta function by the modified Lentz method.
+func betaFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	clamp := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+	c, d := 1.0, 1/clamp(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		// even step
+		aa := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 / clamp(1+aa*d)
+		c = clamp(1 + aa/c)
+		h *= d * c
+		// odd step
+		aa = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 / clamp(1+aa*d)
+		c = clamp(1 + aa/c)
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,47 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistributions(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"normal tail", normalSF(1.959964), 0.025},
+		{"normal median", normalSF(0), 0.5},
+		{"beta", regIncBeta(2, 3, 0.4), 0.5248},
+		{"beta lower edge", regIncBeta(2, 3, 0), 0},
+		{"beta upper edge", regIncBeta(2, 3, 1), 1},
+		{"beta symmetry", regIncBeta(3, 2, 0.6), 1 - 0.5248},
+		{"t tail", studentTSF(2.228139, 10), 0.025},
+		{"t lower tail", studentTSF(-2.228139, 10), 0.975},
+		{"t cauchy", studentTSF(1, 1), 0.25},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDistributionQuantiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"t quantile", studentTISF(0.025, 18), 2.100922},
+		{"t lower quantile", studentTISF(0.975, 18), -2.100922},
+		{"t median", studentTISF(0.5, 5), 0},
+		{"cauchy quantile", studentTISF(0.25, 1), 1},
+		{"F tail", fisherSF(4.413873, 1, 18), 0.05},
+		{"F at zero", fisherSF(0, 3, 10), 1},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
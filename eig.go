@@ -0,0 +1,379 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// EigSym returns the eigenvalues and eigenvectors of the symmetric matrix m, computed by cyclic Jacobi rotations.
+// values is 1 x n with the eigenvalues in descending order, and the ith column of vectors is the unit eigenvector
+// belonging to the ith eigenvalue. If m is not square or not symmetric, the error is available from Err() on both results.
+func EigSym[T Float](m *Matrix[T]) (values, vectors *Matrix[T]) {
+	if m.err != nil {
+		return m, m
+	}
+	if !m.Square() {
+		err := fmt.Errorf("EigSym: eigendecomposition of a non-square (%d x %d) matrix is undefined", m.rows, m.cols)
+		return errored[T](err), errored[T](err)
+	}
+	n := m.rows
+	eps := epsilon[T]()
+	tol := eps * float64(n) * float64(maxAbs(m))
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(float64(m.at(i, j)-m.at(j, i))) > tol {
+				err := fmt.Errorf("EigSym: matrix is not symmetric: element (%d, %d) differs from (%d, %d)", i, j, j, i)
+				return errored[T](err), errored[T](err)
+			}
+		}
+	}
+
+	a := m.Clone()
+	vt := NewIdentity[T](n)
+	var norm float64
+	for i := 0; i < n; i++ {
+		norm += float64(sumSquares(a.row(i)))
+	}
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		var off float64
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += float64(a.at(p, q)) * float64(a.at(p, q))
+			}
+		}
+		if off <= eps*eps*norm {
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := float64(a.at(p, q))
+				if apq == 0 {
+					continue
+				}
+				// choose the rotation that annihilates a_pq in JᵀAJ
+				theta := float64(a.at(q, q)-a.at(p, p)) / (2 * apq)
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				cs, sn := T(c), T(t*c)
+				rotate(a.row(p), a.row(q), cs, sn)
+				for k := 0; k < n; k++ {
+					row := a.row(k)
+					akp, akq := row[p], row[q]
+					row[p] = cs*akp - sn*akq
+					row[q] = sn*akp + cs*akq
+				}
+				a.set(p, q, 0)
+				a.set(q, p, 0)
+				rotate(vt.row(p), vt.row(q), cs, sn)
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return a.at(order[i], order[i]) > a.at(order[j], order[j]) })
+	values, vectors = Empty[T](1, n), Empty[T](n, n)
+	for i, k := range order {
+		values.set(0, i, a.at(k, k))
+		for j, v := range vt.row(k) {
+			vectors.set(j, i, v)
+		}
+	}
+	return values, vectors
+}
+
+// ErrNoConvergence is returned when an iterative decomposition fails to converge.
+var ErrNoConvergence = errors.New("iteration did not converge")
+
+// Eig returns the eigenvalues of the square matrix m, which need not be symmetric.
+// m is reduced to upper Hessenberg form by Householder reflections, and the eigenvalues are found by
+// Francis double-shift QR iteration. Complex eigenvalues of a real matrix come in conjugate pairs;
+// the result is ordered by descending modulus, with the member of each pair with positive imaginary part first.
+func Eig[T Float](m *Matrix[T]) ([]complex128, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if !m.Square() {
+		return nil, fmt.Errorf("Eig: eigendecomposition of a non-square (%d x %d) matrix is undefined", m.rows, m.cols)
+	}
+	hm := convert[float64](m)
+	h := make([][]float64, hm.rows)
+	for i := range h {
+		h[i] = hm.row(i)
+	}
+	hessenberg(h)
+	values, err := hqr(h)
+	if err != nil {
+		return nil, fmt.Errorf("Eig: %w", err)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if ma, mb := cmplx.Abs(a), cmplx.Abs(b); ma != mb {
+			return ma > mb
+		}
+		if real(a) != real(b) {
+			return real(a) > real(b)
+		}
+		return imag(a) > imag(b)
+	})
+	return values, nil
+}
+
+// hessenberg reduces h to upper Hessenberg form in place by Householder similarity transformations.
+func hessenberg(h [][]float64) {
+	n := len(h)
+	ort := make([]float64, n)
+	high := n - 1
+	for m := 1; m <= high-1; m++ {
+		var scale float64
+		for i := m; i <= high; i++ {
+			scale += math.Abs(h[i][m-1])
+		}
+		if scale == 0 {
+			continue
+		}
+		var hh float64
+		for i := high; i >= m; i-- {
+			ort[i] = h[i][m-1] / scale
+			hh += ort[i] * ort[i]
+		}
+		g := math.Sqrt(hh)
+		if ort[m] > 0 {
+			g = -g
+		}
+		hh -= ort[m] * g
+		ort[m] -= g
+
+		// apply I - uuᵀ/h from the left, then from the right
+		for j := m; j < n; j++ {
+			var f float64
+			for i := high; i >= m; i-- {
+				f += ort[i] * h[i][j]
+			}
+			f /= hh
+			for i := m; i <= high; i++ {
+				h[i][j] -= f * ort[i]
+			}
+		}
+		for i := 0; i <= high; i++ {
+			var f float64
+			for j := high; j >= m; j-- {
+				f += ort[j] * h[i][j]
+			}
+			f /= hh
+			for j := m; j <= high; j++ {
+				h[i][j] -= f * ort[j]
+			}
+		}
+		ort[m] *= scale
+		h[m][m-1] = scale * g
+	}
+}
+
+// hqr returns the eigenvalues of the upper Hessenberg matrix h, destroying h.
+// It follows the EISPACK routine hqr, deflating one real root or a pair of roots at a time
+// from the bottom of the matrix.
+func hqr(h [][]float64) ([]complex128, error) {
+	nn := len(h)
+	d, e := make([]float64, nn), make([]float64, nn)
+	eps := epsilon[float64]()
+	var exshift, p, q, r, s, z, w, x, y float64
+
+	var norm float64
+	for i := 0; i < nn; i++ {
+		for j := i - 1; j < nn; j++ {
+			if j >= 0 {
+				norm += math.Abs(h[i][j])
+			}
+		}
+	}
+
+	n, iter, total := nn-1, 0, 0
+	for n >= 0 {
+		// look for a single small sub-diagonal element
+		l := n
+		for l > 0 {
+			s = math.Abs(h[l-1][l-1]) + math.Abs(h[l][l])
+			if s == 0 {
+				s = norm
+			}
+			if math.Abs(h[l][l-1]) < eps*s {
+				break
+			}
+			l--
+		}
+
+		switch {
+		case l == n:
+			// one real root
+			d[n], e[n] = h[n][n]+exshift, 0
+			n--
+			iter = 0
+		case l == n-1:
+			// two roots, real or a complex conjugate pair
+			w = h[n][n-1] * h[n-1][n]
+			p = (h[n-1][n-1] - h[n][n]) / 2
+			q = p*p + w
+			z = math.Sqrt(math.Abs(q))
+			x = h[n][n] + exshift
+			if q >= 0 {
+				if p >= 0 {
+					z = p + z
+				} else {
+					z = p - z
+				}
+				d[n-1] = x + z
+				d[n] = d[n-1]
+				if z != 0 {
+					d[n] = x - w/z
+				}
+				e[n-1], e[n] = 0, 0
+			} else {
+				d[n-1], d[n] = x+p, x+p
+				e[n-1], e[n] = z, -z
+			}
+			n -= 2
+			iter = 0
+		default:
+			if total > 30*nn {
+				return nil, ErrNoConvergence
+			}
+			x = h[n][n]
+			y, w = 0, 0
+			if l < n {
+				y = h[n-1][n-1]
+				w = h[n][n-1] * h[n-1][n]
+			}
+			// exceptional shifts break the cycles the standard shift can fall into
+			if iter == 10 {
+				exshift += x
+				for i := 0; i <= n; i++ {
+					h[i][i] -= x
+				}
+				s = math.Abs(h[n][n-1]) + math.Abs(h[n-1][n-2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+			if iter == 30 {
+				s = (y - x) / 2
+				s = s*s + w
+				if s > 0 {
+					s = math.Sqrt(s)
+					if y < x {
+						s = -s
+					}
+					s = x - w/((y-x)/2+s)
+					for i := 0; i <= n; i++ {
+						h[i][i] -= s
+					}
+					exshift += s
+					x, y, w = 0.964, 0.964, 0.964
+				}
+			}
+			iter++
+			total++
+
+			// look for two consecutive small sub-diagonal elements
+			m := n - 2
+			for m >= l {
+				z = h[m][m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/h[m+1][m] + h[m][m+1]
+				q = h[m+1][m+1] - z - r - s
+				r = h[m+2][m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				if math.Abs(h[m][m-1])*(math.Abs(q)+math.Abs(r)) <
+					eps*(math.Abs(p)*(math.Abs(h[m-1][m-1])+math.Abs(z)+math.Abs(h[m+1][m+1]))) {
+					break
+				}
+				m--
+			}
+			for i := m + 2; i <= n; i++ {
+				h[i][i-2] = 0
+				if i > m+2 {
+					h[i][i-3] = 0
+				}
+			}
+
+			// double QR step on rows l through n and columns m through n
+			for k := m; k <= n-1; k++ {
+				notlast := k != n-1
+				if k != m {
+					p = h[k][k-1]
+					q = h[k+1][k-1]
+					r = 0
+					if notlast {
+						r = h[k+2][k-1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x == 0 {
+						continue
+					}
+					p /= x
+					q /= x
+					r /= x
+				}
+				s = math.Sqrt(p*p + q*q + r*r)
+				if p < 0 {
+					s = -s
+				}
+				if s == 0 {
+					continue
+				}
+				if k != m {
+					h[k][k-1] = -s * x
+				} else if l != m {
+					h[k][k-1] = -h[k][k-1]
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+
+				for j := k; j < nn; j++ {
+					p = h[k][j] + q*h[k+1][j]
+					if notlast {
+						p += r * h[k+2][j]
+						h[k+2][j] -= p * z
+					}
+					h[k][j] -= p * x
+					h[k+1][j] -= p * y
+				}
+				last := k + 3
+				if n < last {
+					last = n
+				}
+				for i := 0; i <= last; i++ {
+					p = x*h[i][k] + y*h[i][k+1]
+					if notlast {
+						p += z * h[i][k+2]
+						h[i][k+2] -= p * r
+					}
+					h[i][k] -= p
+					h[i][k+1] -= p * q
+				}
+			}
+		}
+	}
+
+	values := make([]complex128, nn)
+	for i := range values {
+		values[i] = complex(d[i], e[i])
+	}
+	return values, nil
+}
@@ -0,0 +1,141 @@
+package pa
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestEigSym(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      *Matrix[float64]
+		values []float64
+	}{
+		{"diagonal", NewMatrix([][]float64{{1, 0}, {0, 3}}, nil), []float64{3, 1}},
+		{"two", NewMatrix([][]float64{{2, 1}, {1, 2}}, nil), []float64{3, 1}},
+		{"tridiagonal", NewMatrix([][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, nil), []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2}},
+		{"indefinite", NewMatrix([][]float64{{1, 2}, {2, 1}}, nil), []float64{3, -1}},
+		{"repeated", NewMatrix([][]float64{{2, 0, 0}, {0, 2, 0}, {0, 0, 5}}, nil), []float64{5, 2, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, vectors := EigSym(tt.m)
+			if values.Err() != nil {
+				t.Fatalf("EigSym() error: %v", values.Err())
+			}
+			want := NewMatrix([][]float64{tt.values}, nil)
+			if !approxEqual(values, want, 1e-9) {
+				t.Errorf("\nvalues:\n%s\nwant:\n%s", values, want)
+			}
+			n := len(tt.values)
+			if got := vectors.T().Mul(vectors); !approxEqual(got, NewIdentity[float64](n), 1e-9) {
+				t.Errorf("\nVᵀV:\n%s\nwant identity", got)
+			}
+			// AV = VΛ
+			lambda := Empty[float64](n, n)
+			for i, v := range tt.values {
+				lambda.set(i, i, v)
+			}
+			if got, want := tt.m.Mul(vectors), vectors.Mul(lambda); !approxEqual(got, want, 1e-9) {
+				t.Errorf("\nAV:\n%s\nwant VΛ:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestEigSymErr(t *testing.T) {
+	if values, vectors := EigSym(NewMatrix([][]float64{{1, 2, 3}}, nil)); values.Err() == nil || vectors.Err() == nil {
+		t.Error("EigSym() of non-square matrix returned nil error")
+	}
+	if values, _ := EigSym(NewMatrix([][]float64{{1, 2}, {3, 4}}, nil)); values.Err() == nil {
+		t.Error("EigSym() of asymmetric matrix returned nil error")
+	}
+}
+
+func TestEig(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      *Matrix[float64]
+		values []complex128
+	}{
+		{"unit", NewMatrix([][]float64{{-3}}, nil), []complex128{-3}},
+		{"rotation", NewMatrix([][]float64{{0, -1}, {1, 0}}, nil), []complex128{1i, -1i}},
+		{"triangular", NewMatrix([][]float64{{1, 2, 3}, {0, 4, 5}, {0, 0, 6}}, nil), []complex128{6, 4, 1}},
+		{"symmetric", NewMatrix([][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, nil), []complex128{2 + math.Sqrt2, 2, 2 - math.Sqrt2}},
+		{
+			// companion matrix of (x - 2)(x² + 2x + 5), with roots 2 and -1 ± 2i
+			name:   "companion",
+			m:      NewMatrix([][]float64{{0, 1, 0}, {0, 0, 1}, {10, -1, 0}}, nil),
+			values: []complex128{-1 + 2i, -1 - 2i, 2},
+		},
+		{
+			// block diagonal with a rotation-scaling block, eigenvalues 3 ± 4i, 5 and -1
+			name: "mixed",
+			m: NewMatrix([][]float64{
+				{3, -4, 0, 0},
+				{4, 3, 0, 0},
+				{0, 0, 2, 3},
+				{0, 0, 3, 2},
+			}, nil),
+			values: []complex128{5, 3 + 4i, 3 - 4i, -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eig(tt.m)
+			if err != nil {
+				t.Fatalf("Eig() error: %v", err)
+			}
+			if len(got) != len(tt.values) {
+				t.Fatalf("Eig() = %v, want %v", got, tt.values)
+			}
+			for i := range got {
+				if cmplx.Abs(got[i]-tt.values[i]) > 1e-9 {
+					t.Errorf("Eig() = %v, want %v", got, tt.values)
+					break
+				}
+			}
+		})
+	}
+	if _, err := Eig(NewMatrix([][]float64{{1, 2}}, nil)); err == nil {
+		t.Error("Eig() of non-square matrix returned nil error")
+	}
+}
+
+func TestEigInvariants(t *testing.T) {
+	// a pseudo-random non-symmetric matrix: the eigenvalues must sum to the trace
+	// and multiply to the determinant
+	n := 8
+	m := Empty[float64](n, n)
+	seed := uint32(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			seed = seed*1664525 + 1013904223
+			m.set(i, j, float64(seed>>8)/float64(1<<24)-0.5)
+		}
+	}
+	values, err := Eig(m)
+	if err != nil {
+		t.Fatalf("Eig() error: %v", err)
+	}
+	var trace float64
+	for i := 0; i < n; i++ {
+		trace += m.at(i, i)
+	}
+	f, err := Factor(m)
+	if err != nil {
+		t.Fatalf("Factor() error: %v", err)
+	}
+	var sum, prod complex128 = 0, 1
+	for _, v := range values {
+		sum += v
+		prod *= v
+	}
+	if cmplx.Abs(sum-complex(trace, 0)) > 1e-9 {
+		t.Errorf("sum of eigenvalues = %v, want trace %v", sum, trace)
+	}
+	if cmplx.Abs(prod-complex(f.Det(), 0)) > 1e-9 {
+		t.Errorf("product of eigenvalues = %v, want determinant %v", prod, f.Det())
+	}
+}
@@ -0,0 +1,517 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// forestConfig is the validated settings of a forest.
+type forestConfig struct {
+	trees     int
+	tree      cartConfig
+	bootstrap bool
+	oob       bool
+	jobs      int
+	seed      int64
+}
+
+// newForestConfig validates the settings shared by the forests, filling in defaults.
+// defaultFeatures is the number of candidate features per split if maxFeatures is zero.
+func newForestConfig(trees int, tree cartConfig, maxFeatures, defaultFeatures int, bootstrap, oob bool, jobs int, seed int64) (forestConfig, error) {
+	cfg := forestConfig{trees: trees, tree: tree, bootstrap: bootstrap, oob: oob, jobs: jobs, seed: seed}
+	if cfg.trees == 0 {
+		cfg.trees = 100
+	}
+	if cfg.trees < 0 {
+		return cfg, fmt.Errorf("NEstimators must not be negative, got %d", trees)
+	}
+	if maxFeatures < 0 {
+		return cfg, fmt.Errorf("MaxFeatures must not be negative, got %d", maxFeatures)
+	}
+	cfg.tree.maxFeatures = maxFeatures
+	if maxFeatures == 0 {
+		cfg.tree.maxFeatures = defaultFeatures
+	}
+	if oob && !bootstrap {
+		return cfg, errors.New("OOB needs bootstrap samples")
+	}
+	if cfg.jobs < 0 {
+		return cfg, fmt.Errorf("Jobs must not be negative, got %d", jobs)
+	}
+	if cfg.jobs == 0 {
+		cfg.jobs = runtime.GOMAXPROCS(0)
+	}
+	return cfg, nil
+}
+
+// forest is an ensemble of carts whose prediction is the mean of theirs.
+type forest struct {
+	name        string
+	trees       []cart
+	p           int
+	importances []float64
+	oob         float64
+	hasOOB      bool
+}
+
+// grow fits the trees on X, whose targets are the rows of targets, spreading them over cfg.jobs goroutines.
+// Each tree draws from its own source, seeded in order from cfg.seed, so the forest does not depend on scheduling.
+// If cfg.oob is set, it returns the mean prediction for each sample of the trees that did not train on it,
+// and which samples had any such tree.
+func (f *forest) grow(X, targets *Matrix[float64], cfg forestConfig) (oob *Matrix[float64], seen []bool) {
+	seeds := make([]int64, cfg.trees)
+	rng := rand.New(rand.NewSource(cfg.seed))
+	for i := range seeds {
+		seeds[i] = rng.Int63()
+	}
+	f.trees, f.p = make([]cart, cfg.trees), X.cols
+	inBag := make([][]bool, cfg.trees)
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for j := 0; j < cfg.jobs; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				tree := cfg.tree
+				tree.rng = rand.New(rand.NewSource(seeds[i]))
+				var idx []int
+				if cfg.bootstrap {
+					idx, inBag[i] = make([]int, X.rows), make([]bool, X.rows)
+					for k := range idx {
+						idx[k] = tree.rng.Intn(X.rows)
+						inBag[i][idx[k]] = true
+					}
+				}
+				f.trees[i].build(X, targets, idx, tree)
+			}
+		}()
+	}
+	for i := range f.trees {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	f.importances = make([]float64, X.cols)
+	for _, t := range f.trees {
+		axpy(1/float64(len(f.trees)), t.importances, f.importances)
+	}
+	if total := sum(f.importances); total > 0 {
+		scale(1/total, f.importances)
+	}
+	if !cfg.oob {
+		return nil, nil
+	}
+	oob, seen = Empty[float64](X.rows, targets.cols), make([]bool, X.rows)
+	counts := make([]float64, X.rows)
+	for i, t := range f.trees {
+		for k := 0; k < X.rows; k++ {
+			if !inBag[i][k] {
+				axpy(1, t.leaf(X.row(k)).value, oob.row(k))
+				counts[k]++
+			}
+		}
+	}
+	for k, n := range counts {
+		if seen[k] = n > 0; seen[k] {
+			scale(1/n, oob.row(k))
+		}
+	}
+	return oob, seen
+}
+
+// predict returns the mean of the trees' predictions for each row of X.
+func (f *forest) predict(X *Matrix[float64]) (*Matrix[float64], error) {
+	if f.trees == nil {
+		return nil, errors.New("model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != f.p {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, f.p)
+	}
+	out := Empty[float64](X.rows, len(f.trees[0].nodes[0].value))
+	w := 1 / float64(len(f.trees))
+	for i := 0; i < X.rows; i++ {
+		row := out.row(i)
+		for _, t := range f.trees {
+			axpy(w, t.leaf(X.row(i)).value, row)
+		}
+	}
+	return out, nil
+}
+
+// FeatureImportances returns the mean over the trees of the share of the impurity decrease achieved by
+// the splits on each feature, normalized to sum to 1.
+func (f *forest) FeatureImportances() []float64 {
+	return f.importances
+}
+
+// OOBScore returns the score of the out-of-bag predictions for the training samples, each made by the
+// trees that did not see that sample. It is only available after fitting with OOB set.
+func (f *forest) OOBScore() (float64, error) {
+	if !f.hasOOB {
+		return math.NaN(), fmt.Errorf("%s.OOBScore: the model was not fitted with OOB set", f.name)
+	}
+	return f.oob, nil
+}
+
+// forestClassifier is the part shared by the forest classifiers.
+type forestClassifier[T Number] struct {
+	forest
+	classes []T
+}
+
+// fit grows the forest on the samples in the rows of X, whose labels are in the column vector y.
+func (fc *forestClassifier[T]) fit(X, y *Matrix[T], cfg forestConfig) error {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if X.rows == 0 {
+		return errors.New("X has no samples")
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return fmt.Errorf("y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	if cfg.tree.criterion == CriterionAuto {
+		cfg.tree.criterion = CriterionGini
+	}
+	if cfg.tree.criterion != CriterionGini && cfg.tree.criterion != CriterionEntropy {
+		return fmt.Errorf("unsupported criterion %d", cfg.tree.criterion)
+	}
+	classes, labels := encodeLabels(y)
+	targets := Empty[float64](len(labels), len(classes))
+	for i, c := range labels {
+		targets.set(i, c, 1)
+	}
+	fc.classes = classes
+	oob, seen := fc.grow(convert[float64](X), targets, cfg)
+	fc.hasOOB = oob != nil
+	if fc.hasOOB {
+		var hits, n int
+		for i, ok := range seen {
+			if ok {
+				n++
+				if argmax(oob.row(i)) == labels[i] {
+					hits++
+				}
+			}
+		}
+		fc.oob = float64(hits) / float64(n)
+	}
+	return nil
+}
+
+// PredictProba returns the mean over the trees of the class fractions in the leaf each row of X falls into,
+// with columns in the order of Classes.
+func (fc *forestClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	proba, err := fc.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("%s.PredictProba: %w", fc.name, err)
+	}
+	return convert[T](proba), nil
+}
+
+// Predict returns the class with the largest mean probability over the trees for each row of X,
+// breaking ties in favour of the smaller class.
+func (fc *forestClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	proba, err := fc.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("%s.Predict: %w", fc.name, err)
+	}
+	yh := Empty[T](proba.rows, 1)
+	for i := 0; i < proba.rows; i++ {
+		yh.set(i, 0, fc.classes[argmax(proba.row(i))])
+	}
+	return yh, nil
+}
+
+func (fc *forestClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := fc.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (fc *forestClassifier[T]) Classes() []T {
+	return fc.classes
+}
+
+// forestRegressor is the part shared by the forest regressors.
+type forestRegressor[T Number] struct {
+	forest
+}
+
+// fit grows the forest on the samples in the rows of X, whose targets are the rows of y.
+func (fr *forestRegressor[T]) fit(X, y *Matrix[T], cfg forestConfig) error {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if X.rows == 0 {
+		return errors.New("X has no samples")
+	}
+	if y.rows != X.rows {
+		return fmt.Errorf("y has %d rows, X has %d", y.rows, X.rows)
+	}
+	if cfg.tree.criterion != CriterionAuto && cfg.tree.criterion != CriterionMSE {
+		return fmt.Errorf("unsupported criterion %d", cfg.tree.criterion)
+	}
+	cfg.tree.criterion = CriterionMSE
+	oob, seen := fr.grow(convert[float64](X), convert[float64](y), cfg)
+	fr.hasOOB = oob != nil
+	if fr.hasOOB {
+		var rows []int
+		for i, ok := range seen {
+			if ok {
+				rows = append(rows, i)
+			}
+		}
+		fr.oob = r2Score(y.pick(rows), fr.round(oob.pick(rows)))
+	}
+	return nil
+}
+
+// round converts predictions to T, rounding to the nearest integer for integer types.
+func (fr *forestRegressor[T]) round(m *Matrix[float64]) *Matrix[T] {
+	out := Empty[T](m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		for c, v := range m.row(i) {
+			out.set(i, c, fromFloat[T](v))
+		}
+	}
+	return out
+}
+
+// Predict returns the mean over the trees of the mean targets in the leaf each row of X falls into.
+func (fr *forestRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	means, err := fr.predict(convert[float64](X))
+	if err != nil {
+		return nil, fmt.Errorf("%s.Predict: %w", fr.name, err)
+	}
+	return fr.round(means), nil
+}
+
+func (fr *forestRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := fr.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// sqrtFeatures returns the default number of candidate features per split for classification forests.
+func sqrtFeatures(p int) int {
+	if k := int(math.Sqrt(float64(p))); k > 1 {
+		return k
+	}
+	return 1
+}
+
+// RandomForestClassifier averages the class probabilities of decision trees, each grown on a bootstrap
+// sample of the training data and choosing each split among a random subset of the features.
+type RandomForestClassifier[T Number] struct {
+	// NEstimators is the number of trees. If zero, 100 is used.
+	NEstimators int
+	// Criterion is the impurity splits minimize, CriterionGini or CriterionEntropy.
+	// The zero value is CriterionAuto, which uses CriterionGini.
+	Criterion Criterion
+	// MaxDepth limits the depth of each tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of the cost-complexity pruning of each tree. If zero, trees are not pruned.
+	CCPAlpha float64
+	// MaxFeatures is the number of features drawn as candidates for each split.
+	// If zero, the square root of the number of features is used.
+	MaxFeatures int
+	// NoBootstrap grows every tree on the whole training set instead of a bootstrap sample.
+	NoBootstrap bool
+	// OOB scores the model on the training samples left out of each bootstrap sample during Fit; see OOBScore.
+	OOB bool
+	// Jobs is the number of goroutines growing trees. If zero, GOMAXPROCS is used.
+	Jobs int
+	// Seed seeds the bootstrap samples and feature draws, so fits are reproducible whatever Jobs is.
+	Seed int64
+
+	forestClassifier[T]
+}
+
+// Fit grows the forest on the samples in the rows of X, whose labels are in the column vector y.
+func (rf *RandomForestClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	rf.name = "RandomForestClassifier"
+	tree, err := newCARTConfig(rf.Criterion, rf.MaxDepth, rf.MinSamplesSplit, rf.MinSamplesLeaf, rf.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("RandomForestClassifier.Fit: %w", err)
+	}
+	cfg, err := newForestConfig(rf.NEstimators, tree, rf.MaxFeatures, sqrtFeatures(X.cols), !rf.NoBootstrap, rf.OOB, rf.Jobs, rf.Seed)
+	if err != nil {
+		return fmt.Errorf("RandomForestClassifier.Fit: %w", err)
+	}
+	if err := rf.fit(X, y, cfg); err != nil {
+		return fmt.Errorf("RandomForestClassifier.Fit: %w", err)
+	}
+	return nil
+}
+
+// RandomForestRegressor averages the predictions of regression trees, each grown on a bootstrap
+// sample of the training data. y may have several columns.
+type RandomForestRegressor[T Number] struct {
+	// NEstimators is the number of trees. If zero, 100 is used.
+	NEstimators int
+	// Criterion is the impurity splits minimize. Only CriterionMSE is supported,
+	// and the zero value CriterionAuto selects it.
+	Criterion Criterion
+	// MaxDepth limits the depth of each tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of the cost-complexity pruning of each tree. If zero, trees are not pruned.
+	CCPAlpha float64
+	// MaxFeatures is the number of features drawn as candidates for each split. If zero, all features are.
+	MaxFeatures int
+	// NoBootstrap grows every tree on the whole training set instead of a bootstrap sample.
+	NoBootstrap bool
+	// OOB scores the model on the training samples left out of each bootstrap sample during Fit; see OOBScore.
+	OOB bool
+	// Jobs is the number of goroutines growing trees. If zero, GOMAXPROCS is used.
+	Jobs int
+	// Seed seeds the bootstrap samples and feature draws, so fits are reproducible whatever Jobs is.
+	Seed int64
+
+	forestRegressor[T]
+}
+
+// Fit grows the forest on the samples in the rows of X, whose targets are the rows of y.
+func (rf *RandomForestRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	rf.name = "RandomForestRegressor"
+	tree, err := newCARTConfig(rf.Criterion, rf.MaxDepth, rf.MinSamplesSplit, rf.MinSamplesLeaf, rf.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("RandomForestRegressor.Fit: %w", err)
+	}
+	cfg, err := newForestConfig(rf.NEstimators, tree, rf.MaxFeatures, X.cols, !rf.NoBootstrap, rf.OOB, rf.Jobs, rf.Seed)
+	if err != nil {
+		return fmt.Errorf("RandomForestRegressor.Fit: %w", err)
+	}
+	if err := rf.fit(X, y, cfg); err != nil {
+		return fmt.Errorf("RandomForestRegressor.Fit: %w", err)
+	}
+	return nil
+}
+
+// ExtraTreesClassifier averages the class probabilities of extremely randomized trees, which split
+// each node at a random threshold on the best of a random subset of the features.
+// By default every tree sees the whole training set.
+type ExtraTreesClassifier[T Number] struct {
+	// NEstimators is the number of trees. If zero, 100 is used.
+	NEstimators int
+	// Criterion is the impurity splits minimize, CriterionGini or CriterionEntropy.
+	// The zero value is CriterionAuto, which uses CriterionGini.
+	Criterion Criterion
+	// MaxDepth limits the depth of each tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of the cost-complexity pruning of each tree. If zero, trees are not pruned.
+	CCPAlpha float64
+	// MaxFeatures is the number of features drawn as candidates for each split.
+	// If zero, the square root of the number of features is used.
+	MaxFeatures int
+	// Bootstrap grows each tree on a bootstrap sample of the training set instead of the whole of it.
+	Bootstrap bool
+	// OOB scores the model on the training samples left out of each bootstrap sample during Fit; see OOBScore.
+	// It needs Bootstrap.
+	OOB bool
+	// Jobs is the number of goroutines growing trees. If zero, GOMAXPROCS is used.
+	Jobs int
+	// Seed seeds the thresholds, feature draws and any bootstrap samples, so fits are reproducible whatever Jobs is.
+	Seed int64
+
+	forestClassifier[T]
+}
+
+// Fit grows the forest on the samples in the rows of X, whose labels are in the column vector y.
+func (et *ExtraTreesClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	et.name = "ExtraTreesClassifier"
+	tree, err := newCARTConfig(et.Criterion, et.MaxDepth, et.MinSamplesSplit, et.MinSamplesLeaf, et.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("ExtraTreesClassifier.Fit: %w", err)
+	}
+	tree.random = true
+	cfg, err := newForestConfig(et.NEstimators, tree, et.MaxFeatures, sqrtFeatures(X.cols), et.Bootstrap, et.OOB, et.Jobs, et.Seed)
+	if err != nil {
+		return fmt.Errorf("ExtraTreesClassifier.Fit: %w", err)
+	}
+	if err := et.fit(X, y, cfg); err != nil {
+		return fmt.Errorf("ExtraTreesClassifier.Fit: %w", err)
+	}
+	return nil
+}
+
+// ExtraTreesRegressor averages the predictions of extremely randomized regression trees, which split
+// each node at a random threshold on the best of a random subset of the features.
+// By default every tree sees the whole training set. y may have several columns.
+type ExtraTreesRegressor[T Number] struct {
+	// NEstimators is the number of trees. If zero, 100 is used.
+	NEstimators int
+	// Criterion is the impurity splits minimize. Only CriterionMSE is supported,
+	// and the zero value CriterionAuto selects it.
+	Criterion Criterion
+	// MaxDepth limits the depth of each tree. If zero, nodes are split until they are pure or too small to split.
+	MaxDepth int
+	// MinSamplesSplit is the fewest samples a node needs to be split. If zero, 2 is used.
+	MinSamplesSplit int
+	// MinSamplesLeaf is the fewest samples each side of a split must keep. If zero, 1 is used.
+	MinSamplesLeaf int
+	// CCPAlpha is the complexity parameter of the cost-complexity pruning of each tree. If zero, trees are not pruned.
+	CCPAlpha float64
+	// MaxFeatures is the number of features drawn as candidates for each split. If zero, all features are.
+	MaxFeatures int
+	// Bootstrap grows each tree on a bootstrap sample of the training set instead of the whole of it.
+	Bootstrap bool
+	// OOB scores the model on the training samples left out of each bootstrap sample during Fit; see OOBScore.
+	// It needs Bootstrap.
+	OOB bool
+	// Jobs is the number of goroutines growing trees. If zero, GOMAXPROCS is used.
+	Jobs int
+	// Seed seeds the thresholds, feature draws and any bootstrap samples, so fits are reproducible whatever Jobs is.
+	Seed int64
+
+	forestRegressor[T]
+}
+
+// Fit grows the forest on the samples in the rows of X, whose targets are the rows of y.
+func (et *ExtraTreesRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	et.name = "ExtraTreesRegressor"
+	tree, err := newCARTConfig(et.Criterion, et.MaxDepth, et.MinSamplesSplit, et.MinSamplesLeaf, et.CCPAlpha)
+	if err != nil {
+		return fmt.Errorf("ExtraTreesRegressor.Fit: %w", err)
+	}
+	tree.random = true
+	cfg, err := newForestConfig(et.NEstimators, tree, et.MaxFeatures, X.cols, et.Bootstrap, et.OOB, et.Jobs, et.Seed)
+	if err != nil {
+		return fmt.Errorf("ExtraTreesRegressor.Fit: %w", err)
+	}
+	if err := et.fit(X, y, cfg); err != nil {
+		return fmt.Errorf("ExtraTreesRegressor.Fit: %w", err)
+	}
+	return nil
+}
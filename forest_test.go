@@ -0,0 +1,143 @@
+package pa
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestForestClassifiers(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {4, 0}, {0, 4}}, 40)
+	tests := []struct {
+		name  string
+		model func(jobs int) Classifier[float64]
+	}{
+		{"random forest", func(jobs int) Classifier[float64] {
+			return &RandomForestClassifier[float64]{NEstimators: 30, OOB: true, Jobs: jobs, Seed: 3}
+		}},
+		{"random forest entropy", func(jobs int) Classifier[float64] {
+			return &RandomForestClassifier[float64]{NEstimators: 30, Criterion: CriterionEntropy, MaxDepth: 4, OOB: true, Jobs: jobs, Seed: 3}
+		}},
+		{"extra trees", func(jobs int) Classifier[float64] {
+			return &ExtraTreesClassifier[float64]{NEstimators: 30, Bootstrap: true, OOB: true, Jobs: jobs, Seed: 3}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.model(4)
+			if err := m.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := m.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.95 {
+				t.Errorf("Score() = %v, want at least 0.95", acc)
+			}
+			oob, err := m.(interface{ OOBScore() (float64, error) }).OOBScore()
+			if err != nil {
+				t.Fatalf("OOBScore() error: %v", err)
+			}
+			if oob < 0.85 || oob > acc {
+				t.Errorf("OOBScore() = %v, want between 0.85 and the training accuracy %v", oob, acc)
+			}
+			proba, err := m.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-9 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+
+			// the forest does not depend on how many goroutines grew it
+			serial := tt.model(1)
+			if err := serial.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			want, _ := serial.PredictProba(X)
+			if !reflect.DeepEqual(proba.data, want.data) {
+				t.Error("PredictProba() differs between Jobs 4 and Jobs 1")
+			}
+		})
+	}
+}
+
+func TestForestRegressors(t *testing.T) {
+	// the target depends on the first feature only
+	rng := rand.New(rand.NewSource(1))
+	X, y := Empty[float64](200, 3), Empty[float64](200, 1)
+	for i := 0; i < X.rows; i++ {
+		for j := range X.row(i) {
+			X.set(i, j, rng.Float64()*6)
+		}
+		y.set(i, 0, math.Sin(X.at(i, 0))+0.1*rng.NormFloat64())
+	}
+	tests := []struct {
+		name string
+		m    Regressor[float64]
+	}{
+		{"random forest", &RandomForestRegressor[float64]{NEstimators: 40, OOB: true, Seed: 1}},
+		{"random forest subsampled", &RandomForestRegressor[float64]{NEstimators: 40, MaxFeatures: 2, MinSamplesLeaf: 3, OOB: true, Seed: 1}},
+		{"extra trees", &ExtraTreesRegressor[float64]{NEstimators: 40, Bootstrap: true, OOB: true, Seed: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			r2, err := tt.m.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if r2 < 0.9 {
+				t.Errorf("Score() = %v, want at least 0.9", r2)
+			}
+			oob, err := tt.m.(interface{ OOBScore() (float64, error) }).OOBScore()
+			if err != nil {
+				t.Fatalf("OOBScore() error: %v", err)
+			}
+			if oob < 0.8 {
+				t.Errorf("OOBScore() = %v, want at least 0.8", oob)
+			}
+			imp := tt.m.(interface{ FeatureImportances() []float64 }).FeatureImportances()
+			if math.Abs(sum(imp)-1) > 1e-12 || imp[0] < 0.8 {
+				t.Errorf("FeatureImportances() = %v, want most weight on feature 0", imp)
+			}
+		})
+	}
+}
+
+func TestForestErrors(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {4, 0}}, 10)
+	tests := []struct {
+		name string
+		m    interface {
+			Fit(X, y *Matrix[float64]) error
+		}
+	}{
+		{"oob without bootstrap", &ExtraTreesClassifier[float64]{OOB: true}},
+		{"oob with NoBootstrap", &RandomForestRegressor[float64]{NoBootstrap: true, OOB: true}},
+		{"negative trees", &RandomForestClassifier[float64]{NEstimators: -1}},
+		{"regression criterion", &RandomForestClassifier[float64]{Criterion: CriterionMSE}},
+		{"classification criterion", &ExtraTreesRegressor[float64]{Criterion: CriterionGini}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Fit(X, y); err == nil {
+				t.Error("Fit() returned nil error")
+			}
+		})
+	}
+
+	rf := &RandomForestClassifier[float64]{NEstimators: 5}
+	if err := rf.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if _, err := rf.OOBScore(); err == nil {
+		t.Error("OOBScore() without OOB returned nil error")
+	}
+}
@@ -0,0 +1,489 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+)
+
+// Family selects the distribution of the response of a generalized linear model, given its mean μ.
+// Every family but FamilyBinomial is a Tweedie family with variance proportional to a power of μ.
+type Family int
+
+const (
+	// FamilyGaussian has constant variance and suits unbounded responses.
+	FamilyGaussian Family = iota
+	// FamilyBinomial has variance μ(1 - μ) and suits proportions and 0/1 responses in [0, 1].
+	FamilyBinomial
+	// FamilyPoisson has variance μ and suits non-negative counts.
+	FamilyPoisson
+	// FamilyGamma has variance μ² and suits positive responses whose spread grows with their size, such as claim severities.
+	FamilyGamma
+	// FamilyTweedie has variance μ^Power. With 1 < Power < 2 it is a compound Poisson–gamma distribution,
+	// which puts mass at zero and suits non-negative responses such as pure premiums.
+	FamilyTweedie
+	// FamilyInverseGaussian has variance μ³ and suits positive, strongly right-skewed responses.
+	FamilyInverseGaussian
+)
+
+// Link selects the function g relating the mean μ of the response to the linear predictor: g(μ) = Xb.
+type Link int
+
+const (
+	// LinkDefault uses the canonical link of the family: identity for FamilyGaussian, logit for FamilyBinomial,
+	// log for FamilyPoisson and inverse for FamilyGamma. FamilyTweedie and FamilyInverseGaussian use the log link.
+	LinkDefault Link = iota
+	// LinkIdentity is g(μ) = μ.
+	LinkIdentity
+	// LinkLog is g(μ) = log μ, which makes the effects of features multiplicative.
+	LinkLog
+	// LinkLogit is g(μ) = log(μ/(1 - μ)), the log odds of a probability.
+	LinkLogit
+	// LinkInverse is g(μ) = 1/μ.
+	LinkInverse
+)
+
+// link, inverse and derivative evaluate g, its inverse and its derivative.
+func (l Link) link(mu float64) float64 {
+	switch l {
+	case LinkLog:
+		return math.Log(mu)
+	case LinkLogit:
+		return math.Log(mu / (1 - mu))
+	case LinkInverse:
+		return 1 / mu
+	}
+	return mu
+}
+
+func (l Link) inverse(eta float64) float64 {
+	switch l {
+	case LinkLog:
+		return math.Exp(eta)
+	case LinkLogit:
+		return sigmoid(eta)
+	case LinkInverse:
+		return 1 / eta
+	}
+	return eta
+}
+
+func (l Link) derivative(mu float64) float64 {
+	switch l {
+	case LinkLog:
+		return 1 / mu
+	case LinkLogit:
+		return 1 / (mu * (1 - mu))
+	case LinkInverse:
+		return -1 / (mu * mu)
+	}
+	return 1
+}
+
+// glmFamily is a family with its variance power resolved. power is ignored for FamilyBinomial.
+type glmFamily struct {
+	family Family
+	power  float64
+}
+
+func (f glmFamily) variance(mu float64) float64 {
+	if f.family == FamilyBinomial {
+		return mu * (1 - mu)
+	}
+	return math.Pow(mu, f.power)
+}
+
+// deviance returns the unit deviance d(y, μ), twice the log-likelihood lost by predicting μ rather than y.
+func (f glmFamily) deviance(y, mu float64) float64 {
+	if f.family == FamilyBinomial {
+		return 2 * (xlogy(y, y/mu) + xlogy(1-y, (1-y)/(1-mu)))
+	}
+	switch p := f.power; p {
+	case 0:
+		return (y - mu) * (y - mu)
+	case 1:
+		return 2 * (xlogy(y, y/mu) - y + mu)
+	case 2:
+		return 2 * (y/mu - math.Log(y/mu) - 1)
+	default:
+		return 2 * (math.Pow(math.Max(y, 0), 2-p)/((1-p)*(2-p)) - y*math.Pow(mu, 1-p)/(1-p) + math.Pow(mu, 2-p)/(2-p))
+	}
+}
+
+// check returns an error if y is outside the support of the family.
+func (f glmFamily) check(y float64) error {
+	switch {
+	case f.family == FamilyBinomial:
+		if y < 0 || y > 1 {
+			return fmt.Errorf("binomial responses must be in [0, 1], got %v", y)
+		}
+	case f.power == 0:
+	case f.power < 2:
+		if y < 0 {
+			return fmt.Errorf("responses must not be negative, got %v", y)
+		}
+	default:
+		if y <= 0 {
+			return fmt.Errorf("responses must be positive, got %v", y)
+		}
+	}
+	return nil
+}
+
+// valid reports whether mu is a possible mean for the family.
+func (f glmFamily) valid(mu float64) bool {
+	switch {
+	case math.IsNaN(mu) || math.IsInf(mu, 0):
+		return false
+	case f.family == FamilyBinomial:
+		return mu > 0 && mu < 1
+	case f.power == 0:
+		return true
+	}
+	return mu > 0
+}
+
+// knownDispersion reports whether the dispersion of the family is fixed at 1 rather than estimated.
+func (f glmFamily) knownDispersion() bool {
+	return f.family == FamilyBinomial || f.family == FamilyPoisson
+}
+
+// xlogy returns x·log(y), taking 0·log(0) as 0.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
+
+// GLM is a generalized linear model: the response follows a distribution of Family whose mean μ is related to
+// the features by Link, g(μ) = b₀ + Xb. It is fitted by maximum likelihood with iteratively reweighted least
+// squares, solving a weighted least squares problem with Solver at each iteration.
+type GLM[T Float] struct {
+	// Family is the distribution of the response. The zero value is FamilyGaussian.
+	Family Family
+	// Link relates the mean to the linear predictor. The zero value, LinkDefault, uses the family's usual link.
+	Link Link
+	// Power is the variance power of FamilyTweedie, which must be at least 1. If zero, 1.5 is used;
+	// FamilyGaussian is the Tweedie family of power zero. Power is ignored by the other families.
+	Power float64
+	// Solver is the method used for each weighted least squares problem. The zero value is SolveCholesky.
+	Solver Solver
+	// Tol stops fitting once an iteration changes the deviance by at most Tol relative to it. If zero, 1e-8 is used.
+	Tol float64
+	// MaxIter bounds the number of iterations. If zero, 100 is used.
+	MaxIter int
+
+	coef         *Matrix[T]
+	se           []float64
+	family       glmFamily
+	link         Link
+	deviance     float64
+	nullDeviance float64
+	dispersion   float64
+	dfResid      int
+	iters        int
+}
+
+// resolve returns the family and link to fit, with defaults filled in.
+func (g *GLM[T]) resolve() (glmFamily, Link, error) {
+	f := glmFamily{family: g.Family}
+	link := g.Link
+	switch g.Family {
+	case FamilyGaussian:
+		f.power = 0
+		if link == LinkDefault {
+			link = LinkIdentity
+		}
+	case FamilyBinomial:
+		if link == LinkDefault {
+			link = LinkLogit
+		}
+	case FamilyPoisson:
+		f.power = 1
+		if link == LinkDefault {
+			link = LinkLog
+		}
+	case FamilyGamma:
+		f.power = 2
+		if link == LinkDefault {
+			link = LinkInverse
+		}
+	case FamilyTweedie:
+		f.power = g.Power
+		if f.power == 0 {
+			f.power = 1.5
+		}
+		if f.power < 1 {
+			return f, link, fmt.Errorf("Tweedie Power must be at least 1, got %v", g.Power)
+		}
+		if link == LinkDefault {
+			link = LinkLog
+		}
+	case FamilyInverseGaussian:
+		f.power = 3
+		if link == LinkDefault {
+			link = LinkLog
+		}
+	default:
+		return f, link, fmt.Errorf("unknown family %d", g.Family)
+	}
+	if link < LinkDefault || link > LinkInverse {
+		return f, link, fmt.Errorf("unknown link %d", link)
+	}
+	return f, link, nil
+}
+
+// Fit fits the model to the samples in the rows of X, whose responses are in the column vector y.
+func (g *GLM[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("GLM.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	if n <= p+1 {
+		return fmt.Errorf("GLM.Fit: need more samples than the %d coefficients, got %d", p+1, n)
+	}
+	family, link, err := g.resolve()
+	if err != nil {
+		return fmt.Errorf("GLM.Fit: %w", err)
+	}
+	tol, maxIter := g.Tol, g.MaxIter
+	if tol == 0 {
+		tol = 1e-8
+	}
+	if maxIter == 0 {
+		maxIter = 100
+	}
+
+	xa := convert[float64](withIntercept(X))
+	ys := make([]float64, n)
+	for i := range ys {
+		ys[i] = float64(y.at(i, 0))
+		if err := family.check(ys[i]); err != nil {
+			return fmt.Errorf("GLM.Fit: sample %d: %w", i, err)
+		}
+	}
+	ybar := mean(ys)
+	mu := make([]float64, n)
+	for i, v := range ys {
+		// start halfway between each response and the mean, which keeps μ inside the support
+		mu[i] = (v + ybar) / 2
+		if family.family == FamilyBinomial {
+			mu[i] = (v + 0.5) / 2
+		}
+	}
+	eta := make([]float64, n)
+	for i := range eta {
+		eta[i] = link.link(mu[i])
+	}
+	dev := totalDeviance(family, ys, mu)
+
+	w := make([]float64, n)
+	xw, zw := Empty[float64](n, p+1), Empty[float64](n, 1)
+	var b *Matrix[float64]
+	converged := false
+	iter := 0
+	for iter < maxIter && !converged {
+		iter++
+		// the working response z linearizes g around μ, and w weighs each sample by its precision on that scale
+		for i := range ys {
+			d := link.derivative(mu[i])
+			w[i] = 1 / (d * d * family.variance(mu[i]))
+			root := math.Sqrt(w[i])
+			for j, v := range xa.row(i) {
+				xw.set(i, j, root*v)
+			}
+			zw.set(i, 0, root*(eta[i]+(ys[i]-mu[i])*d))
+		}
+		next, err := solveLinear(xw, zw, 0, g.Solver)
+		if err != nil {
+			return fmt.Errorf("GLM.Fit: %w", err)
+		}
+		// halve the step while it leaves the support of the family or increases the deviance
+		var newDev float64
+		for halving := 0; ; halving++ {
+			newDev = math.NaN()
+			etaNew := xa.Mul(next)
+			ok := true
+			for i := range mu {
+				mu[i] = link.inverse(etaNew.at(i, 0))
+				if !family.valid(mu[i]) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				newDev = totalDeviance(family, ys, mu)
+				for i := range eta {
+					eta[i] = etaNew.at(i, 0)
+				}
+			}
+			if ok && (b == nil || newDev <= dev*(1+1e-12)) {
+				break
+			}
+			if b == nil || halving == 30 {
+				return fmt.Errorf("GLM.Fit: link %d gives means outside the support of the family; try another link", link)
+			}
+			next = next.Add(b).Product(0.5)
+		}
+		b = next
+		converged = math.Abs(newDev-dev) <= tol*(math.Abs(newDev)+0.1)
+		dev = newDev
+	}
+
+	// the covariance of b is the dispersion times (XᵀWX)⁻¹ at the solution
+	pearson := 0.0
+	for i := range ys {
+		d := link.derivative(mu[i])
+		w[i] = 1 / (d * d * family.variance(mu[i]))
+		root := math.Sqrt(w[i])
+		for j, v := range xa.row(i) {
+			xw.set(i, j, root*v)
+		}
+		pearson += (ys[i] - mu[i]) * (ys[i] - mu[i]) / family.variance(mu[i])
+	}
+	inner := xw.T().Mul(xw)
+	info, err := FactorCholesky(inner)
+	if err != nil {
+		return fmt.Errorf("GLM.Fit: %w", err)
+	}
+	g.family, g.link = family, link
+	g.dfResid = n - p - 1
+	g.dispersion = 1
+	if !family.knownDispersion() {
+		g.dispersion = pearson / float64(g.dfResid)
+	}
+	cov := info.Inverse(inner)
+	g.se = make([]float64, p+1)
+	for j := range g.se {
+		g.se[j] = math.Sqrt(g.dispersion * cov.at(j, j))
+	}
+	g.coef = convert[T](b)
+	g.deviance = dev
+	nullMu := make([]float64, n)
+	for i := range nullMu {
+		nullMu[i] = ybar
+	}
+	g.nullDeviance = totalDeviance(family, ys, nullMu)
+	g.iters = iter
+	if !converged {
+		return fmt.Errorf("GLM.Fit: %w after %d iterations", ErrNoConvergence, iter)
+	}
+	return nil
+}
+
+func totalDeviance(f glmFamily, y, mu []float64) float64 {
+	var d float64
+	for i := range y {
+		d += f.deviance(y[i], mu[i])
+	}
+	return d
+}
+
+// Predict returns the predicted mean response for each row of X.
+func (g *GLM[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	if g.coef == nil {
+		return nil, fmt.Errorf("GLM.Predict: model is not fitted")
+	}
+	eta := withIntercept(X).Mul(g.coef)
+	if eta.Err() != nil {
+		return nil, fmt.Errorf("GLM.Predict: %w", eta.Err())
+	}
+	return eta.Apply(func(v T) T { return T(g.link.inverse(float64(v))) }), nil
+}
+
+// Score returns the coefficient of determination R² of the predicted means for X, as for every Regressor.
+// DevianceExplained is the analogue measured in the family's own deviance.
+func (g *GLM[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := g.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// DevianceExplained returns the fraction of the null deviance explained by the model on X and y, 1 - D/D₀,
+// where D₀ is the deviance of predicting the mean of y for every sample. For FamilyGaussian it equals Score.
+func (g *GLM[T]) DevianceExplained(X, y *Matrix[T]) (float64, error) {
+	yh, err := g.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	n := y.rows
+	ys, mu, nullMu := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range ys {
+		ys[i], mu[i] = float64(y.at(i, 0)), float64(yh.at(i, 0))
+	}
+	ybar := mean(ys)
+	for i := range nullMu {
+		nullMu[i] = ybar
+	}
+	return 1 - totalDeviance(g.family, ys, mu)/totalDeviance(g.family, ys, nullMu), nil
+}
+
+// Coefficients returns the fitted coefficients, with the intercept first.
+func (g *GLM[T]) Coefficients() *Matrix[T] {
+	return g.coef
+}
+
+// StdErrors returns the standard error of each coefficient, in the order of Coefficients.
+func (g *GLM[T]) StdErrors() *Matrix[T] {
+	return g.column(func(j int) float64 { return g.se[j] })
+}
+
+// PValues returns, for each coefficient, the two-sided p-value of the Wald test that it is zero. For the
+// binomial and Poisson families, whose dispersion is known, the test statistic is compared with the standard
+// normal distribution; otherwise the dispersion is estimated and it is compared with Student's t distribution
+// on DFResid degrees of freedom.
+func (g *GLM[T]) PValues() *Matrix[T] {
+	return g.column(func(j int) float64 {
+		stat := math.Abs(float64(g.coef.at(j, 0))) / g.se[j]
+		if g.family.knownDispersion() {
+			return 2 * normalSF(stat)
+		}
+		return 2 * studentTSF(stat, float64(g.dfResid))
+	})
+}
+
+func (g *GLM[T]) column(f func(j int) float64) *Matrix[T] {
+	if g.coef == nil {
+		return nil
+	}
+	m := Empty[T](len(g.se), 1)
+	for j := range g.se {
+		m.set(j, 0, T(f(j)))
+	}
+	return m
+}
+
+// Deviance returns the residual deviance of the fitted model, twice the log-likelihood it loses to a model
+// that fits every response exactly.
+func (g *GLM[T]) Deviance() float64 {
+	return g.deviance
+}
+
+// NullDeviance returns the deviance of the model with only an intercept, which predicts the mean response.
+func (g *GLM[T]) NullDeviance() float64 {
+	return g.nullDeviance
+}
+
+// Dispersion returns the dispersion φ, which scales the variance function: Var(y) = φV(μ). It is 1 for the
+// binomial and Poisson families, and otherwise the Pearson χ² statistic divided by DFResid.
+func (g *GLM[T]) Dispersion() float64 {
+	return g.dispersion
+}
+
+// DFResid returns the residual degrees of freedom, the number of samples less the number of coefficients.
+func (g *GLM[T]) DFResid() int {
+	return g.dfResid
+}
+
+// Iterations returns the number of IRLS iterations made by the last call to Fit.
+func (g *GLM[T]) Iterations() int {
+	return g.iters
+}
@@ -0,0 +1,256 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGLMReference(t *testing.T) {
+	// the examples of R's ?glm, with the estimates R reports
+	tests := []struct {
+		name         string
+		glm          *GLM[float64]
+		X, y         [][]float64
+		coef, se, pv []float64
+		deviance     float64
+		null         float64
+		dispersion   float64
+	}{
+		{
+			// Dobson (1990): counts ~ outcome + treatment, with dummy-coded factors
+			name: "poisson",
+			glm:  &GLM[float64]{Family: FamilyPoisson},
+			X: [][]float64{
+				{0, 0, 0, 0}, {1, 0, 0, 0}, {0, 1, 0, 0},
+				{0, 0, 1, 0}, {1, 0, 1, 0}, {0, 1, 1, 0},
+				{0, 0, 0, 1}, {1, 0, 0, 1}, {0, 1, 0, 1},
+			},
+			y:          [][]float64{{18}, {17}, {15}, {20}, {10}, {20}, {25}, {13}, {12}},
+			coef:       []float64{3.045, -0.4543, -0.2930, 0, 0},
+			se:         []float64{0.1709, 0.2022, 0.1927, 0.2000, 0.2000},
+			pv:         []float64{5.4e-71, 0.0246, 0.1285, 1, 1},
+			deviance:   5.1291,
+			null:       10.5814,
+			dispersion: 1,
+		},
+		{
+			// McCullagh and Nelder (1989): clotting time of plasma lot 1 ~ log(u)
+			name: "gamma",
+			glm:  &GLM[float64]{Family: FamilyGamma},
+			X: [][]float64{
+				{math.Log(5)}, {math.Log(10)}, {math.Log(15)}, {math.Log(20)}, {math.Log(30)},
+				{math.Log(40)}, {math.Log(60)}, {math.Log(80)}, {math.Log(100)},
+			},
+			y:          [][]float64{{118}, {58}, {42}, {35}, {27}, {25}, {21}, {19}, {18}},
+			coef:       []float64{-0.01655438, 0.01534311},
+			se:         []float64{0.0009275, 0.0004150},
+			pv:         []float64{4.28e-07, 2.75e-09},
+			deviance:   0.01673,
+			null:       3.51283,
+			dispersion: 0.002446059,
+		},
+	}
+	close := func(got, want float64) bool {
+		// R prints four significant figures; anything below 1e-8 is zero
+		return math.Abs(got-want) <= 5e-4*math.Abs(want)+1e-8
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := NewMatrix(tt.X, nil), NewMatrix(tt.y, nil)
+			if err := tt.glm.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			coef, se, pv := tt.glm.Coefficients(), tt.glm.StdErrors(), tt.glm.PValues()
+			for j := range tt.coef {
+				if !close(coef.at(j, 0), tt.coef[j]) && math.Abs(coef.at(j, 0)-tt.coef[j]) > 5e-4 {
+					t.Errorf("coefficient %d = %v, want %v", j, coef.at(j, 0), tt.coef[j])
+				}
+				if !close(se.at(j, 0), tt.se[j]) && math.Abs(se.at(j, 0)-tt.se[j]) > 5e-5 {
+					t.Errorf("standard error %d = %v, want %v", j, se.at(j, 0), tt.se[j])
+				}
+				if math.Abs(pv.at(j, 0)-tt.pv[j]) > 5e-3*tt.pv[j]+1e-4 {
+					t.Errorf("p-value %d = %v, want %v", j, pv.at(j, 0), tt.pv[j])
+				}
+			}
+			if d := tt.glm.Deviance(); math.Abs(d-tt.deviance) > 5e-4*tt.deviance {
+				t.Errorf("Deviance() = %v, want %v", d, tt.deviance)
+			}
+			if d := tt.glm.NullDeviance(); math.Abs(d-tt.null) > 5e-4*tt.null {
+				t.Errorf("NullDeviance() = %v, want %v", d, tt.null)
+			}
+			if d := tt.glm.Dispersion(); math.Abs(d-tt.dispersion) > 5e-4*tt.dispersion {
+				t.Errorf("Dispersion() = %v, want %v", d, tt.dispersion)
+			}
+			if d2, _ := tt.glm.DevianceExplained(X, y); math.Abs(d2-(1-tt.deviance/tt.null)) > 1e-3 {
+				t.Errorf("DevianceExplained() = %v, want 1 - deviance/null deviance = %v", d2, 1-tt.deviance/tt.null)
+			}
+			// Score is the R² of the predicted means, which differs from the deviance explained outside the Gaussian family
+			yh, _ := tt.glm.Predict(X)
+			if score, _ := tt.glm.Score(X, y); math.Abs(score-r2Score(y, yh)) > 1e-12 {
+				t.Errorf("Score() = %v, want the R² of the predictions %v", score, r2Score(y, yh))
+			}
+		})
+	}
+}
+
+func TestGLMScoreEquations(t *testing.T) {
+	// at the maximum likelihood estimate Σ xᵢ(yᵢ - μᵢ)/(V(μᵢ)g'(μᵢ)) = 0 for every family and link
+	rng := rand.New(rand.NewSource(1))
+	n := 200
+	X := Empty[float64](n, 2)
+	positive, counts, proportions := Empty[float64](n, 1), Empty[float64](n, 1), Empty[float64](n, 1)
+	for i := 0; i < n; i++ {
+		a, b := rng.Float64(), rng.Float64()
+		X.set(i, 0, a)
+		X.set(i, 1, b)
+		mu := math.Exp(0.5 + 0.8*a - 0.6*b)
+		positive.set(i, 0, mu*rng.ExpFloat64())
+		// a Poisson draw by counting exponential arrivals in [0, mu]
+		k := 0
+		for s := rng.ExpFloat64(); s < mu; s += rng.ExpFloat64() {
+			k++
+		}
+		counts.set(i, 0, float64(k))
+		proportions.set(i, 0, float64(k%2))
+	}
+	tests := []struct {
+		name string
+		glm  *GLM[float64]
+		y    *Matrix[float64]
+	}{
+		{"gaussian", &GLM[float64]{}, positive},
+		{"gaussian log", &GLM[float64]{Link: LinkLog}, positive},
+		{"binomial", &GLM[float64]{Family: FamilyBinomial}, proportions},
+		{"poisson", &GLM[float64]{Family: FamilyPoisson}, counts},
+		{"poisson identity", &GLM[float64]{Family: FamilyPoisson, Link: LinkIdentity}, counts},
+		{"gamma", &GLM[float64]{Family: FamilyGamma}, positive},
+		{"gamma log", &GLM[float64]{Family: FamilyGamma, Link: LinkLog}, positive},
+		{"tweedie", &GLM[float64]{Family: FamilyTweedie}, counts},
+		{"tweedie 1.2 qr", &GLM[float64]{Family: FamilyTweedie, Power: 1.2, Solver: SolveQR}, counts},
+		{"tweedie 2.5", &GLM[float64]{Family: FamilyTweedie, Power: 2.5}, positive},
+		{"inverse gaussian", &GLM[float64]{Family: FamilyInverseGaussian}, positive},
+		{"inverse gaussian inverse", &GLM[float64]{Family: FamilyInverseGaussian, Link: LinkInverse}, positive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.glm.Tol = 1e-12
+			if err := tt.glm.Fit(X, tt.y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			mu, err := tt.glm.Predict(X)
+			if err != nil {
+				t.Fatalf("Predict() error: %v", err)
+			}
+			f, link := tt.glm.family, tt.glm.link
+			score := make([]float64, 3)
+			for i := 0; i < n; i++ {
+				m := mu.at(i, 0)
+				r := (tt.y.at(i, 0) - m) / (f.variance(m) * link.derivative(m))
+				score[0] += r
+				score[1] += r * X.at(i, 0)
+				score[2] += r * X.at(i, 1)
+			}
+			if normInf := maxAbs(NewMatrix([][]float64{score}, nil)); normInf > 1e-6*float64(n) {
+				t.Errorf("score = %v, want zero", score)
+			}
+			if d := tt.glm.Deviance(); d > tt.glm.NullDeviance() {
+				t.Errorf("Deviance() = %v exceeds NullDeviance() = %v", d, tt.glm.NullDeviance())
+			}
+			for j, s := range tt.glm.StdErrors().data {
+				if !(s > 0) {
+					t.Errorf("standard error %d = %v", j, s)
+				}
+			}
+		})
+	}
+}
+
+func TestGLMMatchesLinearModels(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {1.5, 1}}, 60)
+	binomial := &GLM[float64]{Family: FamilyBinomial}
+	if err := binomial.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	lr := &LogisticRegression[float64]{Tol: 1e-10}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("LogisticRegression.Fit() error: %v", err)
+	}
+	if got, want := binomial.Coefficients(), lr.Coefficients(); !approxEqual(got, want, 1e-6) {
+		t.Errorf("binomial Coefficients():\n%s\nwant LogisticRegression's:\n%s", got, want)
+	}
+
+	// the Gaussian family is ordinary least squares, with the usual standard errors
+	rng := rand.New(rand.NewSource(3))
+	Xg, yg := Empty[float64](50, 2), Empty[float64](50, 1)
+	for i := 0; i < 50; i++ {
+		Xg.set(i, 0, rng.NormFloat64())
+		Xg.set(i, 1, rng.NormFloat64())
+		yg.set(i, 0, 1+2*Xg.at(i, 0)-Xg.at(i, 1)+rng.NormFloat64())
+	}
+	gaussian := &GLM[float64]{}
+	if err := gaussian.Fit(Xg, yg); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	ols := &LinearRegression[float64]{}
+	if err := ols.Fit(Xg, yg); err != nil {
+		t.Fatalf("LinearRegression.Fit() error: %v", err)
+	}
+	if got, want := gaussian.Coefficients(), ols.Coefficients(); !approxEqual(got, want, 1e-10) {
+		t.Errorf("Gaussian Coefficients():\n%s\nwant LinearRegression's:\n%s", got, want)
+	}
+	yh, _ := ols.Predict(Xg)
+	rss := yg.Sub(yh).Apply(func(v float64) float64 { return v * v }).Sum(All).at(0, 0)
+	if d := gaussian.Deviance(); math.Abs(d-rss) > 1e-9*rss {
+		t.Errorf("Deviance() = %v, want the residual sum of squares %v", d, rss)
+	}
+	if s := gaussian.Dispersion(); math.Abs(s-rss/47) > 1e-9*s {
+		t.Errorf("Dispersion() = %v, want RSS/(n - 3) = %v", s, rss/47)
+	}
+	r2, _ := ols.Score(Xg, yg)
+	score, _ := gaussian.Score(Xg, yg)
+	d2, _ := gaussian.DevianceExplained(Xg, yg)
+	if math.Abs(score-r2) > 1e-9 || math.Abs(d2-r2) > 1e-9 {
+		t.Errorf("Score(), DevianceExplained() = %v, %v, want LinearRegression's R² %v", score, d2, r2)
+	}
+	if gaussian.Iterations() > 2 {
+		t.Errorf("Iterations() = %d, want the identity link to converge at once", gaussian.Iterations())
+	}
+}
+
+func TestGLMErrors(t *testing.T) {
+	X := NewMatrix([][]float64{{1}, {2}, {3}, {4}}, nil)
+	tests := []struct {
+		name string
+		glm  *GLM[float64]
+		y    [][]float64
+	}{
+		{"negative count", &GLM[float64]{Family: FamilyPoisson}, [][]float64{{1}, {-1}, {2}, {3}}},
+		{"binomial above one", &GLM[float64]{Family: FamilyBinomial}, [][]float64{{0}, {1}, {2}, {1}}},
+		{"zero gamma response", &GLM[float64]{Family: FamilyGamma}, [][]float64{{1}, {0}, {2}, {3}}},
+		{"tweedie power below one", &GLM[float64]{Family: FamilyTweedie, Power: 0.5}, [][]float64{{1}, {1}, {2}, {3}}},
+		{"unknown family", &GLM[float64]{Family: 9}, [][]float64{{1}, {1}, {2}, {3}}},
+		{"unknown link", &GLM[float64]{Link: 9}, [][]float64{{1}, {1}, {2}, {3}}},
+		{"too few samples", &GLM[float64]{}, [][]float64{{1}, {2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Xs := X
+			if len(tt.y) < X.rows {
+				Xs = X.Slice(0, len(tt.y), 0, 1)
+			}
+			if err := tt.glm.Fit(Xs, NewMatrix(tt.y, nil)); err == nil {
+				t.Error("Fit() returned nil error")
+			}
+		})
+	}
+
+	if _, err := (&GLM[float64]{}).Predict(X); err == nil {
+		t.Error("Predict() before Fit returned nil error")
+	}
+	g := &GLM[float64]{Family: FamilyPoisson, MaxIter: 1}
+	if err := g.Fit(X, NewMatrix([][]float64{{1}, {3}, {2}, {7}}, nil)); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Fit() with MaxIter 1 error = %v, want ErrNoConvergence", err)
+	}
+}
@@ -0,0 +1,73 @@
+package optim
+
+import "math"
+
+// ConjugateGradient is nonlinear conjugate gradient with the Polak–Ribière+ update, which restarts from
+// steepest descent whenever the update would not descend and every len(x) iterations. Steps are chosen by
+// a strong Wolfe line search with c1 = 1e-4 and c2 = 0.1.
+type ConjugateGradient struct{}
+
+func (ConjugateGradient) Minimize(f Func, x0 []float64, s Settings) Result {
+	ls := Wolfe{C1: 1e-4, C2: 0.1}
+	p := &problem{f: f, max: s.MaxEval}
+	n := len(x0)
+	x := append([]float64(nil), x0...)
+	g, d := make([]float64, n), make([]float64, n)
+	xn, gn := make([]float64, n), make([]float64, n)
+	fx := p.eval(x, g)
+	copy(d, g)
+	scale(-1, d)
+	step := math.Min(1, 1/normInf(g))
+	since := 0 // iterations since the last restart
+	for iter := 0; iter < s.MaxIter; iter++ {
+		if normInf(g) <= s.GradTol {
+			return p.result(x, fx, GradientConverged, iter)
+		}
+		if p.exhausted() {
+			return p.result(x, fx, EvaluationLimit, iter)
+		}
+		r := ls.search(p, x, d, fx, g, step, xn, gn)
+		if !r.OK && since > 0 {
+			// the conjugate direction failed; try once more along steepest descent
+			copy(d, g)
+			scale(-1, d)
+			since = 0
+			step = math.Min(1, 1/normInf(g))
+			r = ls.search(p, x, d, fx, g, step, xn, gn)
+		}
+		if !r.OK {
+			return p.result(x, fx, LineSearchFailed, iter+1)
+		}
+
+		// β = max(0, gₙᵀ(gₙ - g) / gᵀg)
+		gg := dot(g, g)
+		beta := math.Max(0, (dot(gn, gn)-dot(gn, g))/gg)
+		since++
+		if since >= n {
+			beta, since = 0, 0
+		}
+		slope := dot(g, d)
+		for i := range d {
+			d[i] = beta*d[i] - gn[i]
+		}
+		if dot(d, gn) >= 0 {
+			copy(d, gn)
+			scale(-1, d)
+			since = 0
+		}
+		// start the next search where the last one would have ended to first order (Nocedal and Wright 3.60)
+		step = r.Step * slope / dot(gn, d)
+
+		done := settled(fx, r.F, s.FuncTol)
+		copy(x, xn)
+		copy(g, gn)
+		fx = r.F
+		if done {
+			return p.result(x, fx, FunctionConverged, iter+1)
+		}
+	}
+	if normInf(g) <= s.GradTol {
+		return p.result(x, fx, GradientConverged, s.MaxIter)
+	}
+	return p.result(x, fx, IterationLimit, s.MaxIter)
+}
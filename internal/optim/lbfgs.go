@@ -0,0 +1,161 @@
+package optim
+
+import "math"
+
+// LBFGS is limited-memory BFGS, which builds a quasi-Newton direction from the last Memory steps and the
+// changes in gradient over them. If Memory is zero, 10 is used.
+//
+// If L1 is not nil it minimizes f(x) + Σ L1[i]|x[i]| by the orthant-wise variant (OWL-QN) of Andrew and Gao,
+// which restricts each step to the orthant of the current iterate. GradTol then applies to the minimum-norm
+// subgradient.
+type LBFGS struct {
+	Memory int
+	L1     []float64
+}
+
+func (lb *LBFGS) Minimize(f Func, x0 []float64, s Settings) Result {
+	memory := lb.Memory
+	if memory == 0 {
+		memory = 10
+	}
+	l1 := lb.L1
+	p := &problem{f: f, max: s.MaxEval}
+	n := len(x0)
+	x := append([]float64(nil), x0...)
+	g, pg, d := make([]float64, n), make([]float64, n), make([]float64, n)
+	xn, gn := make([]float64, n), make([]float64, n)
+	fx := p.eval(x, g) + l1Norm(x, l1)
+
+	var ss, ys [][]float64
+	var rhos []float64
+	alpha := make([]float64, memory)
+	for iter := 0; iter < s.MaxIter; iter++ {
+		pseudoGradient(pg, x, g, l1)
+		if normInf(pg) <= s.GradTol {
+			return p.result(x, fx, GradientConverged, iter)
+		}
+		if p.exhausted() {
+			return p.result(x, fx, EvaluationLimit, iter)
+		}
+
+		// two-loop recursion for d = -H·pg
+		copy(d, pg)
+		for i := len(ss) - 1; i >= 0; i-- {
+			alpha[i] = rhos[i] * dot(ss[i], d)
+			axpy(-alpha[i], ys[i], d)
+		}
+		if k := len(ss) - 1; k >= 0 {
+			scale(dot(ss[k], ys[k])/dot(ys[k], ys[k]), d)
+		}
+		for i := range ss {
+			beta := rhos[i] * dot(ys[i], d)
+			axpy(alpha[i]-beta, ss[i], d)
+		}
+		scale(-1, d)
+		if l1 != nil {
+			// keep only the components that descend along the pseudo-gradient
+			for i := range d {
+				if d[i]*pg[i] >= 0 {
+					d[i] = 0
+				}
+			}
+		}
+		if dot(d, pg) >= 0 {
+			// the curvature history is useless here; restart from steepest descent
+			ss, ys, rhos = nil, nil, nil
+			copy(d, pg)
+			scale(-1, d)
+		}
+
+		// backtracking line search on the Armijo condition, which unlike a Wolfe search copes with
+		// the kinks of the L1 term
+		t := 1.0
+		if len(ss) == 0 {
+			t = math.Min(1, 1/math.Sqrt(dot(pg, pg)))
+		}
+		var fn float64
+		accepted := false
+		for ls := 0; ls < 50; ls++ {
+			for i := range xn {
+				xn[i] = x[i] + t*d[i]
+				if l1 != nil && l1[i] != 0 {
+					orthant := math.Copysign(1, x[i])
+					if x[i] == 0 {
+						orthant = -math.Copysign(1, pg[i])
+					}
+					if xn[i]*orthant <= 0 {
+						xn[i] = 0
+					}
+				}
+			}
+			fn = p.eval(xn, gn) + l1Norm(xn, l1)
+			var decrease float64
+			for i := range xn {
+				decrease += pg[i] * (xn[i] - x[i])
+			}
+			if fn <= fx+1e-4*decrease {
+				accepted = true
+				break
+			}
+			t /= 2
+		}
+		if !accepted {
+			// no further progress is possible at working precision
+			return p.result(x, fx, LineSearchFailed, iter+1)
+		}
+
+		sv, yv := make([]float64, n), make([]float64, n)
+		for i := range sv {
+			sv[i] = xn[i] - x[i]
+			yv[i] = gn[i] - g[i]
+		}
+		if sy := dot(sv, yv); sy > 1e-10 {
+			if len(ss) == memory {
+				ss, ys, rhos = ss[1:], ys[1:], rhos[1:]
+			}
+			ss, ys, rhos = append(ss, sv), append(ys, yv), append(rhos, 1/sy)
+		}
+		done := settled(fx, fn, s.FuncTol)
+		copy(x, xn)
+		copy(g, gn)
+		fx = fn
+		if done {
+			return p.result(x, fx, FunctionConverged, iter+1)
+		}
+	}
+	pseudoGradient(pg, x, g, l1)
+	if normInf(pg) <= s.GradTol {
+		return p.result(x, fx, GradientConverged, s.MaxIter)
+	}
+	return p.result(x, fx, IterationLimit, s.MaxIter)
+}
+
+// pseudoGradient writes the minimum-norm subgradient of f(x) + Σ l1[i]|x[i]| into pg, given the gradient g of f.
+func pseudoGradient(pg, x, g, l1 []float64) {
+	for i := range pg {
+		if l1 == nil || l1[i] == 0 {
+			pg[i] = g[i]
+			continue
+		}
+		switch {
+		case x[i] > 0:
+			pg[i] = g[i] + l1[i]
+		case x[i] < 0:
+			pg[i] = g[i] - l1[i]
+		case g[i]+l1[i] < 0:
+			pg[i] = g[i] + l1[i]
+		case g[i]-l1[i] > 0:
+			pg[i] = g[i] - l1[i]
+		default:
+			pg[i] = 0
+		}
+	}
+}
+
+func l1Norm(x, w []float64) float64 {
+	var s float64
+	for i := range w {
+		s += w[i] * math.Abs(x[i])
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+package optim
+
+import "math"
+
+// Wolfe is a line search for a step length satisfying the strong Wolfe conditions
+//
+//	f(x + αd) ≤ f(x) + C1·α·∇f(x)ᵀd
+//	|∇f(x + αd)ᵀd| ≤ C2·|∇f(x)ᵀd|
+//
+// with 0 < C1 < C2 < 1, by the bracketing and zoom of Nocedal and Wright (algorithms 3.5 and 3.6),
+// interpolating cubically within the bracket.
+type Wolfe struct {
+	C1, C2 float64
+}
+
+// LineResult is the outcome of a line search.
+type LineResult struct {
+	// Step is the accepted step length α, X the point x + αd and F and Grad the value and gradient of f there.
+	// OK reports whether such a step was found; it is false when d does not descend from x.
+	Step  float64
+	X     []float64
+	F     float64
+	Grad  []float64
+	OK    bool
+	Evals int
+}
+
+// maxLineEvals bounds the evaluations made by one line search.
+const maxLineEvals = 40
+
+// Search looks along d from x, where f has value fx and gradient g, starting from the step length step.
+func (w Wolfe) Search(f Func, x, d []float64, fx float64, g []float64, step float64) LineResult {
+	p := &problem{f: f}
+	xn, gn := make([]float64, len(x)), make([]float64, len(x))
+	r := w.search(p, x, d, fx, g, step, xn, gn)
+	r.Evals = p.evals
+	return r
+}
+
+// search is Search with the evaluations counted by p, writing the accepted point and its gradient into xn and gn.
+func (w Wolfe) search(p *problem, x, d []float64, fx float64, g []float64, step float64, xn, gn []float64) LineResult {
+	d0 := dot(g, d)
+	if !(d0 < 0) {
+		return LineResult{}
+	}
+	xt, gt := make([]float64, len(x)), make([]float64, len(x))
+	evals := 0
+	// phi evaluates f at x + a·d, returning its value and directional derivative
+	phi := func(a float64) (float64, float64) {
+		for i := range xt {
+			xt[i] = x[i] + a*d[i]
+		}
+		evals++
+		fa := p.eval(xt, gt)
+		return fa, dot(gt, d)
+	}
+	accept := func(a, fa float64) LineResult {
+		copy(xn, xt)
+		copy(gn, gt)
+		return LineResult{Step: a, X: xn, F: fa, Grad: gn, OK: true}
+	}
+
+	sufficient := func(a, fa float64) bool { return fa <= fx+w.C1*a*d0 }
+	curved := func(da float64) bool { return math.Abs(da) <= -w.C2*d0 }
+
+	// zoom narrows a bracket [lo, hi] known to contain acceptable steps, where lo is the end with the lower f
+	zoom := func(lo, flo, dlo, hi, fhi, dhi float64) LineResult {
+		for evals < maxLineEvals {
+			a := cubicMin(lo, flo, dlo, hi, fhi, dhi)
+			fa, da := phi(a)
+			if math.IsNaN(fa) {
+				return LineResult{}
+			}
+			if !sufficient(a, fa) || fa >= flo {
+				hi, fhi, dhi = a, fa, da
+				continue
+			}
+			if curved(da) {
+				return accept(a, fa)
+			}
+			if da*(hi-lo) >= 0 {
+				hi, fhi, dhi = lo, flo, dlo
+			}
+			lo, flo, dlo = a, fa, da
+		}
+		return LineResult{}
+	}
+
+	prev, fprev, dprev := 0.0, fx, d0
+	a := step
+	for first := true; evals < maxLineEvals; first = false {
+		fa, da := phi(a)
+		if math.IsNaN(fa) || math.IsInf(fa, 1) {
+			// stepped outside the domain of f; come back towards x
+			a = prev + (a-prev)/10
+			continue
+		}
+		if !sufficient(a, fa) || (!first && fa >= fprev) {
+			return zoom(prev, fprev, dprev, a, fa, da)
+		}
+		if curved(da) {
+			return accept(a, fa)
+		}
+		if da >= 0 {
+			return zoom(a, fa, da, prev, fprev, dprev)
+		}
+		prev, fprev, dprev = a, fa, da
+		a *= 2
+	}
+	return LineResult{}
+}
+
+// cubicMin returns the minimizer of the cubic interpolating the values and derivatives at a and b,
+// falling back to bisection when it is undefined or too close to either end.
+func cubicMin(a, fa, da, b, fb, db float64) float64 {
+	d1 := da + db - 3*(fa-fb)/(a-b)
+	disc := d1*d1 - da*db
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	mid := (a + b) / 2
+	if disc < 0 {
+		return mid
+	}
+	d2 := math.Copysign(math.Sqrt(disc), b-a)
+	t := b - (b-a)*(db+d2-d1)/(db-da+2*d2)
+	margin := (hi - lo) / 10
+	if math.IsNaN(t) || t < lo+margin || t > hi-margin {
+		return mid
+	}
+	return t
+}
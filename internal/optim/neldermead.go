@@ -0,0 +1,109 @@
+package optim
+
+import (
+	"math"
+	"sort"
+)
+
+// NelderMead is the downhill simplex method of Nelder and Mead, which needs no gradients. The initial simplex
+// adds Step·max(1, |x0[i]|) to each coordinate of x0 in turn; if Step is zero, 0.05 is used. Reflection,
+// expansion, contraction and shrinkage use the adaptive coefficients of Gao and Han, which keep the method
+// effective in many dimensions.
+type NelderMead struct {
+	Step float64
+}
+
+func (nm NelderMead) Minimize(f Func, x0 []float64, s Settings) Result {
+	p := &problem{f: f, max: s.MaxEval}
+	n := len(x0)
+	nf := float64(n)
+	alpha, gamma, rho, sigma := 1.0, 1+2/nf, 0.75-1/(2*nf), 1-1/nf
+	if n == 1 {
+		gamma, rho, sigma = 2, 0.5, 0.5
+	}
+	step := nm.Step
+	if step == 0 {
+		step = 0.05
+	}
+
+	type vertex struct {
+		x []float64
+		f float64
+	}
+	simplex := make([]vertex, n+1)
+	for k := range simplex {
+		x := append([]float64(nil), x0...)
+		if k > 0 {
+			x[k-1] += step * math.Max(1, math.Abs(x[k-1]))
+		}
+		simplex[k] = vertex{x, p.eval(x, nil)}
+	}
+	centroid := make([]float64, n)
+	// point returns centroid + t·(centroid - worst)
+	point := func(t float64) vertex {
+		worst := simplex[n].x
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = centroid[i] + t*(centroid[i]-worst[i])
+		}
+		return vertex{x, p.eval(x, nil)}
+	}
+
+	for iter := 0; iter < s.MaxIter; iter++ {
+		sort.SliceStable(simplex, func(a, b int) bool { return simplex[a].f < simplex[b].f })
+		best := simplex[0]
+		var spread, size float64
+		for _, v := range simplex[1:] {
+			spread = math.Max(spread, math.Abs(v.f-best.f))
+			for i := range v.x {
+				size = math.Max(size, math.Abs(v.x[i]-best.x[i]))
+			}
+		}
+		if spread <= s.FuncTol*math.Max(1, math.Abs(best.f)) && size <= s.StepTol*math.Max(1, normInf(best.x)) {
+			return p.result(best.x, best.f, StepConverged, iter)
+		}
+		if p.exhausted() {
+			return p.result(best.x, best.f, EvaluationLimit, iter)
+		}
+
+		for i := range centroid {
+			centroid[i] = 0
+		}
+		for _, v := range simplex[:n] {
+			axpy(1/nf, v.x, centroid)
+		}
+		r := point(alpha)
+		switch {
+		case r.f < best.f:
+			if e := point(alpha * gamma); e.f < r.f {
+				simplex[n] = e
+			} else {
+				simplex[n] = r
+			}
+		case r.f < simplex[n-1].f:
+			simplex[n] = r
+		default:
+			// contract outside the simplex if the reflection improved on the worst vertex, inside otherwise
+			var c vertex
+			if r.f < simplex[n].f {
+				c = point(alpha * rho)
+			} else {
+				c = point(-rho)
+			}
+			if c.f < math.Min(r.f, simplex[n].f) {
+				simplex[n] = c
+				break
+			}
+			for _, v := range simplex[1:] {
+				for i := range v.x {
+					v.x[i] = best.x[i] + sigma*(v.x[i]-best.x[i])
+				}
+			}
+			for k := 1; k <= n; k++ {
+				simplex[k].f = p.eval(simplex[k].x, nil)
+			}
+		}
+	}
+	sort.SliceStable(simplex, func(a, b int) bool { return simplex[a].f < simplex[b].f })
+	return p.result(simplex[0].x, simplex[0].f, IterationLimit, s.MaxIter)
+}
@@ -0,0 +1,118 @@
+// Package optim implements the numerical optimizers shared by the estimators of package pa and
+// exposed by package optimize. It works on flat parameter vectors so that pa can use it without
+// importing optimize, which depends on pa for its Matrix-based API.
+package optim
+
+import "math"
+
+// Func evaluates the function being minimized at x. If grad is not nil, it also writes the gradient at x into grad.
+type Func func(x, grad []float64) float64
+
+// Settings controls when a minimization stops. Methods use them as given; callers fill in defaults.
+type Settings struct {
+	// GradTol stops gradient-based methods once the largest component of the gradient is at most GradTol.
+	GradTol float64
+	// FuncTol stops a method once an iteration changes f by at most FuncTol·max(1, |f|). For Nelder–Mead it
+	// bounds the spread of f over the simplex instead.
+	FuncTol float64
+	// StepTol stops Nelder–Mead once every vertex of the simplex is within StepTol·max(1, ‖x‖∞) of the best one.
+	StepTol float64
+	// MaxIter bounds the number of iterations.
+	MaxIter int
+	// MaxEval bounds the number of evaluations of f; zero leaves them unbounded.
+	MaxEval int
+}
+
+// Status records why a minimization stopped.
+type Status int
+
+const (
+	// IterationLimit means MaxIter iterations were made without meeting a tolerance.
+	IterationLimit Status = iota
+	// GradientConverged means the gradient met GradTol.
+	GradientConverged
+	// FunctionConverged means the change in f met FuncTol.
+	FunctionConverged
+	// StepConverged means the Nelder–Mead simplex met both FuncTol and StepTol.
+	StepConverged
+	// EvaluationLimit means MaxEval evaluations were made without meeting a tolerance.
+	EvaluationLimit
+	// LineSearchFailed means no step along the search direction decreased f enough, which usually means
+	// the gradient is inaccurate or f cannot be decreased further at working precision.
+	LineSearchFailed
+)
+
+// Converged reports whether s means a tolerance was met.
+func (s Status) Converged() bool {
+	return s == GradientConverged || s == FunctionConverged || s == StepConverged
+}
+
+// Result is the outcome of a minimization.
+type Result struct {
+	// X is the best point found and F the value of f there.
+	X []float64
+	F float64
+	// Status records why the method stopped.
+	Status Status
+	// Iterations and Evaluations count the iterations made and the evaluations of f.
+	Iterations, Evaluations int
+}
+
+// Method is an algorithm that minimizes a Func.
+type Method interface {
+	Minimize(f Func, x0 []float64, s Settings) Result
+}
+
+// problem counts the evaluations of f.
+type problem struct {
+	f     Func
+	evals int
+	max   int
+}
+
+func (p *problem) eval(x, grad []float64) float64 {
+	p.evals++
+	return p.f(x, grad)
+}
+
+func (p *problem) exhausted() bool {
+	return p.max > 0 && p.evals >= p.max
+}
+
+func (p *problem) result(x []float64, fx float64, status Status, iters int) Result {
+	return Result{X: x, F: fx, Status: status, Iterations: iters, Evaluations: p.evals}
+}
+
+// settled reports whether the change from fx to fn is within tol relative to fx.
+func settled(fx, fn, tol float64) bool {
+	return math.Abs(fx-fn) <= tol*math.Max(1, math.Abs(fx))
+}
+
+func normInf(x []float64) float64 {
+	var m float64
+	for _, v := range x {
+		m = math.Max(m, math.Abs(v))
+	}
+	return m
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// axpy computes y += a·x.
+func axpy(a float64, x, y []float64) {
+	for i := range x {
+		y[i] += a * x[i]
+	}
+}
+
+func scale(a float64, x []float64) {
+	for i := range x {
+		x[i] *= a
+	}
+}
@@ -0,0 +1,164 @@
+package optim
+
+import (
+	"math"
+	"testing"
+)
+
+// rosenbrock is (1-a)² + 100(b-a²)² summed over consecutive pairs, minimized at all ones.
+func rosenbrock(x, grad []float64) float64 {
+	var f float64
+	for i := range grad {
+		grad[i] = 0
+	}
+	for i := 0; i+1 < len(x); i++ {
+		a, b := x[i], x[i+1]
+		f += (1-a)*(1-a) + 100*(b-a*a)*(b-a*a)
+		if grad != nil {
+			grad[i] += -2*(1-a) - 400*a*(b-a*a)
+			grad[i+1] += 200 * (b - a*a)
+		}
+	}
+	return f
+}
+
+// quadratic is Σ (i+1)(x[i] - i)²/2, minimized at x[i] = i.
+func quadratic(x, grad []float64) float64 {
+	var f float64
+	for i, v := range x {
+		d := v - float64(i)
+		f += float64(i+1) * d * d / 2
+		if grad != nil {
+			grad[i] = float64(i+1) * d
+		}
+	}
+	return f
+}
+
+func TestMinimize(t *testing.T) {
+	tests := []struct {
+		name   string
+		method Method
+		f      Func
+		x0     []float64
+		want   []float64
+		tol    float64
+		s      Settings
+	}{
+		{"gradient descent", &GradientDescent{Rate: 0.2}, quadratic, []float64{5, 5, 5}, []float64{0, 1, 2}, 1e-6,
+			Settings{GradTol: 1e-8, MaxIter: 1000}},
+		{"momentum", &Momentum{Rate: 0.05, Momentum: 0.9}, quadratic, []float64{5, 5, 5}, []float64{0, 1, 2}, 1e-6,
+			Settings{GradTol: 1e-8, MaxIter: 1000}},
+		{"nesterov", &Nesterov{Rate: 0.05, Momentum: 0.9}, quadratic, []float64{5, 5, 5}, []float64{0, 1, 2}, 1e-6,
+			Settings{GradTol: 1e-8, MaxIter: 1000}},
+		{"adam", &Adam{Rate: 0.05, Beta1: 0.9, Beta2: 0.999, Epsilon: 1e-8}, quadratic, []float64{5, 5, 5}, []float64{0, 1, 2}, 1e-4,
+			Settings{GradTol: 1e-6, MaxIter: 5000}},
+		{"lbfgs", &LBFGS{}, rosenbrock, []float64{-1.2, 1, -1.2, 1}, []float64{1, 1, 1, 1}, 1e-6,
+			Settings{GradTol: 1e-9, MaxIter: 1000}},
+		{"conjugate gradient", ConjugateGradient{}, rosenbrock, []float64{-1.2, 1}, []float64{1, 1}, 1e-6,
+			Settings{GradTol: 1e-9, MaxIter: 1000}},
+		{"conjugate gradient quadratic", ConjugateGradient{}, quadratic, []float64{5, 5, 5, 5}, []float64{0, 1, 2, 3}, 1e-8,
+			Settings{GradTol: 1e-10, MaxIter: 100}},
+		{"nelder-mead", NelderMead{}, rosenbrock, []float64{-1.2, 1}, []float64{1, 1}, 1e-4,
+			Settings{FuncTol: 1e-12, StepTol: 1e-8, MaxIter: 5000}},
+		{"nelder-mead quadratic", NelderMead{Step: 1}, quadratic, []float64{5, 5, 5, 5, 5}, []float64{0, 1, 2, 3, 4}, 1e-4,
+			Settings{FuncTol: 1e-12, StepTol: 1e-8, MaxIter: 5000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.method.Minimize(tt.f, tt.x0, tt.s)
+			if !r.Status.Converged() {
+				t.Errorf("Status = %d after %d iterations, want converged", r.Status, r.Iterations)
+			}
+			for i := range tt.want {
+				if math.Abs(r.X[i]-tt.want[i]) > tt.tol {
+					t.Fatalf("X = %v, want %v", r.X, tt.want)
+				}
+			}
+			if got := tt.f(r.X, make([]float64, len(r.X))); got != r.F {
+				t.Errorf("F = %v, want f(X) = %v", r.F, got)
+			}
+			if r.Evaluations < r.Iterations {
+				t.Errorf("Evaluations = %d, fewer than the %d iterations", r.Evaluations, r.Iterations)
+			}
+		})
+	}
+}
+
+func TestMinimizeLimits(t *testing.T) {
+	r := (&LBFGS{}).Minimize(rosenbrock, []float64{-1.2, 1}, Settings{GradTol: 1e-9, MaxIter: 3})
+	if r.Status != IterationLimit || r.Iterations != 3 {
+		t.Errorf("MaxIter 3: Status = %d after %d iterations, want IterationLimit after 3", r.Status, r.Iterations)
+	}
+	r = NelderMead{}.Minimize(rosenbrock, []float64{-1.2, 1}, Settings{MaxIter: 1000, MaxEval: 20})
+	if r.Status != EvaluationLimit || r.Evaluations < 20 || r.Evaluations > 25 {
+		t.Errorf("MaxEval 20: Status = %d after %d evaluations, want EvaluationLimit after about 20", r.Status, r.Evaluations)
+	}
+}
+
+func TestLBFGSL1(t *testing.T) {
+	// minimizing quadratic + |x|₁ soft-thresholds each coordinate: x[i] = max(0, i - 1/(i+1))
+	l1 := []float64{1, 1, 1, 1}
+	r := (&LBFGS{L1: l1}).Minimize(quadratic, []float64{0, 0, 0, 0}, Settings{GradTol: 1e-10, MaxIter: 100})
+	want := []float64{0, 0.5, 2 - 1.0/3, 3 - 0.25}
+	for i := range want {
+		if math.Abs(r.X[i]-want[i]) > 1e-8 {
+			t.Fatalf("X = %v, want %v", r.X, want)
+		}
+	}
+	if r.X[0] != 0 {
+		t.Errorf("X[0] = %v, want exactly 0", r.X[0])
+	}
+}
+
+func TestWolfe(t *testing.T) {
+	w := Wolfe{C1: 1e-4, C2: 0.9}
+	x := []float64{-1.2, 1}
+	g := make([]float64, 2)
+	fx := rosenbrock(x, g)
+	d := []float64{-g[0], -g[1]}
+	for _, step := range []float64{1e-6, 1e-3, 1, 100} {
+		r := w.Search(rosenbrock, x, d, fx, g, step)
+		if !r.OK {
+			t.Fatalf("Search() from step %v failed", step)
+		}
+		if r.F > fx+w.C1*r.Step*dot(g, d) {
+			t.Errorf("Search() from step %v: f = %v violates sufficient decrease", step, r.F)
+		}
+		if math.Abs(dot(r.Grad, d)) > -w.C2*dot(g, d) {
+			t.Errorf("Search() from step %v: slope %v violates the curvature condition", step, dot(r.Grad, d))
+		}
+		if r.Evals == 0 || r.Evals > maxLineEvals {
+			t.Errorf("Search() from step %v made %d evaluations", step, r.Evals)
+		}
+	}
+	if r := w.Search(rosenbrock, x, g, fx, g, 1); r.OK {
+		t.Error("Search() along an ascent direction succeeded")
+	}
+}
+
+func TestSteppers(t *testing.T) {
+	// one step from zero with gradient (1, -2)
+	tests := []struct {
+		name string
+		st   Stepper
+		want []float64
+	}{
+		{"gradient descent", &GradientDescent{Rate: 0.1}, []float64{-0.1, 0.2}},
+		{"momentum", &Momentum{Rate: 0.1, Momentum: 0.9}, []float64{-0.1, 0.2}},
+		{"nesterov", &Nesterov{Rate: 0.1, Momentum: 0.9}, []float64{-0.19, 0.38}},
+		// Adam's first step has the size of Rate in every coordinate
+		{"adam", &Adam{Rate: 0.1, Beta1: 0.9, Beta2: 0.999}, []float64{-0.1, 0.1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := []float64{0, 0}
+			tt.st.Step(x, []float64{1, -2})
+			for i := range x {
+				if math.Abs(x[i]-tt.want[i]) > 1e-12 {
+					t.Fatalf("Step() = %v, want %v", x, tt.want)
+				}
+			}
+		})
+	}
+}
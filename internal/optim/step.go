@@ -0,0 +1,116 @@
+package optim
+
+import "math"
+
+// Stepper updates parameters in place from their gradient, keeping whatever state it needs between steps.
+// Steppers drive stochastic training, where each gradient comes from a different minibatch, and, through
+// Minimize, full-batch first-order minimization.
+type Stepper interface {
+	Step(x, grad []float64)
+}
+
+// GradientDescent steps against the gradient, scaled by Rate.
+type GradientDescent struct {
+	Rate float64
+}
+
+func (gd *GradientDescent) Step(x, grad []float64) {
+	axpy(-gd.Rate, grad, x)
+}
+
+func (gd *GradientDescent) Minimize(f Func, x0 []float64, s Settings) Result {
+	return descend(f, x0, s, &GradientDescent{Rate: gd.Rate})
+}
+
+// Momentum steps along a velocity that accumulates past gradients, decaying by Momentum each step.
+type Momentum struct {
+	Rate, Momentum float64
+	velocity       []float64
+}
+
+func (m *Momentum) Step(x, grad []float64) {
+	if m.velocity == nil {
+		m.velocity = make([]float64, len(x))
+	}
+	for i, g := range grad {
+		m.velocity[i] = m.Momentum*m.velocity[i] - m.Rate*g
+		x[i] += m.velocity[i]
+	}
+}
+
+func (m *Momentum) Minimize(f Func, x0 []float64, s Settings) Result {
+	return descend(f, x0, s, &Momentum{Rate: m.Rate, Momentum: m.Momentum})
+}
+
+// Nesterov is momentum with Nesterov's accelerated gradient, which evaluates the gradient after the momentum
+// step rather than before it. It is written in the form of Bengio et al., so x holds the look-ahead point
+// and the gradient there is all it needs.
+type Nesterov struct {
+	Rate, Momentum float64
+	velocity       []float64
+}
+
+func (n *Nesterov) Step(x, grad []float64) {
+	if n.velocity == nil {
+		n.velocity = make([]float64, len(x))
+	}
+	for i, g := range grad {
+		n.velocity[i] = n.Momentum*n.velocity[i] - n.Rate*g
+		x[i] += n.Momentum*n.velocity[i] - n.Rate*g
+	}
+}
+
+func (n *Nesterov) Minimize(f Func, x0 []float64, s Settings) Result {
+	return descend(f, x0, s, &Nesterov{Rate: n.Rate, Momentum: n.Momentum})
+}
+
+// Adam adapts the step of each parameter from running estimates of the first and second moments of its
+// gradient (Kingma and Ba, 2015). Rate bounds the size of each step.
+type Adam struct {
+	Rate, Beta1, Beta2, Epsilon float64
+	m, v                        []float64
+	t                           int
+}
+
+func (a *Adam) Step(x, grad []float64) {
+	if a.m == nil {
+		a.m, a.v = make([]float64, len(x)), make([]float64, len(x))
+	}
+	a.t++
+	rate := a.Rate * math.Sqrt(1-math.Pow(a.Beta2, float64(a.t))) / (1 - math.Pow(a.Beta1, float64(a.t)))
+	for i, g := range grad {
+		a.m[i] = a.Beta1*a.m[i] + (1-a.Beta1)*g
+		a.v[i] = a.Beta2*a.v[i] + (1-a.Beta2)*g*g
+		x[i] -= rate * a.m[i] / (math.Sqrt(a.v[i]) + a.Epsilon)
+	}
+}
+
+func (a *Adam) Minimize(f Func, x0 []float64, s Settings) Result {
+	return descend(f, x0, s, &Adam{Rate: a.Rate, Beta1: a.Beta1, Beta2: a.Beta2, Epsilon: a.Epsilon})
+}
+
+// descend minimizes f by taking a step with st at each iteration, using the full gradient.
+func descend(f Func, x0 []float64, s Settings, st Stepper) Result {
+	p := &problem{f: f, max: s.MaxEval}
+	x := append([]float64(nil), x0...)
+	g := make([]float64, len(x))
+	fx := p.eval(x, g)
+	for iter := 0; iter < s.MaxIter; iter++ {
+		if normInf(g) <= s.GradTol {
+			return p.result(x, fx, GradientConverged, iter)
+		}
+		if p.exhausted() {
+			return p.result(x, fx, EvaluationLimit, iter)
+		}
+		st.Step(x, g)
+		fn := p.eval(x, g)
+		if settled(fx, fn, s.FuncTol) {
+			return p.result(x, fn, FunctionConverged, iter+1)
+		}
+		fx = fn
+	}
+	if normInf(g) <= s.GradTol {
+		return p.result(x, fx, GradientConverged, s.MaxIter)
+	}
+	return p.result(x, fx, IterationLimit, s.MaxIter)
+}
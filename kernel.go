@@ -0,0 +1,72 @@
+package pa
+
+import "math"
+
+// Kernel is a similarity function between two samples, given as rows of a Matrix.
+// A valid kernel is an inner product in some feature space, so kernel methods such as SVC
+// can fit non-linear boundaries while only ever solving a linear problem.
+type Kernel[T Number] interface {
+	Eval(x, y Array[T]) float64
+}
+
+// LinearKernel is the plain inner product x·y.
+type LinearKernel[T Number] struct{}
+
+func (LinearKernel[T]) Eval(x, y Array[T]) float64 {
+	d, _ := Dot[T](x, y)
+	return float64(d)
+}
+
+// PolyKernel is (Gamma·x·y + Coef0)^Degree.
+type PolyKernel[T Number] struct {
+	// Degree is the degree of the polynomial. If zero, 3 is used.
+	Degree int
+	// Gamma scales the inner product. If zero, 1/p is used for samples with p features.
+	Gamma float64
+	Coef0 float64
+}
+
+func (k PolyKernel[T]) Eval(x, y Array[T]) float64 {
+	d, _ := Dot[T](x, y)
+	deg := k.Degree
+	if deg == 0 {
+		deg = 3
+	}
+	return math.Pow(gamma(k.Gamma, len(x))*float64(d)+k.Coef0, float64(deg))
+}
+
+// RBFKernel is the Gaussian radial basis function exp(-Gamma‖x - y‖²).
+type RBFKernel[T Number] struct {
+	// Gamma is the inverse width of the kernel. If zero, 1/p is used for samples with p features.
+	Gamma float64
+}
+
+func (k RBFKernel[T]) Eval(x, y Array[T]) float64 {
+	var d float64
+	for i := range x {
+		v := float64(x[i]) - float64(y[i])
+		d += v * v
+	}
+	return math.Exp(-gamma(k.Gamma, len(x)) * d)
+}
+
+// SigmoidKernel is tanh(Gamma·x·y + Coef0). It is not positive definite for all parameters,
+// but often works well in practice.
+type SigmoidKernel[T Number] struct {
+	// Gamma scales the inner product. If zero, 1/p is used for samples with p features.
+	Gamma float64
+	Coef0 float64
+}
+
+func (k SigmoidKernel[T]) Eval(x, y Array[T]) float64 {
+	d, _ := Dot[T](x, y)
+	return math.Tanh(gamma(k.Gamma, len(x))*float64(d) + k.Coef0)
+}
+
+// gamma returns g, or 1/p if g is zero.
+func gamma(g float64, p int) float64 {
+	if g == 0 {
+		return 1 / float64(p)
+	}
+	return g
+}
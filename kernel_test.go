@@ -0,0 +1,32 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKernels(t *testing.T) {
+	x, y := Array[float64]{1, 2}, Array[float64]{3, -1}
+	tests := []struct {
+		name   string
+		kernel Kernel[float64]
+		want   float64
+	}{
+		{"linear", LinearKernel[float64]{}, 1},
+		{"poly", PolyKernel[float64]{Degree: 2, Gamma: 1, Coef0: 1}, 4},
+		{"poly defaults", PolyKernel[float64]{}, 0.125},
+		{"rbf", RBFKernel[float64]{Gamma: 0.1}, math.Exp(-1.3)},
+		{"rbf default gamma", RBFKernel[float64]{}, math.Exp(-6.5)},
+		{"sigmoid", SigmoidKernel[float64]{Gamma: 2, Coef0: -1}, math.Tanh(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kernel.Eval(x, y); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+			if a, b := tt.kernel.Eval(x, y), tt.kernel.Eval(y, x); a != b {
+				t.Errorf("Eval() is not symmetric: %v vs. %v", a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+)
+
+// NeighborSearch selects the index a nearest neighbour model searches.
+type NeighborSearch int
+
+const (
+	// SearchAuto uses a KDTree for Minkowski metrics on data with at most 15 features,
+	// a BallTree for them on wider data, and brute force for any other metric.
+	SearchAuto NeighborSearch = iota
+	// SearchBrute compares each query with every training sample.
+	SearchBrute
+	// SearchKDTree searches a KDTree.
+	SearchKDTree
+	// SearchBallTree searches a BallTree.
+	SearchBallTree
+)
+
+// NeighborWeights selects how the neighbours of a sample contribute to its prediction.
+type NeighborWeights int
+
+const (
+	// UniformWeights gives every neighbour an equal say.
+	UniformWeights NeighborWeights = iota
+	// DistanceWeights weighs each neighbour by the inverse of its distance. Neighbours at distance zero,
+	// if there are any, decide the prediction alone.
+	DistanceWeights
+)
+
+// knn holds the settings and index shared by the nearest neighbour models.
+type knn[T Number] struct {
+	k       int
+	weights NeighborWeights
+	index   NeighborIndex[T]
+}
+
+// fit validates the settings and indexes the rows of X.
+func (m *knn[T]) fit(X *Matrix[T], k int, weights NeighborWeights, metric Metric[T], search NeighborSearch) error {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if k == 0 {
+		k = 5
+	}
+	if k < 0 || k > X.rows {
+		return fmt.Errorf("K must be in [1, %d], got %d", X.rows, k)
+	}
+	if weights != UniformWeights && weights != DistanceWeights {
+		return fmt.Errorf("unknown weights %d", weights)
+	}
+	if metric == nil {
+		metric = EuclideanDistance[T]{}
+	}
+	if search == SearchAuto {
+		search = SearchBrute
+		if _, ok := metric.(minkowskiMetric); ok {
+			search = SearchKDTree
+			if X.cols > 15 {
+				search = SearchBallTree
+			}
+		}
+	}
+	switch search {
+	case SearchBrute:
+		t := newBruteForce(X, metric)
+		if t.err != nil {
+			return t.err
+		}
+		m.index = t
+	case SearchKDTree:
+		t := NewKDTree(X).WithMetric(metric)
+		if t.Err() != nil {
+			return t.Err()
+		}
+		m.index = t
+	case SearchBallTree:
+		t := NewBallTree(X).WithMetric(metric)
+		if t.Err() != nil {
+			return t.Err()
+		}
+		m.index = t
+	default:
+		return fmt.Errorf("unknown search %d", search)
+	}
+	m.k, m.weights = k, weights
+	return nil
+}
+
+// neighbors returns the neighbours of each row of X with the weight each one carries.
+func (m *knn[T]) neighbors(X *Matrix[T]) ([][]Neighbor, [][]float64, error) {
+	if m.index == nil {
+		return nil, nil, fmt.Errorf("model is not fitted")
+	}
+	nbrs, err := m.index.Query(X, m.k)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := make([][]float64, len(nbrs))
+	for i, ns := range nbrs {
+		w[i] = make([]float64, len(ns))
+		exact := ns[0].Distance == 0
+		for j, n := range ns {
+			switch {
+			case m.weights == UniformWeights:
+				w[i][j] = 1
+			case exact && n.Distance == 0:
+				w[i][j] = 1
+			case !exact:
+				w[i][j] = 1 / n.Distance
+			}
+		}
+	}
+	return nbrs, w, nil
+}
+
+// KNeighborsClassifier assigns each sample the class most common among its K nearest training samples.
+type KNeighborsClassifier[T Number] struct {
+	// K is the number of neighbours consulted. If zero, 5 is used.
+	K int
+	// Weights selects how neighbours are weighed. The zero value is UniformWeights.
+	Weights NeighborWeights
+	// Metric is the distance between samples. If nil, EuclideanDistance is used.
+	Metric Metric[T]
+	// Search selects the neighbour index. The zero value is SearchAuto.
+	Search NeighborSearch
+
+	knn[T]
+	classes []T
+	labels  []int
+}
+
+// Fit indexes the training samples in the rows of X, whose labels are in the column vector y.
+// The index refers to X, which must not be modified while the model is in use.
+func (kc *KNeighborsClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return fmt.Errorf("KNeighborsClassifier.Fit: y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	if err := kc.fit(X, kc.K, kc.Weights, kc.Metric, kc.Search); err != nil {
+		return fmt.Errorf("KNeighborsClassifier.Fit: %w", err)
+	}
+	kc.classes, kc.labels = encodeLabels(y)
+	return nil
+}
+
+// votes returns the weighted share of the neighbours of each row of X in each class.
+func (kc *KNeighborsClassifier[T]) votes(X *Matrix[T]) (*Matrix[float64], error) {
+	nbrs, w, err := kc.neighbors(X)
+	if err != nil {
+		return nil, err
+	}
+	votes := Empty[float64](len(nbrs), len(kc.classes))
+	for i, ns := range nbrs {
+		row := votes.row(i)
+		var total float64
+		for j, n := range ns {
+			row[kc.labels[n.Index]] += w[i][j]
+			total += w[i][j]
+		}
+		scale(1/total, row)
+	}
+	return votes, nil
+}
+
+// PredictProba returns the weighted share of the neighbours of each row of X in each class,
+// with columns in the order of Classes.
+func (kc *KNeighborsClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	votes, err := kc.votes(X)
+	if err != nil {
+		return nil, fmt.Errorf("KNeighborsClassifier.PredictProba: %w", err)
+	}
+	return convert[T](votes), nil
+}
+
+// Predict returns the class with the largest weighted vote for each row of X, breaking ties in favour of the smaller class.
+func (kc *KNeighborsClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	votes, err := kc.votes(X)
+	if err != nil {
+		return nil, fmt.Errorf("KNeighborsClassifier.Predict: %w", err)
+	}
+	yh := Empty[T](votes.rows, 1)
+	for i := 0; i < votes.rows; i++ {
+		row, best := votes.row(i), 0
+		for c, v := range row {
+			if v > row[best] {
+				best = c
+			}
+		}
+		yh.set(i, 0, kc.classes[best])
+	}
+	return yh, nil
+}
+
+func (kc *KNeighborsClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := kc.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (kc *KNeighborsClassifier[T]) Classes() []T {
+	return kc.classes
+}
+
+// Neighbors returns the K nearest training samples to each row of X, nearest first.
+func (kc *KNeighborsClassifier[T]) Neighbors(X *Matrix[T]) ([][]Neighbor, error) {
+	nbrs, _, err := kc.neighbors(X)
+	return nbrs, err
+}
+
+// KNeighborsRegressor predicts the (weighted) mean target of the K nearest training samples.
+// y may have several columns, which are averaged independently.
+type KNeighborsRegressor[T Number] struct {
+	// K is the number of neighbours consulted. If zero, 5 is used.
+	K int
+	// Weights selects how neighbours are weighed. The zero value is UniformWeights.
+	Weights NeighborWeights
+	// Metric is the distance between samples. If nil, EuclideanDistance is used.
+	Metric Metric[T]
+	// Search selects the neighbour index. The zero value is SearchAuto.
+	Search NeighborSearch
+
+	knn[T]
+	y *Matrix[T]
+}
+
+// Fit indexes the training samples in the rows of X, whose targets are the rows of y.
+// The model refers to X and y, which must not be modified while it is in use.
+func (kr *KNeighborsRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if y.rows != X.rows {
+		return fmt.Errorf("KNeighborsRegressor.Fit: y has %d rows, X has %d", y.rows, X.rows)
+	}
+	if err := kr.fit(X, kr.K, kr.Weights, kr.Metric, kr.Search); err != nil {
+		return fmt.Errorf("KNeighborsRegressor.Fit: %w", err)
+	}
+	kr.y = y
+	return nil
+}
+
+func (kr *KNeighborsRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	nbrs, w, err := kr.neighbors(X)
+	if err != nil {
+		return nil, fmt.Errorf("KNeighborsRegressor.Predict: %w", err)
+	}
+	yh := Empty[T](len(nbrs), kr.y.cols)
+	acc := make([]float64, kr.y.cols)
+	for i, ns := range nbrs {
+		for c := range acc {
+			acc[c] = 0
+		}
+		var total float64
+		for j, n := range ns {
+			for c, v := range kr.y.row(n.Index) {
+				acc[c] += w[i][j] * float64(v)
+			}
+			total += w[i][j]
+		}
+		for c, v := range acc {
+			yh.set(i, c, fromFloat[T](v/total))
+		}
+	}
+	return yh, nil
+}
+
+func (kr *KNeighborsRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := kr.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// Neighbors returns the K nearest training samples to each row of X, nearest first.
+func (kr *KNeighborsRegressor[T]) Neighbors(X *Matrix[T]) ([][]Neighbor, error) {
+	nbrs, _, err := kr.neighbors(X)
+	return nbrs, err
+}
+
+// fromFloat converts v to T, rounding to the nearest integer for integer types.
+func fromFloat[T Number](v float64) T {
+	if epsilon[T]() == 0 {
+		return T(math.Round(v))
+	}
+	return T(v)
+}
@@ -0,0 +1,130 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKNeighborsClassifier(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {5, 0}, {0, 5}}, 30)
+	tests := []struct {
+		name string
+		kc   *KNeighborsClassifier[float64]
+	}{
+		{"default", &KNeighborsClassifier[float64]{}},
+		{"brute distance", &KNeighborsClassifier[float64]{Search: SearchBrute, Weights: DistanceWeights}},
+		{"kd-tree manhattan", &KNeighborsClassifier[float64]{Search: SearchKDTree, Metric: ManhattanDistance[float64]{}}},
+		{"ball tree minkowski", &KNeighborsClassifier[float64]{K: 9, Search: SearchBallTree, Metric: MinkowskiDistance[float64]{P: 3}}},
+		{"cosine", &KNeighborsClassifier[float64]{Metric: CosineDistance[float64]{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.kc.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := tt.kc.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.9 {
+				t.Errorf("Score() = %v, want at least 0.9", acc)
+			}
+			proba, err := tt.kc.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+		})
+	}
+
+	// with distance weights every training sample is its own prediction
+	kc := &KNeighborsClassifier[float64]{Weights: DistanceWeights}
+	if err := kc.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if acc, _ := kc.Score(X, y); acc != 1 {
+		t.Errorf("distance-weighted Score() on the training data = %v, want 1", acc)
+	}
+	if err := (&KNeighborsClassifier[float64]{Search: SearchKDTree, Metric: CosineDistance[float64]{}}).Fit(X, y); err == nil {
+		t.Error("Fit() of a KD-tree with CosineDistance returned nil error")
+	}
+}
+
+func TestKNeighborsRegressor(t *testing.T) {
+	X := NewMatrix([][]float64{{0}, {1}, {2}, {3}, {10}}, nil)
+	y := NewMatrix([][]float64{{0}, {10}, {20}, {30}, {100}}, nil)
+	tests := []struct {
+		name    string
+		weights NeighborWeights
+		want    float64
+	}{
+		// the two nearest neighbours of 1.5 are 1 and 2, and of 0.5 are 0 and 1
+		{"uniform", UniformWeights, 5},
+		{"distance", DistanceWeights, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kr := &KNeighborsRegressor[float64]{K: 2, Weights: tt.weights}
+			if err := kr.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			yh, err := kr.Predict(NewMatrix([][]float64{{0.5}, {1.75}}, nil))
+			if err != nil {
+				t.Fatalf("Predict() error: %v", err)
+			}
+			if got := yh.at(0, 0); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Predict(0.5) = %v, want %v", got, tt.want)
+			}
+			want := 15.0
+			if tt.weights == DistanceWeights {
+				// weights 1/0.75 and 1/0.25 on targets 10 and 20
+				want = (10/0.75 + 20/0.25) / (1/0.75 + 1/0.25)
+			}
+			if got := yh.at(1, 0); math.Abs(got-want) > 1e-12 {
+				t.Errorf("Predict(1.75) = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// integer targets are rounded rather than truncated
+	kr := &KNeighborsRegressor[int]{K: 2}
+	if err := kr.Fit(NewMatrix([][]int{{0}, {1}, {5}}, nil), NewMatrix([][]int{{1}, {2}, {9}}, nil)); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if yh, _ := kr.Predict(NewMatrix([][]int{{0}}, nil)); yh.at(0, 0) != 2 {
+		t.Errorf("Predict() = %d, want 2", yh.at(0, 0))
+	}
+}
+
+func TestKNeighborsRegressorScoreColumns(t *testing.T) {
+	// the first target is smooth in x, the second alternates sign from sample to sample,
+	// so averaging two neighbours fits the first closely and cancels the second out
+	X, y := Empty[float64](40, 1), Empty[float64](40, 2)
+	for i := 0; i < 40; i++ {
+		X.set(i, 0, float64(i))
+		y.set(i, 0, float64(i))
+		y.set(i, 1, float64(1-2*(i%2)))
+	}
+	kr := &KNeighborsRegressor[float64]{K: 2}
+	if err := kr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	Xq := X.Apply(func(v float64) float64 { return v + 0.5 })
+	yh, _ := kr.Predict(Xq)
+	smooth := r2Score(y.Slice(0, 40, 0, 1), yh.Slice(0, 40, 0, 1))
+	noise := r2Score(y.Slice(0, 40, 1, 2), yh.Slice(0, 40, 1, 2))
+	if smooth < 0.99 || noise > 0.1 {
+		t.Fatalf("per-column R² = %v, %v, want near 1 and at most 0.1", smooth, noise)
+	}
+	score, err := kr.Score(Xq, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if want := (smooth + noise) / 2; math.Abs(score-want) > 1e-12 {
+		t.Errorf("Score() = %v, want the mean per-column R² %v", score, want)
+	}
+}
@@ -0,0 +1,239 @@
+package pa
+
+import "fmt"
+
+const (
+	defaultTolerance = 1e-4
+	defaultMaxIter   = 1000
+)
+
+// ElasticNet is linear regression with combined L1 and L2 penalties, minimising
+//
+//	‖y - Xb‖²/2n + Alpha·L1Ratio‖b‖₁ + Alpha·(1 - L1Ratio)‖b‖²/2
+//
+// by cyclic coordinate descent. The intercept is not penalised.
+type ElasticNet[T Float] struct {
+	// Alpha is the overall strength of the penalty.
+	Alpha T
+	// L1Ratio mixes the penalties: 1 is the lasso, 0 is ridge regression.
+	L1Ratio T
+	// Tol stops descent once no coefficient moves by more than Tol times the largest coefficient.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the coefficients. If zero, 1000 is used.
+	MaxIter int
+	// WarmStart starts Fit from the previous solution instead of zero, which makes fitting
+	// a regularization path over a decreasing sequence of alphas much cheaper.
+	WarmStart bool
+
+	bhat  *Matrix[T]
+	iters int
+}
+
+func (en *ElasticNet[T]) Fit(X, y *Matrix[T]) (err error) {
+	if en.L1Ratio < 0 || en.L1Ratio > 1 {
+		return fmt.Errorf("ElasticNet.Fit: L1Ratio must be in [0, 1], got %v", en.L1Ratio)
+	}
+	var warm *Matrix[T]
+	if en.WarmStart {
+		warm = en.bhat
+	}
+	en.bhat, en.iters, err = coordinateDescent(X, y, en.Alpha, en.L1Ratio, en.Tol, en.MaxIter, warm)
+	if err != nil {
+		return fmt.Errorf("ElasticNet.Fit: %w", err)
+	}
+	return nil
+}
+
+func (en *ElasticNet[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	yh := withIntercept(X).Mul(en.bhat)
+	if yh.Err() != nil {
+		return nil, yh.Err()
+	}
+	return yh, nil
+}
+
+// Coefficients returns the fitted coefficients, with the intercept first.
+// Coefficients eliminated by the L1 penalty are exactly zero.
+func (en *ElasticNet[T]) Coefficients() *Matrix[T] {
+	return en.bhat
+}
+
+// Iterations returns the number of passes over the coefficients made by the last call to Fit.
+func (en *ElasticNet[T]) Iterations() int {
+	return en.iters
+}
+
+func (en *ElasticNet[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := en.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// Lasso is linear regression with an L1 penalty, minimising ‖y - Xb‖²/2n + Alpha‖b‖₁
+// by cyclic coordinate descent. The penalty drives uninformative coefficients to exactly zero,
+// so the fit doubles as feature selection. The intercept is not penalised.
+type Lasso[T Float] struct {
+	// Alpha is the strength of the penalty.
+	Alpha T
+	// Tol stops descent once no coefficient moves by more than Tol times the largest coefficient.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the coefficients. If zero, 1000 is used.
+	MaxIter int
+	// WarmStart starts Fit from the previous solution instead of zero.
+	WarmStart bool
+
+	bhat  *Matrix[T]
+	iters int
+}
+
+func (l *Lasso[T]) Fit(X, y *Matrix[T]) (err error) {
+	var warm *Matrix[T]
+	if l.WarmStart {
+		warm = l.bhat
+	}
+	l.bhat, l.iters, err = coordinateDescent(X, y, l.Alpha, 1, l.Tol, l.MaxIter, warm)
+	if err != nil {
+		return fmt.Errorf("Lasso.Fit: %w", err)
+	}
+	return nil
+}
+
+func (l *Lasso[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	yh := withIntercept(X).Mul(l.bhat)
+	if yh.Err() != nil {
+		return nil, yh.Err()
+	}
+	return yh, nil
+}
+
+// Coefficients returns the fitted coefficients, with the intercept first.
+// Coefficients eliminated by the penalty are exactly zero.
+func (l *Lasso[T]) Coefficients() *Matrix[T] {
+	return l.bhat
+}
+
+// Iterations returns the number of passes over the coefficients made by the last call to Fit.
+func (l *Lasso[T]) Iterations() int {
+	return l.iters
+}
+
+func (l *Lasso[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := l.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// coordinateDescent fits the elastic net objective to X and the column vector y.
+// It returns the coefficients with the intercept first and the number of passes made.
+// If warm has the right shape, descent starts from it. If descent does not converge within maxIter
+// passes, the last iterate is returned along with an error wrapping ErrNoConvergence.
+func coordinateDescent[T Float](X, y *Matrix[T], alpha, l1Ratio T, tol float64, maxIter int, warm *Matrix[T]) (*Matrix[T], int, error) {
+	if X.Err() != nil {
+		return nil, 0, X.Err()
+	}
+	if y.Err() != nil {
+		return nil, 0, y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return nil, 0, fmt.Errorf("y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	if alpha < 0 {
+		return nil, 0, fmt.Errorf("Alpha must not be negative, got %v", alpha)
+	}
+	if tol == 0 {
+		tol = defaultTolerance
+	}
+	if maxIter == 0 {
+		maxIter = defaultMaxIter
+	}
+
+	// centre X and y so the unpenalised intercept drops out; xt holds the centred columns as rows
+	xt := X.T().Clone()
+	xbar := make([]T, p)
+	norms := make([]T, p)
+	for j := 0; j < p; j++ {
+		col := xt.row(j)
+		xbar[j] = mean(col)
+		for i := range col {
+			col[i] -= xbar[j]
+		}
+		norms[j] = sumSquares(col)
+	}
+	ybar := y.Mean(Column).at(0, 0)
+
+	b := make([]T, p)
+	if warm != nil && warm.rows == p+1 && warm.cols == 1 {
+		for j := range b {
+			b[j] = warm.at(j+1, 0)
+		}
+	}
+	resid := make([]T, n)
+	for i := range resid {
+		resid[i] = y.at(i, 0) - ybar
+	}
+	for j, bj := range b {
+		if bj != 0 {
+			for i, x := range xt.row(j) {
+				resid[i] -= x * bj
+			}
+		}
+	}
+
+	nt := T(n)
+	l1, l2 := nt*alpha*l1Ratio, nt*alpha*(1-l1Ratio)
+	iter, converged := 0, false
+	for iter < maxIter && !converged {
+		iter++
+		var maxDelta, maxCoef T
+		for j := 0; j < p; j++ {
+			if norms[j] == 0 {
+				continue
+			}
+			col, old := xt.row(j), b[j]
+			// correlation of column j with the partial residual that excludes it
+			rho := norms[j] * old
+			for i, x := range col {
+				rho += x * resid[i]
+			}
+			b[j] = softThreshold(rho, l1) / (norms[j] + l2)
+			if d := b[j] - old; d != 0 {
+				for i, x := range col {
+					resid[i] -= x * d
+				}
+				maxDelta = maxT(maxDelta, abs(d))
+			}
+			maxCoef = maxT(maxCoef, abs(b[j]))
+		}
+		converged = float64(maxDelta) <= tol*float64(maxCoef)
+	}
+
+	bhat := Empty[T](p+1, 1)
+	intercept := ybar
+	for j, bj := range b {
+		bhat.set(j+1, 0, bj)
+		intercept -= xbar[j] * bj
+	}
+	bhat.set(0, 0, intercept)
+	if !converged {
+		return bhat, iter, fmt.Errorf("%w after %d iterations", ErrNoConvergence, iter)
+	}
+	return bhat, iter, nil
+}
+
+// softThreshold shrinks x towards zero by t, returning zero if |x| <= t.
+func softThreshold[T Float](x, t T) T {
+	switch {
+	case x > t:
+		return x - t
+	case x < -t:
+		return x + t
+	}
+	return 0
+}
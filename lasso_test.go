@@ -0,0 +1,93 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLasso(t *testing.T) {
+	// one centred feature: the slope is soft(Σxy, nα)/Σx² = soft(5, 3)/2 = 1, the intercept mean(y) = 3
+	X := NewMatrix([][]float64{{-1}, {0}, {1}}, nil)
+	y := NewMatrix([][]float64{{1}, {2}, {6}}, nil)
+	l := &Lasso[float64]{Alpha: 1}
+	if err := l.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if want := NewMatrix([][]float64{{3}, {1}}, nil); !approxEqual(l.Coefficients(), want, 1e-9) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", l.Coefficients(), want)
+	}
+
+	// with elastic net mixing, the ridge part also divides: soft(5, 3)/(2 + 3) = 0.4
+	en := &ElasticNet[float64]{Alpha: 2, L1Ratio: 0.5}
+	if err := en.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if want := NewMatrix([][]float64{{3}, {0.4}}, nil); !approxEqual(en.Coefficients(), want, 1e-9) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", en.Coefficients(), want)
+	}
+}
+
+// sparseProblem returns data where y depends on only the first two of five correlated features.
+func sparseProblem() (*Matrix[float64], *Matrix[float64]) {
+	var rows, ys [][]float64
+	for i := 0; i < 50; i++ {
+		x := make([]float64, 5)
+		common := math.Cos(float64(i))
+		for j := range x {
+			x[j] = math.Sin(float64(i*(j+1))+float64(j)) + common
+		}
+		rows = append(rows, x)
+		ys = append(ys, []float64{1 + 3*x[0] - 2*x[1]})
+	}
+	return NewMatrix(rows, nil), NewMatrix(ys, nil)
+}
+
+func TestLassoSparse(t *testing.T) {
+	X, y := sparseProblem()
+	l := &Lasso[float64]{Alpha: 0.05, Tol: 1e-8}
+	if err := l.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	b := l.Coefficients()
+	for j := 3; j < 6; j++ {
+		if b.at(j, 0) != 0 {
+			t.Errorf("coefficient %d = %v, want exactly 0", j, b.at(j, 0))
+		}
+	}
+	if b.at(1, 0) < 2.5 || b.at(2, 0) > -1.5 {
+		t.Errorf("informative coefficients shrunk too far: %v, %v", b.at(1, 0), b.at(2, 0))
+	}
+
+	// with a negligible penalty the lasso recovers least squares
+	l = &Lasso[float64]{Alpha: 1e-10, Tol: 1e-12, MaxIter: 100000}
+	if err := l.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	want := NewMatrix([][]float64{{1}, {3}, {-2}, {0}, {0}, {0}}, nil)
+	if !approxEqual(l.Coefficients(), want, 1e-6) {
+		t.Errorf("\nCoefficients():\n%s\nwant:\n%s", l.Coefficients(), want)
+	}
+}
+
+func TestLassoWarmStart(t *testing.T) {
+	X, y := sparseProblem()
+	cold, warm := &Lasso[float64]{Tol: 1e-8}, &Lasso[float64]{Tol: 1e-8, WarmStart: true}
+	var coldIters, warmIters int
+	for _, alpha := range []float64{1, 0.5, 0.2, 0.1, 0.05, 0.02, 0.01} {
+		cold.Alpha, warm.Alpha = alpha, alpha
+		if err := cold.Fit(X, y); err != nil {
+			t.Fatalf("cold Fit() error: %v", err)
+		}
+		if err := warm.Fit(X, y); err != nil {
+			t.Fatalf("warm Fit() error: %v", err)
+		}
+		if !approxEqual(warm.Coefficients(), cold.Coefficients(), 1e-6) {
+			t.Errorf("alpha %v: warm and cold starts disagree:\n%s\n%s", alpha, warm.Coefficients(), cold.Coefficients())
+		}
+		coldIters += cold.Iterations()
+		warmIters += warm.Iterations()
+	}
+	if warmIters >= coldIters {
+		t.Errorf("warm start took %d iterations along the path, cold start %d", warmIters, coldIters)
+	}
+}
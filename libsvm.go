@@ -0,0 +1,82 @@
+package pa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadLibSVM reads samples in the sparse libsvm text format, one per line: a label followed by
+// index:value pairs with 1-based feature indices. Features absent from a line are zero, and X has as
+// many columns as the largest index. Blank lines and comments starting with # are skipped.
+func ReadLibSVM(r io.Reader) (X, y *Matrix[float64], err error) {
+	var rows [][]float64
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		label, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ReadLibSVM: line %d: %w", line, err)
+		}
+		row := []float64{label}
+		for _, f := range fields[1:] {
+			index, value, ok := strings.Cut(f, ":")
+			if !ok {
+				return nil, nil, fmt.Errorf("ReadLibSVM: line %d: %q is not index:value", line, f)
+			}
+			if index == "qid" {
+				continue
+			}
+			j, err := strconv.Atoi(index)
+			if err != nil || j < 1 {
+				return nil, nil, fmt.Errorf("ReadLibSVM: line %d: bad feature index %q", line, index)
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ReadLibSVM: line %d: %w", line, err)
+			}
+			for len(row) <= j {
+				row = append(row, 0)
+			}
+			row[j] = v
+		}
+		rows = append(rows, row)
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, fmt.Errorf("ReadLibSVM: %w", err)
+	}
+	X, y = FromLibSVM(rows)
+	return X, y, nil
+}
+
+// FromLibSVM splits rows laid out as xgboost.DMatrixFromLibSVM returns them, each a label followed by
+// the features at their 1-based libsvm indices, into a matrix of features and a column vector of labels.
+// Rows may have different lengths; features past the end of a row are zero. Empty rows are skipped.
+func FromLibSVM(rows [][]float64) (X, y *Matrix[float64]) {
+	var n, p int
+	for _, row := range rows {
+		if len(row) > 0 {
+			n++
+			if len(row)-1 > p {
+				p = len(row) - 1
+			}
+		}
+	}
+	X, y = Empty[float64](n, p), Empty[float64](n, 1)
+	i := 0
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		y.set(i, 0, row[0])
+		copy(X.row(i), row[1:])
+		i++
+	}
+	return X, y
+}
@@ -0,0 +1,59 @@
+package pa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLibSVM(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		X, y    [][]float64
+		wantErr bool
+	}{
+		{
+			name:  "sparse",
+			input: "0 1:5 3:27.1\n1 2:5 4:98\n",
+			X:     [][]float64{{5, 0, 27.1, 0}, {0, 5, 0, 98}},
+			y:     [][]float64{{0}, {1}},
+		},
+		{
+			name:  "comments, blank lines and qid",
+			input: "# header\n\n-1 qid:3 2:1.5 # trailing\n",
+			X:     [][]float64{{0, 1.5}},
+			y:     [][]float64{{-1}},
+		},
+		{name: "bad label", input: "x 1:1\n", wantErr: true},
+		{name: "bad pair", input: "1 1\n", wantErr: true},
+		{name: "zero index", input: "1 0:1\n", wantErr: true},
+		{name: "bad value", input: "1 1:x\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y, err := ReadLibSVM(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadLibSVM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(X, NewMatrix(tt.X, nil)) || !reflect.DeepEqual(y, NewMatrix(tt.y, nil)) {
+				t.Errorf("ReadLibSVM() = %v, %v, want %v, %v", X, y, tt.X, tt.y)
+			}
+		})
+	}
+}
+
+func TestFromLibSVM(t *testing.T) {
+	// what xgboost.DMatrixFromLibSVM returns for "0 1:5 5:27.1\n1 2:5 3:98\n", with an empty row added
+	rows := [][]float64{{0, 5, 0, 0, 0, 27.1}, {}, {1, 0, 5, 98}}
+	X, y := FromLibSVM(rows)
+	if want := NewMatrix([][]float64{{5, 0, 0, 0, 27.1}, {0, 5, 98, 0, 0}}, nil); !reflect.DeepEqual(X, want) {
+		t.Errorf("FromLibSVM() X = %v, want %v", X, want)
+	}
+	if want := NewMatrix([][]float64{{0}, {1}}, nil); !reflect.DeepEqual(y, want) {
+		t.Errorf("FromLibSVM() y = %v, want %v", y, want)
+	}
+}
@@ -0,0 +1,278 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kipukun/pa/internal/optim"
+)
+
+// LogisticRegression is a linear classifier fitted by maximum likelihood.
+// With two classes it models P(y = classes[1]) as the sigmoid of a linear function of X;
+// with more it fits a multinomial (softmax) model. The mean log loss plus the penalty
+// is minimised by L-BFGS, or by its orthant-wise variant OWL-QN under PenaltyL1.
+// The intercepts are not penalised.
+type LogisticRegression[T Float] struct {
+	// Penalty selects the regularization term. The zero value is PenaltyL2.
+	Penalty Penalty
+	// Alpha is the strength of the penalty; zero fits an unpenalised model.
+	Alpha T
+	// Tol stops fitting once no component of the gradient exceeds Tol. If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of L-BFGS iterations. If zero, 1000 is used.
+	MaxIter int
+
+	classes []T
+	coef    *Matrix[T]
+	iters   int
+}
+
+// Fit fits the model to the samples in the rows of X, whose labels are in the column vector y.
+func (lr *LogisticRegression[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("LogisticRegression.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	if lr.Alpha < 0 {
+		return fmt.Errorf("LogisticRegression.Fit: Alpha must not be negative, got %v", lr.Alpha)
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("LogisticRegression.Fit: need samples of at least two classes, got %d", len(classes))
+	}
+	tol, maxIter := lr.Tol, lr.MaxIter
+	if tol == 0 {
+		tol = defaultTolerance
+	}
+	if maxIter == 0 {
+		maxIter = defaultMaxIter
+	}
+
+	xa := convert[float64](withIntercept(X))
+	k := len(classes)
+	if k == 2 {
+		k = 1
+	}
+	alpha := float64(lr.Alpha)
+	var l2 float64
+	var l1 []float64
+	switch lr.Penalty {
+	case PenaltyL2:
+		l2 = alpha
+	case PenaltyL1:
+		l1 = make([]float64, (p+1)*k)
+		for i := k; i < len(l1); i++ {
+			l1[i] = alpha
+		}
+	default:
+		return fmt.Errorf("LogisticRegression.Fit: unsupported penalty %d", lr.Penalty)
+	}
+
+	f := logLoss(xa, labels, k, l2)
+	res := (&optim.LBFGS{L1: l1}).Minimize(f, make([]float64, (p+1)*k), optim.Settings{GradTol: tol, FuncTol: 1e-15, MaxIter: maxIter})
+	lr.classes = classes
+	lr.coef = convert[T](newMatrix(p+1, k, res.X, nil))
+	lr.iters = res.Iterations
+	if !res.Status.Converged() {
+		return fmt.Errorf("LogisticRegression.Fit: %w after %d iterations", ErrNoConvergence, res.Iterations)
+	}
+	return nil
+}
+
+// logLoss returns the mean log loss of the linear model with (p+1) x k weights on xa, plus l2‖w‖²/2
+// over all but the intercept row. With k = 1 the model is binary and labels are 0 or 1;
+// otherwise it is multinomial over k classes.
+func logLoss(xa *Matrix[float64], labels []int, k int, l2 float64) optim.Func {
+	n, p1 := xa.Size()
+	z := make([]float64, k)
+	return func(w, grad []float64) float64 {
+		for i := range grad {
+			grad[i] = 0
+		}
+		var loss float64
+		for i := 0; i < n; i++ {
+			row := xa.row(i)
+			for c := range z {
+				z[c] = 0
+			}
+			for j, x := range row {
+				for c := range z {
+					z[c] += x * w[j*k+c]
+				}
+			}
+			if k == 1 {
+				y := float64(labels[i])
+				loss += softplus(z[0]) - y*z[0]
+				z[0] = sigmoid(z[0]) - y
+			} else {
+				lse := logSumExp(z)
+				loss += lse - z[labels[i]]
+				for c := range z {
+					z[c] = math.Exp(z[c] - lse)
+				}
+				z[labels[i]]--
+			}
+			// z now holds the residuals ∂loss/∂z
+			for j, x := range row {
+				for c, r := range z {
+					grad[j*k+c] += r * x
+				}
+			}
+		}
+		nf := float64(n)
+		loss /= nf
+		scale(1/nf, grad)
+		for i := k; i < p1*k; i++ {
+			loss += l2 * w[i] * w[i] / 2
+			grad[i] += l2 * w[i]
+		}
+		return loss
+	}
+}
+
+// PredictProba returns the n x k matrix of class probabilities for the rows of X,
+// with columns in the order of Classes.
+func (lr *LogisticRegression[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	z := withIntercept(X).Mul(lr.coef)
+	if z.Err() != nil {
+		return nil, z.Err()
+	}
+	n := z.rows
+	proba := Empty[T](n, len(lr.classes))
+	zf := make([]float64, z.cols)
+	for i := 0; i < n; i++ {
+		out := proba.row(i)
+		if z.cols == 1 {
+			p := sigmoid(float64(z.at(i, 0)))
+			out[0], out[1] = T(1-p), T(p)
+			continue
+		}
+		for c, v := range z.row(i) {
+			zf[c] = float64(v)
+		}
+		lse := logSumExp(zf)
+		for c, v := range zf {
+			out[c] = T(math.Exp(v - lse))
+		}
+	}
+	return proba, nil
+}
+
+// Predict returns the most probable class of each row of X as a column vector.
+func (lr *LogisticRegression[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	proba, err := lr.PredictProba(X)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLabels(proba, lr.classes), nil
+}
+
+// Score returns the mean accuracy of the predictions for X against y.
+func (lr *LogisticRegression[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := lr.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+// Classes returns the distinct labels seen by Fit, in ascending order.
+func (lr *LogisticRegression[T]) Classes() []T {
+	return lr.classes
+}
+
+// Coefficients returns the fitted (p+1) x k weights, with the intercepts in the first row.
+// For a binary problem k is 1 and the weights give the log-odds of the second class.
+func (lr *LogisticRegression[T]) Coefficients() *Matrix[T] {
+	return lr.coef
+}
+
+// Iterations returns the number of optimizer iterations made by the last call to Fit.
+func (lr *LogisticRegression[T]) Iterations() int {
+	return lr.iters
+}
+
+// encodeLabels returns the distinct values of the column vector y in ascending order,
+// and the index into them of each element of y.
+func encodeLabels[T Number](y *Matrix[T]) ([]T, []int) {
+	index := map[T]int{}
+	for i := 0; i < y.rows; i++ {
+		index[y.at(i, 0)] = 0
+	}
+	classes := make([]T, 0, len(index))
+	for c := range index {
+		classes = append(classes, c)
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i] < classes[j] })
+	for i, c := range classes {
+		index[c] = i
+	}
+	labels := make([]int, y.rows)
+	for i := range labels {
+		labels[i] = index[y.at(i, 0)]
+	}
+	return classes, labels
+}
+
+// decodeLabels returns the class with the highest score in each row of scores as a column vector.
+func decodeLabels[T Number](scores *Matrix[T], classes []T) *Matrix[T] {
+	y := Empty[T](scores.rows, 1)
+	for i := 0; i < scores.rows; i++ {
+		row := scores.row(i)
+		best := 0
+		for c, v := range row {
+			if v > row[best] {
+				best = c
+			}
+		}
+		y.set(i, 0, classes[best])
+	}
+	return y
+}
+
+// accuracy returns the fraction of elements of the column vectors y and yh that agree.
+func accuracy[T Number](y, yh *Matrix[T]) float64 {
+	var hits int
+	for i := 0; i < y.rows; i++ {
+		if y.at(i, 0) == yh.at(i, 0) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(y.rows)
+}
+
+func sigmoid(z float64) float64 {
+	if z >= 0 {
+		return 1 / (1 + math.Exp(-z))
+	}
+	e := math.Exp(z)
+	return e / (1 + e)
+}
+
+// softplus returns log(1 + eᶻ) without overflow.
+func softplus(z float64) float64 {
+	if z > 0 {
+		return z + math.Log1p(math.Exp(-z))
+	}
+	return math.Log1p(math.Exp(z))
+}
+
+// logSumExp returns log Σ exp(z[i]) without overflow.
+func logSumExp(z []float64) float64 {
+	m := math.Inf(-1)
+	for _, v := range z {
+		m = math.Max(m, v)
+	}
+	var s float64
+	for _, v := range z {
+		s += math.Exp(v - m)
+	}
+	return m + math.Log(s)
+}
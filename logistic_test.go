@@ -0,0 +1,95 @@
+package pa
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// blobs returns n samples drawn from a unit normal around each of the given centres, labelled by centre index.
+func blobs(centres [][]float64, n int) (*Matrix[float64], *Matrix[float64]) {
+	rng := rand.New(rand.NewSource(1))
+	var rows, ys [][]float64
+	for c, centre := range centres {
+		for i := 0; i < n; i++ {
+			x := make([]float64, len(centre))
+			for j := range x {
+				x[j] = centre[j] + rng.NormFloat64()
+			}
+			rows = append(rows, x)
+			ys = append(ys, []float64{float64(c)})
+		}
+	}
+	return NewMatrix(rows, nil), NewMatrix(ys, nil)
+}
+
+func TestLogisticRegressionBinary(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {2, 1}}, 40)
+	lr := &LogisticRegression[float64]{Alpha: 0, Tol: 1e-10}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	// at the maximum likelihood estimate, Xᵀ(p - y) = 0; in particular the predicted
+	// probabilities of the positive class sum to the number of positive samples
+	proba, err := lr.PredictProba(X)
+	if err != nil {
+		t.Fatalf("PredictProba() error: %v", err)
+	}
+	grad := withIntercept(X).T().Mul(proba.Col(1).Sub(y))
+	if !approxEqual(grad, Empty[float64](3, 1), 1e-6) {
+		t.Errorf("\nXᵀ(p - y):\n%s\nwant zero", grad)
+	}
+	for i := 0; i < proba.rows; i++ {
+		if s := proba.at(i, 0) + proba.at(i, 1); math.Abs(s-1) > 1e-12 {
+			t.Fatalf("row %d probabilities sum to %v", i, s)
+		}
+	}
+	acc, err := lr.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if acc < 0.75 {
+		t.Errorf("Score() = %v, want at least 0.75", acc)
+	}
+	if got := lr.Classes(); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("Classes() = %v, want [0 1]", got)
+	}
+}
+
+func TestLogisticRegressionMultinomial(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {6, 0}, {0, 6}}, 30)
+	lr := &LogisticRegression[float64]{Alpha: 0.01}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if r, c := lr.Coefficients().Size(); r != 3 || c != 3 {
+		t.Errorf("Coefficients() is (%d x %d), want (3 x 3)", r, c)
+	}
+	acc, err := lr.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if acc < 0.95 {
+		t.Errorf("Score() = %v, want at least 0.95", acc)
+	}
+	proba, _ := lr.PredictProba(NewMatrix([][]float64{{6, 0}}, nil))
+	if proba.at(0, 1) < 0.9 {
+		t.Errorf("P(class 1 | (6, 0)) = %v, want > 0.9", proba.at(0, 1))
+	}
+}
+
+func TestLogisticRegressionL1(t *testing.T) {
+	// the third feature is pure noise and should be eliminated by the L1 penalty
+	X, y := blobs([][]float64{{0, 0, 0}, {2, 2, 0}}, 40)
+	lr := &LogisticRegression[float64]{Penalty: PenaltyL1, Alpha: 0.05}
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	w := lr.Coefficients()
+	if w.at(3, 0) != 0 {
+		t.Errorf("noise coefficient = %v, want exactly 0", w.at(3, 0))
+	}
+	if w.at(1, 0) <= 0 || w.at(2, 0) <= 0 {
+		t.Errorf("informative coefficients = %v, %v, want positive", w.at(1, 0), w.at(2, 0))
+	}
+}
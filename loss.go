@@ -0,0 +1,127 @@
+package pa
+
+import "math"
+
+// Loss is a loss function for the stochastic gradient descent models, written in terms of the
+// model's raw prediction p and the target y. Classifiers train one binary problem per class,
+// with y = +1 for the class and -1 for the rest; regressors pass the target unchanged.
+type Loss interface {
+	// Loss returns the loss of predicting p when the target is y.
+	Loss(p, y float64) float64
+	// Derivative returns ∂Loss/∂p. Where the loss is not differentiable any subgradient will do.
+	Derivative(p, y float64) float64
+}
+
+// HingeLoss is max(0, 1 - yp), the loss of a linear support vector machine.
+type HingeLoss struct{}
+
+func (HingeLoss) Loss(p, y float64) float64 {
+	return math.Max(0, 1-y*p)
+}
+
+func (HingeLoss) Derivative(p, y float64) float64 {
+	if y*p < 1 {
+		return -y
+	}
+	return 0
+}
+
+func (HingeLoss) hinge() (squared bool) { return false }
+
+// SquaredHingeLoss is max(0, 1 - yp)², a smooth variant of HingeLoss that penalises margin violations more heavily.
+type SquaredHingeLoss struct{}
+
+func (SquaredHingeLoss) Loss(p, y float64) float64 {
+	z := math.Max(0, 1-y*p)
+	return z * z
+}
+
+func (SquaredHingeLoss) Derivative(p, y float64) float64 {
+	return -2 * y * math.Max(0, 1-y*p)
+}
+
+func (SquaredHingeLoss) hinge() (squared bool) { return true }
+
+// hinge is implemented by the hinge losses, which LinearSVC solves in the dual.
+type hinge interface {
+	hinge() (squared bool)
+}
+
+// LogisticLoss is log(1 + e^(-yp)), the loss of logistic regression.
+// Classifiers trained with it can give calibrated probabilities.
+type LogisticLoss struct{}
+
+func (LogisticLoss) Loss(p, y float64) float64 {
+	return softplus(-y * p)
+}
+
+func (LogisticLoss) Derivative(p, y float64) float64 {
+	return -y * sigmoid(-y*p)
+}
+
+func (LogisticLoss) probabilistic() {}
+
+// probabilistic is implemented by losses whose minimizer is the log-odds of the target,
+// so the sigmoid of a prediction is a probability.
+type probabilistic interface {
+	probabilistic()
+}
+
+// SquaredLoss is (p - y)²/2, the loss of ordinary least squares.
+type SquaredLoss struct{}
+
+func (SquaredLoss) Loss(p, y float64) float64 {
+	return (p - y) * (p - y) / 2
+}
+
+func (SquaredLoss) Derivative(p, y float64) float64 {
+	return p - y
+}
+
+// HuberLoss is quadratic for residuals up to Epsilon and linear beyond,
+// so outliers pull on the fit less than under SquaredLoss.
+type HuberLoss struct {
+	// Epsilon is the residual at which the loss turns linear. If zero, 0.1 is used.
+	Epsilon float64
+}
+
+func (h HuberLoss) Loss(p, y float64) float64 {
+	e, r := h.epsilon(), math.Abs(p-y)
+	if r <= e {
+		return r * r / 2
+	}
+	return e*r - e*e/2
+}
+
+func (h HuberLoss) Derivative(p, y float64) float64 {
+	e, r := h.epsilon(), p-y
+	return math.Max(-e, math.Min(e, r))
+}
+
+func (h HuberLoss) epsilon() float64 {
+	if h.Epsilon == 0 {
+		return 0.1
+	}
+	return h.Epsilon
+}
+
+// EpsilonInsensitiveLoss is max(0, |p - y| - Epsilon), the loss of support vector regression.
+// Residuals smaller than Epsilon are ignored.
+type EpsilonInsensitiveLoss struct {
+	// Epsilon is the width of the insensitive zone.
+	Epsilon float64
+}
+
+func (l EpsilonInsensitiveLoss) Loss(p, y float64) float64 {
+	return math.Max(0, math.Abs(p-y)-l.Epsilon)
+}
+
+func (l EpsilonInsensitiveLoss) Derivative(p, y float64) float64 {
+	switch {
+	case p-y > l.Epsilon:
+		return 1
+	case y-p > l.Epsilon:
+		return -1
+	}
+	return 0
+}
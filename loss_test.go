@@ -0,0 +1,41 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoss(t *testing.T) {
+	tests := []struct {
+		name       string
+		loss       Loss
+		p, y       float64
+		want, grad float64
+	}{
+		{"hinge inside margin", HingeLoss{}, 0.5, 1, 0.5, -1},
+		{"hinge outside margin", HingeLoss{}, -2, -1, 0, 0},
+		{"squared hinge", SquaredHingeLoss{}, 0.5, 1, 0.25, -1},
+		{"logistic", LogisticLoss{}, 0, 1, math.Ln2, -0.5},
+		{"squared", SquaredLoss{}, 3, 1, 2, 2},
+		{"huber quadratic", HuberLoss{Epsilon: 1}, 1.5, 1, 0.125, 0.5},
+		{"huber linear", HuberLoss{Epsilon: 1}, -2, 1, 2.5, -1},
+		{"epsilon-insensitive inside", EpsilonInsensitiveLoss{Epsilon: 0.5}, 1.2, 1, 0, 0},
+		{"epsilon-insensitive above", EpsilonInsensitiveLoss{Epsilon: 0.5}, 2, 1, 0.5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.loss.Loss(tt.p, tt.y); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Loss(%v, %v) = %v, want %v", tt.p, tt.y, got, tt.want)
+			}
+			if got := tt.loss.Derivative(tt.p, tt.y); math.Abs(got-tt.grad) > 1e-12 {
+				t.Errorf("Derivative(%v, %v) = %v, want %v", tt.p, tt.y, got, tt.grad)
+			}
+			// away from kinks the derivative must match a central difference
+			const h = 1e-6
+			fd := (tt.loss.Loss(tt.p+h, tt.y) - tt.loss.Loss(tt.p-h, tt.y)) / (2 * h)
+			if math.Abs(fd-tt.grad) > 1e-6 {
+				t.Errorf("central difference = %v, Derivative() = %v", fd, tt.grad)
+			}
+		})
+	}
+}
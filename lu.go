@@ -0,0 +1,156 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSingular is returned when factoring a matrix that is singular to working precision.
+var ErrSingular = errors.New("matrix is singular")
+
+// lupDecomp is an LU decomposition PAQ = LU. L is unit lower triangular and U is upper triangular;
+// both are packed into lu. Q is the identity unless full pivoting was requested.
+type lupDecomp[T Number] struct {
+	lu   *Matrix[T]
+	p, q []int
+	full bool
+	s    int
+}
+
+func (lupd *lupDecomp[T]) factor(m *Matrix[T]) error {
+	if m.err != nil {
+		return m.err
+	}
+	if !m.Square() {
+		return fmt.Errorf("Factor: LU decomposition of a non-square (%d x %d) matrix is undefined", m.rows, m.cols)
+	}
+	n := m.rows
+	a := m.Clone()
+	lupd.lu = a
+	lupd.p = make([]int, n)
+	lupd.q = make([]int, n)
+	for i := range lupd.p {
+		lupd.p[i], lupd.q[i] = i, i
+	}
+	tol := epsilon[T]() * float64(n) * float64(maxAbs(m))
+
+	for k := 0; k < n; k++ {
+		// choose the largest remaining pivot, searching only column k unless fully pivoting
+		pr, pc := k, k
+		big := abs(a.at(k, k))
+		last := k
+		if lupd.full {
+			last = n - 1
+		}
+		for j := k; j <= last; j++ {
+			for i := k; i < n; i++ {
+				if v := abs(a.at(i, j)); v > big {
+					big, pr, pc = v, i, j
+				}
+			}
+		}
+		if pr != k {
+			a.swapRows(pr, k)
+			lupd.p[pr], lupd.p[k] = lupd.p[k], lupd.p[pr]
+			lupd.s++
+		}
+		if pc != k {
+			for i := 0; i < n; i++ {
+				row := a.row(i)
+				row[pc], row[k] = row[k], row[pc]
+			}
+			lupd.q[pc], lupd.q[k] = lupd.q[k], lupd.q[pc]
+			lupd.s++
+		}
+		if float64(big) <= tol || big == 0 {
+			return fmt.Errorf("Factor: %w: pivot %d is %v", ErrSingular, k, a.at(k, k))
+		}
+
+		base := a.row(k)
+		for i := k + 1; i < n; i++ {
+			row := a.row(i)
+			row[k] /= base[k]
+			f := row[k]
+			for j := k + 1; j < n; j++ {
+				row[j] -= f * base[j]
+			}
+		}
+	}
+	return nil
+}
+
+// Factor computes the LU decomposition of m with partial (row) pivoting.
+// It returns an error wrapping ErrSingular if m is singular to working precision.
+func Factor[T Number](m *Matrix[T]) (factored[T], error) {
+	lupd := new(lupDecomp[T])
+	if err := lupd.factor(m); err != nil {
+		return nil, err
+	}
+	return lupd, nil
+}
+
+// FactorFullPivot computes the LU decomposition of m with full (row and column) pivoting.
+// It is slower than Factor but more stable on badly scaled matrices.
+func FactorFullPivot[T Number](m *Matrix[T]) (factored[T], error) {
+	lupd := &lupDecomp[T]{full: true}
+	if err := lupd.factor(m); err != nil {
+		return nil, err
+	}
+	return lupd, nil
+}
+
+func (lupd *lupDecomp[T]) Det() T {
+	var det T = 1
+	for i := 0; i < lupd.lu.rows; i++ {
+		det *= lupd.lu.at(i, i)
+	}
+
+	if lupd.s%2 == 0 {
+		return det
+	}
+
+	return -det
+}
+
+// Solve returns x such that Ax = b, where A is the factored matrix. b may have several columns.
+func (lupd *lupDecomp[T]) Solve(b *Matrix[T]) *Matrix[T] {
+	if b.err != nil {
+		return b
+	}
+	n := lupd.lu.rows
+	if b.rows != n {
+		return errored[T](fmt.Errorf("Solve: right hand side has %d rows, want %d", b.rows, n))
+	}
+
+	x := Empty[T](n, b.cols)
+	y := make([]T, n)
+	for c := 0; c < b.cols; c++ {
+		// forward substitution with the unit lower triangle
+		for i := 0; i < n; i++ {
+			v := b.at(lupd.p[i], c)
+			row := lupd.lu.row(i)
+			for j := 0; j < i; j++ {
+				v -= row[j] * y[j]
+			}
+			y[i] = v
+		}
+		// back substitution with the upper triangle
+		for i := n - 1; i >= 0; i-- {
+			v := y[i]
+			row := lupd.lu.row(i)
+			for j := i + 1; j < n; j++ {
+				v -= row[j] * y[j]
+			}
+			y[i] = v / row[i]
+		}
+		for i := 0; i < n; i++ {
+			x.set(lupd.q[i], c, y[i])
+		}
+	}
+
+	return x
+}
+
+func (lupd *lupDecomp[T]) Inverse(m *Matrix[T]) *Matrix[T] {
+	return lupd.Solve(NewIdentity[T](lupd.lu.rows))
+}
@@ -0,0 +1,122 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// approxEqual reports whether got and want have the same shape and agree elementwise to within tol.
+func approxEqual[T Number](got, want *Matrix[T], tol float64) bool {
+	if got.Err() != nil || got.rows != want.rows || got.cols != want.cols {
+		return false
+	}
+	for i := 0; i < want.rows; i++ {
+		for j := 0; j < want.cols; j++ {
+			if math.Abs(float64(got.at(i, j)-want.at(i, j))) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *Matrix[float64]
+		det     float64
+		b, x    *Matrix[float64]
+		inverse *Matrix[float64]
+	}{
+		{
+			name:    "needs pivot",
+			m:       NewMatrix([][]float64{{0, 1}, {1, 0}}, nil),
+			det:     -1,
+			b:       NewMatrix([][]float64{{2}, {3}}, nil),
+			x:       NewMatrix([][]float64{{3}, {2}}, nil),
+			inverse: NewMatrix([][]float64{{0, 1}, {1, 0}}, nil),
+		},
+		{
+			name:    "two",
+			m:       NewMatrix([][]float64{{2, 3}, {2, 2}}, nil),
+			det:     -2,
+			b:       NewMatrix([][]float64{{8}, {6}}, nil),
+			x:       NewMatrix([][]float64{{1}, {2}}, nil),
+			inverse: NewMatrix([][]float64{{-1, 3.0 / 2.0}, {1, -1}}, nil),
+		},
+		{
+			name:    "three",
+			m:       NewMatrix([][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, nil),
+			det:     4,
+			b:       NewMatrix([][]float64{{1, 0}, {0, 0}, {1, 4}}, nil),
+			x:       NewMatrix([][]float64{{1, 1}, {1, 2}, {1, 3}}, nil),
+			inverse: NewMatrix([][]float64{{0.75, 0.5, 0.25}, {0.5, 1, 0.5}, {0.25, 0.5, 0.75}}, nil),
+		},
+		{
+			name:    "four",
+			m:       NewMatrix([][]float64{{1, 2, 3, 4}, {2, 1, 0, 1}, {0, 1, 4, 2}, {3, 0, 1, 5}}, nil),
+			det:     -46,
+			b:       NewMatrix([][]float64{{10}, {4}, {7}, {9}}, nil),
+			x:       NewMatrix([][]float64{{1}, {1}, {1}, {1}}, nil),
+			inverse: nil,
+		},
+	}
+	for _, tt := range tests {
+		for _, full := range []bool{false, true} {
+			name := tt.name
+			if full {
+				name += " full"
+			}
+			t.Run(name, func(t *testing.T) {
+				factor := Factor[float64]
+				if full {
+					factor = FactorFullPivot[float64]
+				}
+				f, err := factor(tt.m)
+				if err != nil {
+					t.Fatalf("Factor() error: %v", err)
+				}
+				if got := f.Det(); math.Abs(got-tt.det) > 1e-9 {
+					t.Errorf("Det() = %v, want %v", got, tt.det)
+				}
+				if got := f.Solve(tt.b); !approxEqual(got, tt.x, 1e-9) {
+					t.Errorf("\nSolve(b):\n%s\nwant:\n%s", got, tt.x)
+				}
+				inv := f.Inverse(tt.m)
+				if tt.inverse != nil && !approxEqual(inv, tt.inverse, 1e-9) {
+					t.Errorf("\nInverse():\n%s\nwant:\n%s", inv, tt.inverse)
+				}
+				if got := tt.m.Mul(inv); !approxEqual(got, NewIdentity[float64](tt.m.rows), 1e-9) {
+					t.Errorf("\nm.Mul(Inverse()):\n%s\nwant identity", got)
+				}
+			})
+		}
+	}
+}
+
+func TestFactorSingular(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix[float64]
+	}{
+		{"zero", NewMatrix([][]float64{{0, 0}, {0, 0}}, nil)},
+		{"dependent rows", NewMatrix([][]float64{{1, 2, 3}, {2, 4, 6}, {1, 0, 1}}, nil)},
+		// the last pivot is 2⁻⁵², nonzero in float64 but below the tolerance eps·n·max|mᵢⱼ| = 2⁻⁵¹
+		{"near singular", NewMatrix([][]float64{{1, 1}, {1, 1 + 0x1p-52}}, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Factor(tt.m); !errors.Is(err, ErrSingular) {
+				t.Errorf("Factor() error = %v, want ErrSingular", err)
+			}
+		})
+	}
+	// a pivot of about 1e-15 is just above the tolerance, so the matrix still factors
+	if _, err := Factor(NewMatrix([][]float64{{1, 1}, {1, 1 + 1e-15}}, nil)); err != nil {
+		t.Errorf("Factor() of a barely nonsingular matrix error: %v", err)
+	}
+	if _, err := Factor(NewMatrix([][]float64{{1, 2}}, nil)); err == nil {
+		t.Error("Factor() of non-square matrix returned nil error")
+	}
+}
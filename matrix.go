@@ -14,9 +14,12 @@ const (
 	All
 )
 
+// Matrix is a dense matrix stored row-major in a single contiguous slice.
+// Element (i, j) lives at data[i*stride+j].
 type Matrix[T Number] struct {
-	data       [][]T
+	data       []T
 	rows, cols int
+	stride     int
 	index      []int
 	columns    []string
 	err        error
@@ -30,10 +33,8 @@ func (m *Matrix[T]) Err() error {
 }
 
 func NewMatrix[T Number](d [][]T, columns []string) *Matrix[T] {
-	m := new(Matrix[T])
-	m.data = d
 	if len(d) < 1 {
-		return m
+		return new(Matrix[T])
 	}
 	l := len(d[0])
 	for _, row := range d {
@@ -42,21 +43,54 @@ func NewMatrix[T Number](d [][]T, columns []string) *Matrix[T] {
 		}
 		l = len(row)
 	}
-	m.rows, m.cols = len(d), len(d[0])
-	m.index = []int{}
-	for i := 0; i < len(d); i++ {
-		m.index = append(m.index, i)
+	data := make([]T, 0, len(d)*l)
+	for _, row := range d {
+		data = append(data, row...)
+	}
+	return newMatrix(len(d), l, data, columns)
+}
+
+// newMatrix wraps data, laid out row-major with a stride of c, as an r x c matrix.
+// data is not copied.
+func newMatrix[T Number](r, c int, data []T, columns []string) *Matrix[T] {
+	m := new(Matrix[T])
+	if r < 1 {
+		return m
+	}
+	m.data = data
+	m.rows, m.cols = r, c
+	m.stride = c
+	m.index = make([]int, r)
+	for i := range m.index {
+		m.index[i] = i
 	}
 	m.columns = columns
 	return m
 }
 
+// at returns the element at row i and column j.
+func (m *Matrix[T]) at(i, j int) T {
+	return m.data[i*m.stride+j]
+}
+
+// set stores v at row i and column j.
+func (m *Matrix[T]) set(i, j int, v T) {
+	m.data[i*m.stride+j] = v
+}
+
+// row returns the backing storage of the ith row.
+// The returned slice is capped so appending to it never clobbers the next row.
+func (m *Matrix[T]) row(i int) []T {
+	o := i * m.stride
+	return m.data[o : o+m.cols : o+m.cols]
+}
+
 func (m *Matrix[T]) Size() (int, int) {
 	return m.rows, m.cols
 }
 
 func (m *Matrix[T]) sizet() (T, T) {
-	return any(m.rows).(T), any(m.cols).(T)
+	return T(m.rows), T(m.cols)
 }
 
 func (m *Matrix[T]) String() string {
@@ -68,8 +102,9 @@ func (m *Matrix[T]) String() string {
 			b.WriteString(fmt.Sprintf("%s,", col))
 		}
 	}
-	for i, row := range m.data {
+	for i := 0; i < m.rows; i++ {
 		b.WriteString(fmt.Sprintf("%d: ", i))
+		row := m.row(i)
 		for j, col := range row {
 			if j == len(row)-1 {
 				b.WriteString(fmt.Sprintf("%v\n", col))
@@ -93,19 +128,14 @@ func (m *Matrix[T]) T() *Matrix[T] {
 		return m
 	}
 
-	data := make([][]T, m.cols)
-	for i := range data {
-		data[i] = make([]T, m.rows)
-	}
-	for i := 0; i < m.cols; i++ {
-		for j := 0; j < m.rows; j++ {
-			data[i][j] = m.data[j][i]
+	data := make([]T, m.rows*m.cols)
+	for i := 0; i < m.rows; i++ {
+		for j, v := range m.row(i) {
+			data[j*m.rows+i] = v
 		}
 	}
 
-	t := NewMatrix(data, nil)
-
-	return t
+	return newMatrix(m.cols, m.rows, data, nil)
 }
 
 func (m *Matrix[T]) Add(b *Matrix[T]) *Matrix[T] {
@@ -121,25 +151,19 @@ func (m *Matrix[T]) Add(b *Matrix[T]) *Matrix[T] {
 		return m
 	}
 
-	data := make([][]T, mm)
-	for i := range data {
-		data[i] = make([]T, mn)
-	}
-	for i, row := range m.data {
-		for j := range row {
-			data[i][j] = m.data[i][j] + b.data[i][j]
+	data := make([]T, mm*mn)
+	for i := 0; i < mm; i++ {
+		out, br := data[i*mn:(i+1)*mn], b.row(i)
+		for j, v := range m.row(i) {
+			out[j] = v + br[j]
 		}
 	}
 
-	return NewMatrix(data, m.columns)
+	return newMatrix(mm, mn, data, m.columns)
 }
 
 func Empty[T Number](r, c int) *Matrix[T] {
-	d := make([][]T, r)
-	for i := range d {
-		d[i] = make([]T, c)
-	}
-	return NewMatrix(d, nil)
+	return newMatrix(r, c, make([]T, r*c), nil)
 }
 
 func Filled[T Number](r, c int, s T) *Matrix[T] {
@@ -149,9 +173,10 @@ func Filled[T Number](r, c int, s T) *Matrix[T] {
 }
 
 func (m *Matrix[T]) Fill(s T) {
-	for i := range m.data {
-		for j := range m.data[i] {
-			m.data[i][j] = s
+	for i := 0; i < m.rows; i++ {
+		row := m.row(i)
+		for j := range row {
+			row[j] = s
 		}
 	}
 }
@@ -164,42 +189,59 @@ func (m *Matrix[T]) Sub(b *Matrix[T]) *Matrix[T] {
 }
 
 func (m *Matrix[T]) Sum(ax Axis) *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
 	mn, mm := m.Size()
-	var data [][]T
-	var l int
+	var r []T
 	switch ax {
 	case Row:
-		data = m.data
-		l = mn
+		r = make([]T, mn)
+		for i := range r {
+			r[i] = sum(m.row(i))
+		}
 	case Column:
-		data = m.T().data
-		l = mm
-	}
-	r := make([]T, l)
-	for i, row := range data {
-		r[i] = sum(row)
+		r = make([]T, mm)
+		for i := 0; i < mn; i++ {
+			for j, v := range m.row(i) {
+				r[j] += v
+			}
+		}
+	case All:
+		r = make([]T, 1)
+		for i := 0; i < mn; i++ {
+			r[0] += sum(m.row(i))
+		}
 	}
-	return NewMatrix(append(make([][]T, 0), r), nil)
+	return newMatrix(1, len(r), r, nil)
 }
 
 func (m *Matrix[T]) Mean(ax Axis) *Matrix[T] {
-	mn, mm := m.Size()
+	if m.err != nil {
+		return m
+	}
+	mn, _ := m.Size()
 	var d []T
 	switch ax {
 	case Row:
 		d = make([]T, mn)
-		for i, row := range m.data {
-			d[i] = mean(row)
+		for i := range d {
+			d[i] = mean(m.row(i))
 		}
 	case Column:
-		d = make([]T, mm)
-		for i, row := range m.T().data {
-			d[i] = mean(row)
+		d = m.Sum(Column).data
+		n, _ := m.sizet()
+		for j := range d {
+			d[j] /= n
 		}
+	case All:
+		n, c := m.sizet()
+		d = m.Sum(All).data
+		d[0] /= n * c
 	default:
 		return m
 	}
-	return NewMatrix(append(make([][]T, 0), d), nil)
+	return newMatrix(1, len(d), d, nil)
 }
 
 func (m *Matrix[T]) Product(scalar T) *Matrix[T] {
@@ -207,16 +249,14 @@ func (m *Matrix[T]) Product(scalar T) *Matrix[T] {
 		return m
 	}
 	mn, mm := m.Size()
-	r := make([][]T, mn)
-	for i := range r {
-		r[i] = make([]T, mm)
-	}
-	for i, row := range r {
-		for j := range row {
-			r[i][j] = m.data[i][j] * scalar
+	data := make([]T, mn*mm)
+	for i := 0; i < mn; i++ {
+		out := data[i*mm : (i+1)*mm]
+		for j, v := range m.row(i) {
+			out[j] = v * scalar
 		}
 	}
-	return NewMatrix(r, nil)
+	return newMatrix(mn, mm, data, nil)
 }
 
 func (m *Matrix[T]) Usage() int {
@@ -234,21 +274,17 @@ func (m *Matrix[T]) Mul(b *Matrix[T]) *Matrix[T] {
 		return m
 	}
 
-	data := make([][]T, mi)
-	for i := range data {
-		data[i] = make([]T, bj)
-	}
-
+	// i-k-j ordering walks both operands row by row, keeping the inner loop contiguous.
+	data := make([]T, mi*bj)
 	for i := 0; i < mi; i++ {
-		for j := 0; j < bj; j++ {
-			var sum T
-			for k := 0; k < mj; k++ {
-				sum = sum + m.data[i][k]*b.data[k][j]
+		out := data[i*bj : (i+1)*bj]
+		for k, a := range m.row(i) {
+			for j, v := range b.row(k) {
+				out[j] += a * v
 			}
-			data[i][j] = sum
 		}
 	}
-	return NewMatrix(data, nil)
+	return newMatrix(mi, bj, data, nil)
 }
 
 // Delete drops the nth member of axis and returns the modified matrix.
@@ -257,27 +293,33 @@ func (m *Matrix[T]) Delete(n int, axis Axis) *Matrix[T] {
 		return m
 	}
 	mm, mn := m.Size()
-	data := make([][]T, mm)
-	for i := range data {
-		data[i] = make([]T, mn)
-	}
 	switch axis {
 	case Row:
-		if n > mm {
-			m.err = fmt.Errorf("row number out of bounds: %d > %d", n, mm)
+		if n < 0 || n >= mm {
+			m.err = fmt.Errorf("row number out of bounds: %d >= %d", n, mm)
 			return m
 		}
-		data = append(m.data[:n], m.data[n+1:]...)
+		data := make([]T, 0, (mm-1)*mn)
+		for i := 0; i < mm; i++ {
+			if i != n {
+				data = append(data, m.row(i)...)
+			}
+		}
+		return newMatrix(mm-1, mn, data, nil)
 	case Column:
-		if n > mn {
-			m.err = fmt.Errorf("column number out of bounds: %d > %d", n, mn)
+		if n < 0 || n >= mn {
+			m.err = fmt.Errorf("column number out of bounds: %d >= %d", n, mn)
 			return m
 		}
-		for i, row := range m.data {
-			data[i] = append(row[:n], row[n+1:]...)
+		data := make([]T, 0, mm*(mn-1))
+		for i := 0; i < mm; i++ {
+			row := m.row(i)
+			data = append(data, row[:n]...)
+			data = append(data, row[n+1:]...)
 		}
+		return newMatrix(mm, mn-1, data, nil)
 	}
-	return NewMatrix(data, nil)
+	return m
 }
 
 // Minor returns m with the ith row and jth column removed
@@ -290,17 +332,18 @@ func (m *Matrix[T]) Minor(i, j int) *Matrix[T] {
 
 // Applies f element wise
 func (m *Matrix[T]) Apply(f func(T) T) *Matrix[T] {
-	mn, mm := m.Size()
-	data := make([][]T, mn)
-	for i := range data {
-		data[i] = make([]T, mm)
+	if m.err != nil {
+		return m
 	}
-	for i, row := range data {
-		for j := range row {
-			data[i][j] = f(m.data[i][j])
+	mn, mm := m.Size()
+	data := make([]T, mn*mm)
+	for i := 0; i < mn; i++ {
+		out := data[i*mm : (i+1)*mm]
+		for j, v := range m.row(i) {
+			out[j] = f(v)
 		}
 	}
-	return NewMatrix(data, nil)
+	return newMatrix(mn, mm, data, nil)
 }
 
 func (m *Matrix[T]) Reverse(axis Axis) *Matrix[T] {
@@ -308,139 +351,74 @@ func (m *Matrix[T]) Reverse(axis Axis) *Matrix[T] {
 		return m
 	}
 	mn, mm := m.Size()
-	data := make([][]T, mn)
-	for i := range data {
-		data[i] = make([]T, mm)
-	}
+	data := make([]T, mn*mm)
 	switch axis {
 	case Row:
-		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
-			data[i], data[j] = m.data[j], m.data[i]
+		for i := 0; i < mn; i++ {
+			copy(data[(mn-1-i)*mm:(mn-i)*mm], m.row(i))
 		}
 	case Column:
-		for i := range data {
-			for j, k := 0, len(data[i])-1; j < k; j, k = j+1, k-1 {
-				data[i][j], data[i][k] = m.data[i][k], m.data[i][j]
+		for i := 0; i < mn; i++ {
+			out := data[i*mm : (i+1)*mm]
+			for j, v := range m.row(i) {
+				out[mm-1-j] = v
 			}
 		}
+	default:
+		return m
 	}
 
-	return NewMatrix(data, nil)
+	return newMatrix(mn, mm, data, nil)
 }
 
-func NewIdentity[T Number](size int) *Matrix[T] {
-	data := make([][]T, size)
-	for i := range data {
-		data[i] = make([]T, size)
+// errored returns an empty matrix carrying err, for operations that have no receiver to record it on.
+func errored[T Number](err error) *Matrix[T] {
+	return &Matrix[T]{err: err}
+}
+
+// convert returns a copy of m with each element converted to U.
+func convert[U, T Number](m *Matrix[T]) *Matrix[U] {
+	if m.err != nil {
+		return errored[U](m.err)
 	}
-	for i := range data {
-		data[i][i] = 1
+	data := make([]U, 0, m.rows*m.cols)
+	for i := 0; i < m.rows; i++ {
+		for _, v := range m.row(i) {
+			data = append(data, U(v))
+		}
 	}
+	return newMatrix(m.rows, m.cols, data, m.columns)
+}
 
-	return NewMatrix(data, nil)
+func NewIdentity[T Number](size int) *Matrix[T] {
+	id := Empty[T](size, size)
+	for i := 0; i < size; i++ {
+		id.set(i, i, 1)
+	}
+	return id
 }
 
 // use an interface here, since there are many implementations of matrix factoring
 type factored[T Number] interface {
-	factor(m *Matrix[T])
+	factor(m *Matrix[T]) error
 	Det() T
 	Solve(m *Matrix[T]) *Matrix[T]
 	Inverse(m *Matrix[T]) *Matrix[T]
 }
 
-type lupDecomp[T Number] struct {
-	p, u, l *Matrix[T]
-	lowers  [][]T
-	s       int
-}
-
-func (lupd *lupDecomp[T]) factor(m *Matrix[T]) {
-	mi, _ := m.Size()
-	data := make([][]T, len(m.data))
-	copy(data, m.data)
-	lupd.u = NewMatrix(data, nil)
-	lupd.p = NewIdentity[T](mi)
-	lupd.lowers = make([][]T, mi)
-	for i := range lupd.lowers {
-		lupd.lowers[i] = make([]T, mi)
+// swapRows exchanges rows i and j of m in place.
+func (m *Matrix[T]) swapRows(i, j int) {
+	ri, rj := m.row(i), m.row(j)
+	for k := range ri {
+		ri[k], rj[k] = rj[k], ri[k]
 	}
-
-	for step := 0; step < mi-1; step++ {
-		// perform swapping
-		lupd.u.data[step+1], lupd.u.data[step] = lupd.u.data[step], lupd.u.data[step+1]
-		lupd.p.data[step+1], lupd.p.data[step] = lupd.p.data[step], lupd.p.data[step+1]
-
-		for i, row := range lupd.u.data[step+1:] {
-			base := lupd.u.data[step]
-			lower := row[step] / base[step]
-			lupd.u.data[step+1+i] = Array[T](row).Sub(Array[T](base).Scale(lower))
-			lupd.lowers[step][i] = lower
-		}
-
-		lupd.s++
-	}
-
-	l := NewMatrix(lupd.lowers, nil).T().Reverse(Row)
-	for i := range l.data {
-		l.data[i][i] = 1
-	}
-
-	lupd.l = l
-}
-
-func Factor[T Number](m *Matrix[T]) (factored[T], error) {
-	lupd := new(lupDecomp[T])
-	lupd.factor(m)
-	return lupd, nil
-}
-
-func (lupd *lupDecomp[T]) Det() T {
-	var lp, up T
-	lp = 1
-	up = 1
-	for i := 0; i < len(lupd.u.data); i++ {
-		up *= lupd.u.data[i][i]
-		lp *= lupd.l.data[i][i]
-	}
-
-	if lupd.s%2 == 0 {
-		return lp * up
-	}
-
-	return -lp * up
-}
-
-func (lupd *lupDecomp[T]) Solve(b *Matrix[T]) *Matrix[T] {
-
-	rhs := lupd.p.Mul(b)
-
-	xs := make([]T, len(lupd.l.data))
-	for i, row := range lupd.l.data {
-		before := make([]T, len(row))
-		for j, v := range row {
-			before[j] = xs[j] * v
-		}
-		xs[i] = (rhs.data[i][0] - sum(before)) / lupd.l.data[i][i]
-	}
-	Y := NewMatrix(append(make([][]T, 0), xs), nil).T()
-	xs = make([]T, len(lupd.u.data))
-	for i := len(lupd.u.data) - 1; i >= 0; i-- {
-		front := make([]T, len(lupd.u.data[i]))
-		for j := len(lupd.u.data[i]) - 1; j >= 0; j-- {
-			front[j] = xs[j] * lupd.u.data[i][j]
-		}
-		xs[i] = (Y.data[i][0] - sum(front)) / lupd.u.data[i][i]
-	}
-
-	return NewMatrix(append(make([][]T, 0), xs), nil).T()
 }
 
-func (lupd *lupDecomp[T]) Inverse(m *Matrix[T]) *Matrix[T] {
-	cols := [][]T{}
-	b := NewIdentity[T](len(m.data))
-	for _, row := range b.T().data {
-		res := lupd.Solve(NewMatrix(append(make([][]T, 0), row), nil).T())
-		cols = append(cols, res.T().data...)
+// pick returns a new matrix holding the rows of m listed in idx, in that order.
+func (m *Matrix[T]) pick(idx []int) *Matrix[T] {
+	data := make([]T, 0, len(idx)*m.cols)
+	for _, i := range idx {
+		data = append(data, m.row(i)...)
 	}
-	return NewMatrix(cols, nil).T()
+	return newMatrix(len(idx), m.cols, data, m.columns)
 }
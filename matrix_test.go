@@ -231,6 +231,12 @@ func TestMatrix_Sum(t *testing.T) {
 			axis: Column,
 			want: NewMatrix([][]float64{{0.5, 1.5}}, nil),
 		},
+		{
+			name: "all",
+			m:    NewMatrix([][]float64{{0.5, 1.5}, {2, 3}}, nil),
+			axis: All,
+			want: NewMatrix([][]float64{{7.0}}, nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -287,6 +293,12 @@ func TestMatrix_Reverse(t *testing.T) {
 			axis: Column,
 			want: NewMatrix([][]float64{{4, 5, 6}, {1, 2, 3}}, nil).T(),
 		},
+		{
+			name: "odd row",
+			m:    NewMatrix([][]float64{{1, 2}, {3, 4}, {5, 6}}, nil),
+			axis: Row,
+			want: NewMatrix([][]float64{{5, 6}, {3, 4}, {1, 2}}, nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -315,6 +327,12 @@ func TestMatrix_Mean(t *testing.T) {
 			axis: Column,
 			want: NewMatrix([][]float64{{2.5, 3.5, 4.5}}, nil),
 		},
+		{
+			name: "all",
+			m:    NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}}, nil),
+			axis: All,
+			want: NewMatrix([][]float64{{3.5}}, nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,90 @@
+package pa
+
+import "math"
+
+// Metric is a distance between two samples, given as rows of a Matrix.
+// The tree indexes prune their search with the triangle inequality, so a Metric used with them
+// must satisfy it; brute-force search accepts any Metric.
+type Metric[T Number] interface {
+	Distance(x, y Array[T]) float64
+}
+
+// minkowskiMetric is implemented by the metrics of the Minkowski family, whose distance to an
+// axis-aligned box can be bounded coordinate by coordinate, as the KD-tree requires.
+type minkowskiMetric interface {
+	power() float64
+}
+
+// semimetric is implemented by distances that do not satisfy the triangle inequality,
+// which the ball tree relies on to prune.
+type semimetric interface {
+	semimetric()
+}
+
+// EuclideanDistance is the straight-line distance ‖x - y‖₂.
+type EuclideanDistance[T Number] struct{}
+
+func (EuclideanDistance[T]) Distance(x, y Array[T]) float64 {
+	var s float64
+	for i := range x {
+		d := float64(x[i]) - float64(y[i])
+		s += d * d
+	}
+	return math.Sqrt(s)
+}
+
+func (EuclideanDistance[T]) power() float64 { return 2 }
+
+// ManhattanDistance is the city-block distance ‖x - y‖₁.
+type ManhattanDistance[T Number] struct{}
+
+func (ManhattanDistance[T]) Distance(x, y Array[T]) float64 {
+	var s float64
+	for i := range x {
+		s += math.Abs(float64(x[i]) - float64(y[i]))
+	}
+	return s
+}
+
+func (ManhattanDistance[T]) power() float64 { return 1 }
+
+// MinkowskiDistance is ‖x - y‖ₚ = (Σ|xᵢ - yᵢ|ᴾ)^(1/P).
+// P = 1 is the Manhattan distance and P = 2 the Euclidean distance.
+type MinkowskiDistance[T Number] struct {
+	// P is the order of the norm. It must be at least 1 for the triangle inequality to hold.
+	// If zero, 2 is used.
+	P float64
+}
+
+func (m MinkowskiDistance[T]) Distance(x, y Array[T]) float64 {
+	p := m.power()
+	var s float64
+	for i := range x {
+		s += math.Pow(math.Abs(float64(x[i])-float64(y[i])), p)
+	}
+	return math.Pow(s, 1/p)
+}
+
+func (m MinkowskiDistance[T]) power() float64 {
+	if m.P == 0 {
+		return 2
+	}
+	return m.P
+}
+
+// CosineDistance is 1 - cos θ, where θ is the angle between x and y; it ignores their lengths.
+// A zero vector is at distance 1 from everything. It does not satisfy the triangle inequality,
+// so it can only be searched by brute force.
+type CosineDistance[T Number] struct{}
+
+func (CosineDistance[T]) Distance(x, y Array[T]) float64 {
+	xy, _ := Dot[T](x, y)
+	xx, _ := Dot[T](x, x)
+	yy, _ := Dot[T](y, y)
+	if xx == 0 || yy == 0 {
+		return 1
+	}
+	return 1 - float64(xy)/math.Sqrt(float64(xx)*float64(yy))
+}
+
+func (CosineDistance[T]) semimetric() {}
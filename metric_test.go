@@ -0,0 +1,38 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	x, y := Array[float64]{1, 2}, Array[float64]{4, -2}
+	tests := []struct {
+		name   string
+		metric Metric[float64]
+		want   float64
+	}{
+		{"euclidean", EuclideanDistance[float64]{}, 5},
+		{"manhattan", ManhattanDistance[float64]{}, 7},
+		{"minkowski 1", MinkowskiDistance[float64]{P: 1}, 7},
+		{"minkowski default", MinkowskiDistance[float64]{}, 5},
+		{"minkowski 3", MinkowskiDistance[float64]{P: 3}, math.Cbrt(91)},
+		// x and y are orthogonal
+		{"cosine", CosineDistance[float64]{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.Distance(x, y); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+			if got := tt.metric.Distance(x, x); math.Abs(got) > 1e-12 {
+				t.Errorf("Distance(x, x) = %v, want 0", got)
+			}
+		})
+	}
+
+	// cosine distance ignores length
+	if d := (CosineDistance[float64]{}).Distance(Array[float64]{1, 1}, Array[float64]{3, 3}); math.Abs(d) > 1e-12 {
+		t.Errorf("cosine Distance() of parallel vectors = %v, want 0", d)
+	}
+}
@@ -0,0 +1,639 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/kipukun/pa/internal/optim"
+)
+
+// Activation selects the nonlinearity applied by the hidden layers of a multilayer perceptron.
+type Activation int
+
+const (
+	// ActivationReLU is max(0, z).
+	ActivationReLU Activation = iota
+	// ActivationTanh is the hyperbolic tangent.
+	ActivationTanh
+	// ActivationSigmoid is the logistic function 1/(1+exp(-z)).
+	ActivationSigmoid
+	// ActivationIdentity leaves z unchanged, which makes the network linear.
+	ActivationIdentity
+)
+
+// apply replaces each element of z with its activation.
+func (a Activation) apply(z []float64) {
+	for i, v := range z {
+		switch a {
+		case ActivationReLU:
+			z[i] = math.Max(0, v)
+		case ActivationTanh:
+			z[i] = math.Tanh(v)
+		case ActivationSigmoid:
+			z[i] = sigmoid(v)
+		}
+	}
+}
+
+// backprop multiplies each element of delta by the derivative of the activation,
+// expressed through the activation's output out.
+func (a Activation) backprop(out, delta []float64) {
+	for i, v := range out {
+		switch a {
+		case ActivationReLU:
+			if v <= 0 {
+				delta[i] = 0
+			}
+		case ActivationTanh:
+			delta[i] *= 1 - v*v
+		case ActivationSigmoid:
+			delta[i] *= v * (1 - v)
+		}
+	}
+}
+
+// Optimizer selects how a multilayer perceptron turns minibatch gradients into steps.
+type Optimizer int
+
+const (
+	// OptimizerAdam adapts the step of each weight from running estimates of the first and second moments
+	// of its gradient (Kingma and Ba, 2015).
+	OptimizerAdam Optimizer = iota
+	// OptimizerSGD steps against the gradient, scaled by the learning rate.
+	OptimizerSGD
+	// OptimizerMomentum steps along a velocity that accumulates past gradients, decaying by Momentum each step.
+	OptimizerMomentum
+)
+
+// mlpConfig holds the validated settings of a multilayer perceptron, with defaults filled in.
+type mlpConfig struct {
+	hidden     []int
+	activation Activation
+	optimizer  Optimizer
+	alpha      float64
+	batch      int
+	rate       float64
+	momentum   float64
+	tol        float64
+	maxIter    int
+	patience   int
+	seed       int64
+}
+
+func (c mlpConfig) withDefaults() (mlpConfig, error) {
+	if c.hidden == nil {
+		c.hidden = []int{100}
+	}
+	for _, h := range c.hidden {
+		if h < 1 {
+			return c, fmt.Errorf("hidden layers must have at least one unit, got %v", c.hidden)
+		}
+	}
+	if c.activation < ActivationReLU || c.activation > ActivationIdentity {
+		return c, fmt.Errorf("unknown activation %d", c.activation)
+	}
+	if c.optimizer < OptimizerAdam || c.optimizer > OptimizerMomentum {
+		return c, fmt.Errorf("unknown optimizer %d", c.optimizer)
+	}
+	if c.alpha < 0 {
+		return c, fmt.Errorf("Alpha must not be negative, got %v", c.alpha)
+	}
+	if c.batch < 0 {
+		return c, fmt.Errorf("BatchSize must not be negative, got %d", c.batch)
+	}
+	if c.batch == 0 {
+		c.batch = 200
+	}
+	if c.rate < 0 {
+		return c, fmt.Errorf("LearningRate must not be negative, got %v", c.rate)
+	}
+	if c.rate == 0 {
+		c.rate = 0.001
+	}
+	if c.momentum < 0 || c.momentum >= 1 {
+		return c, fmt.Errorf("Momentum must be in [0, 1), got %v", c.momentum)
+	}
+	if c.momentum == 0 {
+		c.momentum = 0.9
+	}
+	if c.tol == 0 {
+		c.tol = defaultTolerance
+	}
+	if c.maxIter == 0 {
+		c.maxIter = 200
+	}
+	if c.patience == 0 {
+		c.patience = 10
+	}
+	return c, nil
+}
+
+func (c mlpConfig) stepper() optim.Stepper {
+	switch c.optimizer {
+	case OptimizerSGD:
+		return &optim.GradientDescent{Rate: c.rate}
+	case OptimizerMomentum:
+		return &optim.Momentum{Rate: c.rate, Momentum: c.momentum}
+	}
+	return &optim.Adam{Rate: c.rate, Beta1: 0.9, Beta2: 0.999, Epsilon: 1e-8}
+}
+
+// mlp is a fully connected feedforward network, along with everything needed to resume training
+// on the next call to PartialFit. The weights and biases of all layers live in one flat vector.
+type mlp struct {
+	sizes      []int // the width of each layer, input first
+	offsets    []int // where the weights of each layer start in params, with the biases after them
+	activation Activation
+	softmax    bool // softmax outputs with cross-entropy loss, rather than linear outputs with squared loss
+	params     []float64
+	grad       []float64
+	stepper    optim.Stepper
+	rng        *rand.Rand
+	losses     []float64
+}
+
+// newMLP returns a network with in inputs and out outputs, its weights drawn as proposed by Glorot and Bengio.
+func newMLP(cfg mlpConfig, in, out int, softmax bool) *mlp {
+	m := &mlp{activation: cfg.activation, softmax: softmax, stepper: cfg.stepper(), rng: rand.New(rand.NewSource(cfg.seed))}
+	m.sizes = append(append([]int{in}, cfg.hidden...), out)
+	m.offsets = make([]int, len(m.sizes))
+	for l := 1; l < len(m.sizes); l++ {
+		m.offsets[l] = m.offsets[l-1]
+		if l > 1 {
+			m.offsets[l] += (m.sizes[l-2] + 1) * m.sizes[l-1]
+		}
+	}
+	last := len(m.sizes) - 1
+	m.params = make([]float64, m.offsets[last]+(m.sizes[last-1]+1)*m.sizes[last])
+	m.grad = make([]float64, len(m.params))
+	for l := 1; l < len(m.sizes); l++ {
+		factor := 6.0
+		if cfg.activation == ActivationSigmoid {
+			factor = 2
+		}
+		limit := math.Sqrt(factor / float64(m.sizes[l-1]+m.sizes[l]))
+		w, b := m.layer(m.params, l)
+		for i := range w {
+			w[i] = limit * (2*m.rng.Float64() - 1)
+		}
+		for i := range b {
+			b[i] = limit * (2*m.rng.Float64() - 1)
+		}
+	}
+	return m
+}
+
+// layer returns the weights, an (in x out) row-major matrix, and the biases of layer l within v,
+// which is laid out like params.
+func (m *mlp) layer(v []float64, l int) (w, b []float64) {
+	in, out := m.sizes[l-1], m.sizes[l]
+	start := m.offsets[l]
+	return v[start : start+in*out], v[start+in*out : start+(in+1)*out]
+}
+
+// forward returns the outputs of each layer for the n samples in the row-major x, starting with x itself.
+func (m *mlp) forward(x []float64, n int) [][]float64 {
+	acts := [][]float64{x}
+	last := len(m.sizes) - 1
+	for l := 1; l <= last; l++ {
+		in, out := m.sizes[l-1], m.sizes[l]
+		w, b := m.layer(m.params, l)
+		a, z := acts[l-1], make([]float64, n*out)
+		for i := 0; i < n; i++ {
+			zi := z[i*out : (i+1)*out]
+			copy(zi, b)
+			for j, v := range a[i*in : (i+1)*in] {
+				if v != 0 {
+					axpy(v, w[j*out:(j+1)*out], zi)
+				}
+			}
+			switch {
+			case l < last:
+				m.activation.apply(zi)
+			case m.softmax:
+				lse := logSumExp(zi)
+				for o := range zi {
+					zi[o] = math.Exp(zi[o] - lse)
+				}
+			}
+		}
+		acts = append(acts, z)
+	}
+	return acts
+}
+
+// step computes the loss and its gradient on the n samples in the row-major x with targets y,
+// one-hot for softmax outputs, and steps the parameters. It returns the loss before the step.
+func (m *mlp) step(x, y []float64, n int, alpha float64) float64 {
+	acts := m.forward(x, n)
+	last := len(m.sizes) - 1
+	out := acts[last]
+	var loss float64
+	delta := make([]float64, len(out))
+	for i, v := range out {
+		if m.softmax {
+			if y[i] != 0 {
+				loss -= y[i] * math.Log(math.Max(v, 1e-300))
+			}
+		} else {
+			loss += (v - y[i]) * (v - y[i]) / 2
+		}
+		delta[i] = (v - y[i]) / float64(n)
+	}
+	loss /= float64(n)
+
+	for l := last; l >= 1; l-- {
+		in, width := m.sizes[l-1], m.sizes[l]
+		w, _ := m.layer(m.params, l)
+		gw, gb := m.layer(m.grad, l)
+		a := acts[l-1]
+		loss += alpha * dotf(w, w) / (2 * float64(n))
+		copy(gw, w)
+		scale(alpha/float64(n), gw)
+		for o := range gb {
+			gb[o] = 0
+		}
+		for i := 0; i < n; i++ {
+			di := delta[i*width : (i+1)*width]
+			axpy(1, di, gb)
+			for j, v := range a[i*in : (i+1)*in] {
+				if v != 0 {
+					axpy(v, di, gw[j*width:(j+1)*width])
+				}
+			}
+		}
+		if l == 1 {
+			break
+		}
+		prev := make([]float64, n*in)
+		for i := 0; i < n; i++ {
+			di, pi := delta[i*width:(i+1)*width], prev[i*in:(i+1)*in]
+			for j := range pi {
+				pi[j] = dotf(w[j*width:(j+1)*width], di)
+			}
+		}
+		m.activation.backprop(a, prev)
+		delta = prev
+	}
+	m.stepper.Step(m.params, m.grad)
+	return loss
+}
+
+// epoch makes one pass over the rows of X and Y, shuffled, in minibatches of the given size.
+// It returns the mean loss over the samples.
+func (m *mlp) epoch(X, Y *Matrix[float64], batch int, alpha float64) float64 {
+	order := m.rng.Perm(X.rows)
+	var total float64
+	for start := 0; start < len(order); start += batch {
+		idx := order[start:minInt(start+batch, len(order))]
+		xb, yb := X.pick(idx), Y.pick(idx)
+		total += m.step(xb.data, yb.data, len(idx), alpha) * float64(len(idx))
+	}
+	loss := total / float64(X.rows)
+	m.losses = append(m.losses, loss)
+	return loss
+}
+
+// fit makes passes over X and Y until the loss has not improved by tol for more than patience passes.
+// It returns the number of passes made and whether the loss settled.
+func (m *mlp) fit(cfg mlpConfig, X, Y *Matrix[float64]) (int, bool) {
+	best, stale := math.Inf(1), 0
+	for iter := 1; iter <= cfg.maxIter; iter++ {
+		loss := m.epoch(X, Y, cfg.batch, cfg.alpha)
+		if loss > best-cfg.tol {
+			stale++
+		} else {
+			stale = 0
+		}
+		best = math.Min(best, loss)
+		if stale > cfg.patience {
+			return iter, true
+		}
+	}
+	return cfg.maxIter, false
+}
+
+// predict returns the outputs of the network for the rows of X.
+func (m *mlp) predict(X *Matrix[float64]) *Matrix[float64] {
+	acts := m.forward(X.data, X.rows)
+	return newMatrix(X.rows, m.sizes[len(m.sizes)-1], acts[len(acts)-1], nil)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// MLPClassifier is a multilayer perceptron with softmax outputs, one per class, trained by backpropagation
+// on minibatches to minimize the cross-entropy.
+type MLPClassifier[T Float] struct {
+	// HiddenLayers is the number of units in each hidden layer. If nil, one layer of 100 units is used;
+	// an empty, non-nil slice gives a network with no hidden layers, which is multinomial logistic regression.
+	HiddenLayers []int
+	// Activation is the nonlinearity of the hidden layers. The zero value is ActivationReLU.
+	Activation Activation
+	// Optimizer selects how gradients become steps. The zero value is OptimizerAdam.
+	Optimizer Optimizer
+	// Alpha is the strength of the L2 penalty on the weights; zero fits an unpenalised model.
+	Alpha float64
+	// BatchSize is the number of samples in each minibatch. If zero, 200 is used.
+	BatchSize int
+	// LearningRate is the step size, or for OptimizerAdam its upper bound. If zero, 0.001 is used.
+	LearningRate float64
+	// Momentum is the decay of the velocity under OptimizerMomentum. If zero, 0.9 is used.
+	Momentum float64
+	// Tol stops Fit once the training loss has not improved by Tol for more than NIterNoChange passes.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 200 is used.
+	MaxIter int
+	// NIterNoChange is the number of passes without improvement that stops Fit. If zero, 10 is used.
+	NIterNoChange int
+	// Seed seeds the initial weights and the shuffling of samples before each pass, so fits are reproducible.
+	Seed int64
+
+	classes []T
+	net     *mlp
+	iters   int
+}
+
+func (mc *MLPClassifier[T]) config() (mlpConfig, error) {
+	return mlpConfig{
+		hidden:     mc.HiddenLayers,
+		activation: mc.Activation,
+		optimizer:  mc.Optimizer,
+		alpha:      mc.Alpha,
+		batch:      mc.BatchSize,
+		rate:       mc.LearningRate,
+		momentum:   mc.Momentum,
+		tol:        mc.Tol,
+		maxIter:    mc.MaxIter,
+		patience:   mc.NIterNoChange,
+		seed:       mc.Seed,
+	}.withDefaults()
+}
+
+// Fit trains a new network on the samples in the rows of X, whose labels are in the column vector y.
+func (mc *MLPClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return fmt.Errorf("MLPClassifier.Fit: y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	cfg, err := mc.config()
+	if err != nil {
+		return fmt.Errorf("MLPClassifier.Fit: %w", err)
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("MLPClassifier.Fit: need samples of at least two classes, got %d", len(classes))
+	}
+	mc.classes, mc.net = classes, newMLP(cfg, X.cols, len(classes), true)
+	iters, converged := mc.net.fit(cfg, convert[float64](X), oneHotLabels(labels, len(classes)))
+	mc.iters = iters
+	if !converged {
+		return fmt.Errorf("MLPClassifier.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (mc *MLPClassifier[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	cfg, err := mc.config()
+	if err != nil {
+		return fmt.Errorf("MLPClassifier.PartialFit: %w", err)
+	}
+	if mc.net == nil {
+		cs, err := initClasses(classes)
+		if err != nil {
+			return fmt.Errorf("MLPClassifier.PartialFit: %w", err)
+		}
+		mc.classes, mc.net = cs, newMLP(cfg, X.cols, len(cs), true)
+	}
+	labels, err := indexLabels(X, y, mc.classes, mc.net.sizes[0])
+	if err != nil {
+		return fmt.Errorf("MLPClassifier.PartialFit: %w", err)
+	}
+	mc.net.epoch(convert[float64](X), oneHotLabels(labels, len(mc.classes)), cfg.batch, cfg.alpha)
+	mc.iters++
+	return nil
+}
+
+// PredictProba returns the softmax outputs of the network for the rows of X, with columns in the order of Classes.
+func (mc *MLPClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	proba, err := mc.proba(X)
+	if err != nil {
+		return nil, fmt.Errorf("MLPClassifier.PredictProba: %w", err)
+	}
+	return convert[T](proba), nil
+}
+
+func (mc *MLPClassifier[T]) proba(X *Matrix[T]) (*Matrix[float64], error) {
+	if mc.net == nil {
+		return nil, fmt.Errorf("model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != mc.net.sizes[0] {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, mc.net.sizes[0])
+	}
+	return mc.net.predict(convert[float64](X)), nil
+}
+
+// Predict returns the most probable class for each row of X.
+func (mc *MLPClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	proba, err := mc.proba(X)
+	if err != nil {
+		return nil, fmt.Errorf("MLPClassifier.Predict: %w", err)
+	}
+	yh := Empty[T](proba.rows, 1)
+	for i := 0; i < proba.rows; i++ {
+		yh.set(i, 0, mc.classes[argmax(proba.row(i))])
+	}
+	return yh, nil
+}
+
+func (mc *MLPClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := mc.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (mc *MLPClassifier[T]) Classes() []T {
+	return mc.classes
+}
+
+// Iterations returns the number of passes over the data made by the last call to Fit, plus one for each
+// call to PartialFit since.
+func (mc *MLPClassifier[T]) Iterations() int {
+	return mc.iters
+}
+
+// LossCurve returns the mean training loss of each pass over the data, including its penalty.
+func (mc *MLPClassifier[T]) LossCurve() []float64 {
+	if mc.net == nil {
+		return nil
+	}
+	return mc.net.losses
+}
+
+// oneHotLabels returns a matrix with a row per label, holding 1 in the label's column and 0 elsewhere.
+func oneHotLabels(labels []int, k int) *Matrix[float64] {
+	m := Empty[float64](len(labels), k)
+	for i, c := range labels {
+		m.set(i, c, 1)
+	}
+	return m
+}
+
+// MLPRegressor is a multilayer perceptron with linear outputs, one per column of y, trained by
+// backpropagation on minibatches to minimize the squared error.
+type MLPRegressor[T Float] struct {
+	// HiddenLayers is the number of units in each hidden layer. If nil, one layer of 100 units is used;
+	// an empty, non-nil slice gives a network with no hidden layers, which is linear regression.
+	HiddenLayers []int
+	// Activation is the nonlinearity of the hidden layers. The zero value is ActivationReLU.
+	Activation Activation
+	// Optimizer selects how gradients become steps. The zero value is OptimizerAdam.
+	Optimizer Optimizer
+	// Alpha is the strength of the L2 penalty on the weights; zero fits an unpenalised model.
+	Alpha float64
+	// BatchSize is the number of samples in each minibatch. If zero, 200 is used.
+	BatchSize int
+	// LearningRate is the step size, or for OptimizerAdam its upper bound. If zero, 0.001 is used.
+	LearningRate float64
+	// Momentum is the decay of the velocity under OptimizerMomentum. If zero, 0.9 is used.
+	Momentum float64
+	// Tol stops Fit once the training loss has not improved by Tol for more than NIterNoChange passes.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 200 is used.
+	MaxIter int
+	// NIterNoChange is the number of passes without improvement that stops Fit. If zero, 10 is used.
+	NIterNoChange int
+	// Seed seeds the initial weights and the shuffling of samples before each pass, so fits are reproducible.
+	Seed int64
+
+	net   *mlp
+	iters int
+}
+
+func (mr *MLPRegressor[T]) config() (mlpConfig, error) {
+	return mlpConfig{
+		hidden:     mr.HiddenLayers,
+		activation: mr.Activation,
+		optimizer:  mr.Optimizer,
+		alpha:      mr.Alpha,
+		batch:      mr.BatchSize,
+		rate:       mr.LearningRate,
+		momentum:   mr.Momentum,
+		tol:        mr.Tol,
+		maxIter:    mr.MaxIter,
+		patience:   mr.NIterNoChange,
+		seed:       mr.Seed,
+	}.withDefaults()
+}
+
+// Fit trains a new network on the samples in the rows of X, whose targets are the rows of y.
+func (mr *MLPRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if y.rows != X.rows {
+		return fmt.Errorf("MLPRegressor.Fit: y has %d rows, X has %d", y.rows, X.rows)
+	}
+	cfg, err := mr.config()
+	if err != nil {
+		return fmt.Errorf("MLPRegressor.Fit: %w", err)
+	}
+	mr.net = newMLP(cfg, X.cols, y.cols, false)
+	iters, converged := mr.net.fit(cfg, convert[float64](X), convert[float64](y))
+	mr.iters = iters
+	if !converged {
+		return fmt.Errorf("MLPRegressor.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+func (mr *MLPRegressor[T]) PartialFit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if y.rows != X.rows {
+		return fmt.Errorf("MLPRegressor.PartialFit: y has %d rows, X has %d", y.rows, X.rows)
+	}
+	cfg, err := mr.config()
+	if err != nil {
+		return fmt.Errorf("MLPRegressor.PartialFit: %w", err)
+	}
+	if mr.net == nil {
+		mr.net = newMLP(cfg, X.cols, y.cols, false)
+	}
+	if X.cols != mr.net.sizes[0] || y.cols != mr.net.sizes[len(mr.net.sizes)-1] {
+		return fmt.Errorf("MLPRegressor.PartialFit: got (%d x %d) X and (%d x %d) y, model has %d inputs and %d outputs",
+			X.rows, X.cols, y.rows, y.cols, mr.net.sizes[0], mr.net.sizes[len(mr.net.sizes)-1])
+	}
+	mr.net.epoch(convert[float64](X), convert[float64](y), cfg.batch, cfg.alpha)
+	mr.iters++
+	return nil
+}
+
+// Predict returns the outputs of the network for the rows of X.
+func (mr *MLPRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	if mr.net == nil {
+		return nil, fmt.Errorf("MLPRegressor.Predict: model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != mr.net.sizes[0] {
+		return nil, fmt.Errorf("MLPRegressor.Predict: X has %d features, model has %d", X.cols, mr.net.sizes[0])
+	}
+	return convert[T](mr.net.predict(convert[float64](X))), nil
+}
+
+func (mr *MLPRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := mr.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// Iterations returns the number of passes over the data made by the last call to Fit, plus one for each
+// call to PartialFit since.
+func (mr *MLPRegressor[T]) Iterations() int {
+	return mr.iters
+}
+
+// LossCurve returns the mean training loss of each pass over the data, including its penalty.
+func (mr *MLPRegressor[T]) LossCurve() []float64 {
+	if mr.net == nil {
+		return nil
+	}
+	return mr.net.losses
+}
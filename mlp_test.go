@@ -0,0 +1,219 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kipukun/pa/internal/optim"
+)
+
+func TestMLPGradient(t *testing.T) {
+	// backpropagation against central differences, for each activation and both output layers
+	rng := rand.New(rand.NewSource(1))
+	x := make([]float64, 5*3)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	for _, act := range []Activation{ActivationReLU, ActivationTanh, ActivationSigmoid, ActivationIdentity} {
+		for _, softmax := range []bool{false, true} {
+			cfg, _ := mlpConfig{hidden: []int{4, 3}, activation: act, seed: 2}.withDefaults()
+			m := newMLP(cfg, 3, 2, softmax)
+			m.stepper = &optim.GradientDescent{}
+			y := make([]float64, 5*2)
+			for i := 0; i < 5; i++ {
+				if softmax {
+					y[i*2+i%2] = 1
+				} else {
+					y[i*2], y[i*2+1] = rng.NormFloat64(), rng.NormFloat64()
+				}
+			}
+			m.step(x, y, 5, 0.3)
+			grad := append([]float64(nil), m.grad...)
+			for k := range m.params {
+				const h = 1e-6
+				v := m.params[k]
+				m.params[k] = v + h
+				up := m.step(x, y, 5, 0.3)
+				m.params[k] = v - h
+				down := m.step(x, y, 5, 0.3)
+				m.params[k] = v
+				if num := (up - down) / (2 * h); math.Abs(num-grad[k]) > 1e-6 {
+					t.Errorf("activation %d, softmax %v: gradient %d = %v, central difference %v", act, softmax, k, grad[k], num)
+				}
+			}
+		}
+	}
+}
+
+func TestMLPClassifier(t *testing.T) {
+	xor := NewMatrix([][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}, nil)
+	xorLabels := NewMatrix([][]float64{{0}, {1}, {1}, {0}}, nil)
+	tests := []struct {
+		name    string
+		mc      *MLPClassifier[float64]
+		centres [][]float64
+	}{
+		{"adam relu", &MLPClassifier[float64]{LearningRate: 0.01}, [][]float64{{0, 0}, {4, 0}, {0, 4}}},
+		{"sgd tanh", &MLPClassifier[float64]{HiddenLayers: []int{10}, Activation: ActivationTanh, Optimizer: OptimizerSGD, LearningRate: 0.1, BatchSize: 16},
+			[][]float64{{0, 0}, {4, 4}}},
+		{"momentum sigmoid", &MLPClassifier[float64]{HiddenLayers: []int{8, 8}, Activation: ActivationSigmoid, Optimizer: OptimizerMomentum, LearningRate: 0.05, BatchSize: 16},
+			[][]float64{{0, 0}, {4, 0}, {0, 4}}},
+		{"no hidden layers", &MLPClassifier[float64]{HiddenLayers: []int{}, LearningRate: 0.05, Alpha: 1e-3}, [][]float64{{0, 0}, {4, 4}}},
+		{"xor", &MLPClassifier[float64]{HiddenLayers: []int{8}, Activation: ActivationTanh, LearningRate: 0.05, MaxIter: 2000, Seed: 1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := xor, xorLabels
+			if tt.centres != nil {
+				X, y = blobs(tt.centres, 40)
+			}
+			if err := tt.mc.Fit(X, y); err != nil && !errors.Is(err, ErrNoConvergence) {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := tt.mc.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.95 {
+				t.Errorf("Score() = %v, want at least 0.95", acc)
+			}
+			proba, err := tt.mc.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+			if curve := tt.mc.LossCurve(); len(curve) != tt.mc.Iterations() || curve[len(curve)-1] >= curve[0] {
+				t.Errorf("LossCurve() = %v after %d passes, want one decreasing loss per pass", curve, tt.mc.Iterations())
+			}
+		})
+	}
+}
+
+func TestMLPClassifierErrors(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {4, 4}}, 10)
+	tests := []struct {
+		name string
+		mc   *MLPClassifier[float64]
+	}{
+		{"empty layer", &MLPClassifier[float64]{HiddenLayers: []int{5, 0}}},
+		{"negative alpha", &MLPClassifier[float64]{Alpha: -1}},
+		{"momentum of one", &MLPClassifier[float64]{Momentum: 1}},
+		{"unknown optimizer", &MLPClassifier[float64]{Optimizer: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mc.Fit(X, y); err == nil {
+				t.Error("Fit() returned nil error")
+			}
+		})
+	}
+
+	mc := &MLPClassifier[float64]{MaxIter: 2}
+	if err := mc.Fit(X, y); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("Fit() with MaxIter 2 error = %v, want ErrNoConvergence", err)
+	}
+	if _, err := (&MLPClassifier[float64]{}).Predict(X); err == nil {
+		t.Error("Predict() before Fit returned nil error")
+	}
+	if err := (&MLPClassifier[float64]{}).PartialFit(X, y, nil); err == nil {
+		t.Error("PartialFit() without classes returned nil error")
+	}
+}
+
+func TestMLPClassifierPartialFit(t *testing.T) {
+	X, y := shuffled(blobs([][]float64{{0, 0}, {4, 4}}, 50))
+	mc := &MLPClassifier[float64]{HiddenLayers: []int{10}, LearningRate: 0.01, BatchSize: 10}
+	for pass := 0; pass < 20; pass++ {
+		for i := 0; i < X.rows; i += 20 {
+			if err := mc.PartialFit(X.Slice(i, i+20, 0, 2), y.Slice(i, i+20, 0, 1), []float64{0, 1}); err != nil {
+				t.Fatalf("PartialFit() error: %v", err)
+			}
+		}
+	}
+	acc, _ := mc.Score(X, y)
+	if acc < 0.95 {
+		t.Errorf("Score() = %v, want at least 0.95", acc)
+	}
+	if mc.Iterations() != 100 {
+		t.Errorf("Iterations() = %d, want 100", mc.Iterations())
+	}
+	if err := mc.PartialFit(X, NewMatrix([][]float64{{2}}, nil), nil); err == nil {
+		t.Error("PartialFit() with mismatched y returned nil error")
+	}
+}
+
+func TestMLPRegressor(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	X, y := Empty[float64](200, 2), Empty[float64](200, 2)
+	for i := 0; i < X.rows; i++ {
+		a, b := rng.Float64()*2-1, rng.Float64()*2-1
+		X.set(i, 0, a)
+		X.set(i, 1, b)
+		y.set(i, 0, math.Sin(3*a)+b*b)
+		y.set(i, 1, a*b)
+	}
+	tests := []struct {
+		name string
+		mr   *MLPRegressor[float64]
+	}{
+		{"adam relu", &MLPRegressor[float64]{HiddenLayers: []int{50}, LearningRate: 0.01, MaxIter: 500}},
+		{"adam tanh", &MLPRegressor[float64]{HiddenLayers: []int{20, 20}, Activation: ActivationTanh, LearningRate: 0.01, MaxIter: 500}},
+		{"momentum", &MLPRegressor[float64]{HiddenLayers: []int{30}, Activation: ActivationTanh, Optimizer: OptimizerMomentum,
+			LearningRate: 0.05, BatchSize: 20, MaxIter: 500}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.mr.Fit(X, y); err != nil && !errors.Is(err, ErrNoConvergence) {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			yh, err := tt.mr.Predict(X)
+			if err != nil {
+				t.Fatalf("Predict() error: %v", err)
+			}
+			for c := 0; c < 2; c++ {
+				if r2 := r2Score(y.Slice(0, y.rows, c, c+1), yh.Slice(0, yh.rows, c, c+1)); r2 < 0.9 {
+					t.Errorf("R² of output %d = %v, want at least 0.9", c, r2)
+				}
+			}
+			if r2, _ := tt.mr.Score(X, y); r2 < 0.9 {
+				t.Errorf("Score() = %v, want at least 0.9", r2)
+			}
+		})
+	}
+}
+
+func TestMLPRegressorFloat32(t *testing.T) {
+	X, y := Empty[float32](100, 1), Empty[float32](100, 1)
+	for i := 0; i < X.rows; i++ {
+		x := float32(i)/50 - 1
+		X.set(i, 0, x)
+		y.set(i, 0, x*x)
+	}
+	mr := &MLPRegressor[float32]{HiddenLayers: []int{20}, Activation: ActivationTanh, LearningRate: 0.01, MaxIter: 1000, Seed: 4}
+	if err := mr.Fit(X, y); err != nil && !errors.Is(err, ErrNoConvergence) {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if r2, _ := mr.Score(X, y); r2 < 0.95 {
+		t.Errorf("Score() = %v, want at least 0.95", r2)
+	}
+
+	// PartialFit on a fresh model picks its shape from the first batch
+	online := &MLPRegressor[float32]{HiddenLayers: []int{20}, Activation: ActivationTanh, LearningRate: 0.01, BatchSize: 10, Seed: 4}
+	for pass := 0; pass < 300; pass++ {
+		if err := online.PartialFit(X, y); err != nil {
+			t.Fatalf("PartialFit() error: %v", err)
+		}
+	}
+	if r2, _ := online.Score(X, y); r2 < 0.9 {
+		t.Errorf("Score() after PartialFit = %v, want at least 0.9", r2)
+	}
+	if err := online.PartialFit(Empty[float32](3, 2), Empty[float32](3, 1)); err == nil {
+		t.Error("PartialFit() with a new feature count returned nil error")
+	}
+}
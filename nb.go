@@ -0,0 +1,605 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+)
+
+// nbLikelihood is the part of a naive Bayes model that differs between event models:
+// how features are distributed within each class.
+type nbLikelihood interface {
+	// check reports whether the model can learn from the sample x.
+	check(x []float64) error
+	// add learns from the sample x of class c.
+	add(x []float64, c int)
+	// prepare is called before a batch of calls to logLikelihood, so the model can precompute its parameters.
+	prepare()
+	// logLikelihood writes log P(x | c) for each class c into out.
+	logLikelihood(x, out []float64)
+}
+
+// naiveBayes holds the class bookkeeping shared by the naive Bayes models, and predicts from
+// the log-likelihoods of their event model under the assumption that features are independent within a class.
+type naiveBayes[T Float] struct {
+	name    string
+	classes []T
+	count   []float64
+	p       int
+	priors  []float64
+	model   nbLikelihood
+}
+
+// partialFit learns from a batch, calling init with the number of classes and features on first use.
+func (nb *naiveBayes[T]) partialFit(X, y *Matrix[T], classes []T, priors []float64, init func(k, p int)) error {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	cs := nb.classes
+	if cs == nil {
+		var err error
+		if cs, err = initClasses(classes); err != nil {
+			return err
+		}
+	}
+	if priors != nil {
+		if len(priors) != len(cs) {
+			return fmt.Errorf("got %d priors for %d classes", len(priors), len(cs))
+		}
+		var total float64
+		for _, p := range priors {
+			if p < 0 {
+				return fmt.Errorf("priors must not be negative, got %v", priors)
+			}
+			total += p
+		}
+		if math.Abs(total-1) > 1e-9 {
+			return fmt.Errorf("priors must sum to 1, got %v", total)
+		}
+	}
+	if nb.classes == nil {
+		nb.classes, nb.count, nb.p = cs, make([]float64, len(cs)), X.cols
+		init(len(cs), X.cols)
+	}
+	nb.priors = priors
+	labels, err := indexLabels(X, y, nb.classes, nb.p)
+	if err != nil {
+		return err
+	}
+	xs := convert[float64](X)
+	for i := 0; i < xs.rows; i++ {
+		if err := nb.model.check(xs.row(i)); err != nil {
+			return fmt.Errorf("sample %d: %w", i, err)
+		}
+	}
+	for i, c := range labels {
+		nb.model.add(xs.row(i), c)
+		nb.count[c]++
+	}
+	return nil
+}
+
+// jointLogLikelihood returns log P(c) + log P(x | c) for each row x of X and each class c.
+func (nb *naiveBayes[T]) jointLogLikelihood(X *Matrix[T]) (*Matrix[float64], error) {
+	if nb.model == nil {
+		return nil, fmt.Errorf("model is not fitted")
+	}
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != nb.p {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, nb.p)
+	}
+	var total float64
+	for _, n := range nb.count {
+		total += n
+	}
+	prior := make([]float64, len(nb.classes))
+	for c := range prior {
+		if nb.priors != nil {
+			prior[c] = math.Log(nb.priors[c])
+		} else {
+			prior[c] = math.Log(nb.count[c] / total)
+		}
+	}
+	nb.model.prepare()
+	xs := convert[float64](X)
+	jll := Empty[float64](X.rows, len(nb.classes))
+	for i := 0; i < X.rows; i++ {
+		out := jll.row(i)
+		nb.model.logLikelihood(xs.row(i), out)
+		for c := range out {
+			out[c] += prior[c]
+		}
+	}
+	return jll, nil
+}
+
+// PredictLogProba returns the log of the posterior probability of each class for the rows of X,
+// with columns in the order of Classes. It stays accurate where PredictProba would underflow to zero.
+func (nb *naiveBayes[T]) PredictLogProba(X *Matrix[T]) (*Matrix[T], error) {
+	jll, err := nb.jointLogLikelihood(X)
+	if err != nil {
+		return nil, fmt.Errorf("%s.PredictLogProba: %w", nb.name, err)
+	}
+	for i := 0; i < jll.rows; i++ {
+		row := jll.row(i)
+		lse := logSumExp(row)
+		for c := range row {
+			row[c] -= lse
+		}
+	}
+	return convert[T](jll), nil
+}
+
+// PredictProba returns the posterior probability of each class for the rows of X, with columns in the order of Classes.
+func (nb *naiveBayes[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	lp, err := nb.PredictLogProba(X)
+	if err != nil {
+		return nil, err
+	}
+	return lp.Apply(func(v T) T { return T(math.Exp(float64(v))) }), nil
+}
+
+// Predict returns the class with the largest posterior probability for each row of X.
+func (nb *naiveBayes[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	jll, err := nb.jointLogLikelihood(X)
+	if err != nil {
+		return nil, fmt.Errorf("%s.Predict: %w", nb.name, err)
+	}
+	return decodeLabels(convert[T](jll), nb.classes), nil
+}
+
+func (nb *naiveBayes[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := nb.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+// Classes returns the classes the model was fitted to, in ascending order.
+func (nb *naiveBayes[T]) Classes() []T {
+	return nb.classes
+}
+
+// ClassCount returns the number of training samples seen of each class, in the order of Classes.
+func (nb *naiveBayes[T]) ClassCount() []float64 {
+	return nb.count
+}
+
+// smoothing returns the additive smoothing alpha, or 1 if it is zero.
+func smoothing(alpha float64) (float64, error) {
+	if alpha < 0 {
+		return 0, fmt.Errorf("Alpha must not be negative, got %v", alpha)
+	}
+	if alpha == 0 {
+		return 1, nil
+	}
+	return alpha, nil
+}
+
+// GaussianNB is naive Bayes for continuous features, each modelled by a normal distribution within each class.
+type GaussianNB[T Float] struct {
+	// Priors are the prior probabilities of the classes, in ascending order of class.
+	// If nil, they are estimated from the class frequencies in the training data.
+	Priors []float64
+	// VarSmoothing is added to every variance, as a fraction of the largest variance of any feature,
+	// to keep features that are constant within a class from dominating. If zero, 1e-9 is used.
+	VarSmoothing float64
+
+	naiveBayes[T]
+	stats     []welford // per class
+	all       welford
+	logVar    [][]float64
+	variances [][]float64
+}
+
+// welford accumulates the mean of each feature and the sum of squared deviations from it, one sample at a time.
+type welford struct {
+	n        float64
+	mean, m2 []float64
+}
+
+func newWelford(p int) welford {
+	return welford{mean: make([]float64, p), m2: make([]float64, p)}
+}
+
+func (w *welford) add(x []float64) {
+	w.n++
+	for j, v := range x {
+		d := v - w.mean[j]
+		w.mean[j] += d / w.n
+		w.m2[j] += d * (v - w.mean[j])
+	}
+}
+
+// Fit fits the model from scratch to the samples in the rows of X, whose labels are in the column vector y.
+func (g *GaussianNB[T]) Fit(X, y *Matrix[T]) (err error) {
+	if y.Err() != nil {
+		return y.Err()
+	}
+	classes, _ := encodeLabels(y)
+	g.naiveBayes = naiveBayes[T]{}
+	if err := g.learn(X, y, classes); err != nil {
+		return fmt.Errorf("GaussianNB.Fit: %w", err)
+	}
+	return nil
+}
+
+// PartialFit updates the model with the mini-batch X and y.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (g *GaussianNB[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if err := g.learn(X, y, classes); err != nil {
+		return fmt.Errorf("GaussianNB.PartialFit: %w", err)
+	}
+	return nil
+}
+
+// learn validates the settings and learns from a batch.
+func (g *GaussianNB[T]) learn(X, y *Matrix[T], classes []T) error {
+	if g.VarSmoothing < 0 {
+		return fmt.Errorf("VarSmoothing must not be negative, got %v", g.VarSmoothing)
+	}
+	g.name, g.model = "GaussianNB", g
+	return g.partialFit(X, y, classes, g.Priors, func(k, p int) {
+		g.stats = make([]welford, k)
+		for c := range g.stats {
+			g.stats[c] = newWelford(p)
+		}
+		g.all = newWelford(p)
+	})
+}
+
+func (g *GaussianNB[T]) check(x []float64) error {
+	return nil
+}
+
+func (g *GaussianNB[T]) add(x []float64, c int) {
+	g.stats[c].add(x)
+	g.all.add(x)
+}
+
+func (g *GaussianNB[T]) prepare() {
+	smooth := g.VarSmoothing
+	if smooth == 0 {
+		smooth = 1e-9
+	}
+	var maxVar float64
+	for _, m2 := range g.all.m2 {
+		maxVar = math.Max(maxVar, m2/g.all.n)
+	}
+	eps := smooth * maxVar
+	if eps == 0 {
+		eps = smooth
+	}
+	g.variances, g.logVar = make([][]float64, len(g.classes)), make([][]float64, len(g.classes))
+	for c := range g.variances {
+		g.variances[c], g.logVar[c] = make([]float64, g.p), make([]float64, g.p)
+		for j := range g.variances[c] {
+			v := eps
+			if s := g.stats[c]; s.n > 0 {
+				v += s.m2[j] / s.n
+			}
+			g.variances[c][j], g.logVar[c][j] = v, math.Log(2*math.Pi*v)
+		}
+	}
+}
+
+func (g *GaussianNB[T]) logLikelihood(x, out []float64) {
+	for c := range out {
+		var ll float64
+		for j, v := range x {
+			d := v - g.stats[c].mean[j]
+			ll -= (g.logVar[c][j] + d*d/g.variances[c][j]) / 2
+		}
+		out[c] = ll
+	}
+}
+
+// Theta returns the mean of each feature within each class, as a k x p matrix.
+func (g *GaussianNB[T]) Theta() *Matrix[T] {
+	theta := Empty[T](len(g.stats), g.p)
+	for c, s := range g.stats {
+		for j, v := range s.mean {
+			theta.set(c, j, T(v))
+		}
+	}
+	return theta
+}
+
+// MultinomialNB is naive Bayes for count features, such as word counts in documents.
+// Each class is a multinomial distribution over the features, estimated with additive smoothing.
+type MultinomialNB[T Float] struct {
+	// Alpha is the pseudo-count added to every feature of every class. If zero, 1 is used,
+	// which is Laplace smoothing.
+	Alpha float64
+	// Priors are the prior probabilities of the classes, in ascending order of class.
+	// If nil, they are estimated from the class frequencies in the training data.
+	Priors []float64
+
+	naiveBayes[T]
+	feature  [][]float64 // per class, the total of each feature
+	logTheta [][]float64
+}
+
+// Fit fits the model from scratch to the samples in the rows of X, whose labels are in the column vector y.
+func (mn *MultinomialNB[T]) Fit(X, y *Matrix[T]) (err error) {
+	if y.Err() != nil {
+		return y.Err()
+	}
+	classes, _ := encodeLabels(y)
+	mn.naiveBayes = naiveBayes[T]{}
+	if err := mn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("MultinomialNB.Fit: %w", err)
+	}
+	return nil
+}
+
+// PartialFit updates the model with the mini-batch X and y.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (mn *MultinomialNB[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if err := mn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("MultinomialNB.PartialFit: %w", err)
+	}
+	return nil
+}
+
+// learn validates the settings and learns from a batch.
+func (mn *MultinomialNB[T]) learn(X, y *Matrix[T], classes []T) error {
+	if _, err := smoothing(mn.Alpha); err != nil {
+		return err
+	}
+	mn.name, mn.model = "MultinomialNB", mn
+	return mn.partialFit(X, y, classes, mn.Priors, func(k, p int) {
+		mn.feature = make([][]float64, k)
+		for c := range mn.feature {
+			mn.feature[c] = make([]float64, p)
+		}
+	})
+}
+
+func (mn *MultinomialNB[T]) check(x []float64) error {
+	for j, v := range x {
+		if v < 0 {
+			return fmt.Errorf("feature %d is negative (%v); MultinomialNB needs counts", j, v)
+		}
+	}
+	return nil
+}
+
+func (mn *MultinomialNB[T]) add(x []float64, c int) {
+	axpy(1, x, mn.feature[c])
+}
+
+func (mn *MultinomialNB[T]) prepare() {
+	alpha, _ := smoothing(mn.Alpha)
+	mn.logTheta = make([][]float64, len(mn.feature))
+	for c, f := range mn.feature {
+		logTotal := math.Log(sum(f) + alpha*float64(len(f)))
+		mn.logTheta[c] = make([]float64, len(f))
+		for j, v := range f {
+			mn.logTheta[c][j] = math.Log(v+alpha) - logTotal
+		}
+	}
+}
+
+func (mn *MultinomialNB[T]) logLikelihood(x, out []float64) {
+	for c := range out {
+		out[c] = dotf(x, mn.logTheta[c])
+	}
+}
+
+// FeatureLogProb returns the smoothed log probability of each feature within each class, as a k x p matrix.
+func (mn *MultinomialNB[T]) FeatureLogProb() *Matrix[T] {
+	mn.prepare()
+	return convert[T](NewMatrix(mn.logTheta, nil))
+}
+
+// BernoulliNB is naive Bayes for binary features, each a coin flip within each class, estimated with
+// additive smoothing. Unlike MultinomialNB, the absence of a feature counts as evidence too.
+type BernoulliNB[T Float] struct {
+	// Alpha is the pseudo-count added to both outcomes of every feature. If zero, 1 is used,
+	// which is Laplace smoothing.
+	Alpha float64
+	// Binarize is the threshold above which a feature counts as present.
+	// The zero value suits data that is already 0 or 1.
+	Binarize float64
+	// Priors are the prior probabilities of the classes, in ascending order of class.
+	// If nil, they are estimated from the class frequencies in the training data.
+	Priors []float64
+
+	naiveBayes[T]
+	present          [][]float64 // per class, how often each feature was present
+	logP, logAbsence [][]float64
+}
+
+// Fit fits the model from scratch to the samples in the rows of X, whose labels are in the column vector y.
+func (bn *BernoulliNB[T]) Fit(X, y *Matrix[T]) (err error) {
+	if y.Err() != nil {
+		return y.Err()
+	}
+	classes, _ := encodeLabels(y)
+	bn.naiveBayes = naiveBayes[T]{}
+	if err := bn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("BernoulliNB.Fit: %w", err)
+	}
+	return nil
+}
+
+// PartialFit updates the model with the mini-batch X and y.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (bn *BernoulliNB[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if err := bn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("BernoulliNB.PartialFit: %w", err)
+	}
+	return nil
+}
+
+// learn validates the settings and learns from a batch.
+func (bn *BernoulliNB[T]) learn(X, y *Matrix[T], classes []T) error {
+	if _, err := smoothing(bn.Alpha); err != nil {
+		return err
+	}
+	bn.name, bn.model = "BernoulliNB", bn
+	return bn.partialFit(X, y, classes, bn.Priors, func(k, p int) {
+		bn.present = make([][]float64, k)
+		for c := range bn.present {
+			bn.present[c] = make([]float64, p)
+		}
+	})
+}
+
+func (bn *BernoulliNB[T]) check(x []float64) error {
+	return nil
+}
+
+func (bn *BernoulliNB[T]) add(x []float64, c int) {
+	for j, v := range x {
+		if v > bn.Binarize {
+			bn.present[c][j]++
+		}
+	}
+}
+
+func (bn *BernoulliNB[T]) prepare() {
+	alpha, _ := smoothing(bn.Alpha)
+	k := len(bn.present)
+	bn.logP, bn.logAbsence = make([][]float64, k), make([][]float64, k)
+	for c, f := range bn.present {
+		logTotal := math.Log(bn.count[c] + 2*alpha)
+		bn.logP[c], bn.logAbsence[c] = make([]float64, len(f)), make([]float64, len(f))
+		for j, v := range f {
+			bn.logP[c][j] = math.Log(v+alpha) - logTotal
+			bn.logAbsence[c][j] = math.Log(bn.count[c]-v+alpha) - logTotal
+		}
+	}
+}
+
+func (bn *BernoulliNB[T]) logLikelihood(x, out []float64) {
+	for c := range out {
+		var ll float64
+		for j, v := range x {
+			if v > bn.Binarize {
+				ll += bn.logP[c][j]
+			} else {
+				ll += bn.logAbsence[c][j]
+			}
+		}
+		out[c] = ll
+	}
+}
+
+// FeatureLogProb returns the smoothed log probability that each feature is present within each class, as a k x p matrix.
+func (bn *BernoulliNB[T]) FeatureLogProb() *Matrix[T] {
+	bn.prepare()
+	return convert[T](NewMatrix(bn.logP, nil))
+}
+
+// CategoricalNB is naive Bayes for categorical features, each coded as a non-negative integer.
+// Within each class, each feature has its own distribution over its categories, estimated with additive smoothing.
+// A category not seen in training gets only the smoothing pseudo-count.
+type CategoricalNB[T Float] struct {
+	// Alpha is the pseudo-count added to every category of every feature of every class.
+	// If zero, 1 is used, which is Laplace smoothing.
+	Alpha float64
+	// Priors are the prior probabilities of the classes, in ascending order of class.
+	// If nil, they are estimated from the class frequencies in the training data.
+	Priors []float64
+
+	naiveBayes[T]
+	counts   [][][]float64 // per feature, class and category, the number of samples
+	logP     [][][]float64
+	logNever [][]float64 // per feature and class, the log probability of a category never seen
+}
+
+// Fit fits the model from scratch to the samples in the rows of X, whose labels are in the column vector y.
+func (cn *CategoricalNB[T]) Fit(X, y *Matrix[T]) (err error) {
+	if y.Err() != nil {
+		return y.Err()
+	}
+	classes, _ := encodeLabels(y)
+	cn.naiveBayes = naiveBayes[T]{}
+	if err := cn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("CategoricalNB.Fit: %w", err)
+	}
+	return nil
+}
+
+// PartialFit updates the model with the mini-batch X and y. Later batches may introduce new categories.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (cn *CategoricalNB[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if err := cn.learn(X, y, classes); err != nil {
+		return fmt.Errorf("CategoricalNB.PartialFit: %w", err)
+	}
+	return nil
+}
+
+// learn validates the settings and learns from a batch.
+func (cn *CategoricalNB[T]) learn(X, y *Matrix[T], classes []T) error {
+	if _, err := smoothing(cn.Alpha); err != nil {
+		return err
+	}
+	cn.name, cn.model = "CategoricalNB", cn
+	return cn.partialFit(X, y, classes, cn.Priors, func(k, p int) {
+		cn.counts = make([][][]float64, p)
+		for j := range cn.counts {
+			cn.counts[j] = make([][]float64, k)
+		}
+	})
+}
+
+func (cn *CategoricalNB[T]) check(x []float64) error {
+	for j, v := range x {
+		if v < 0 || v != math.Trunc(v) {
+			return fmt.Errorf("feature %d is %v; CategoricalNB needs categories coded as non-negative integers", j, v)
+		}
+	}
+	return nil
+}
+
+func (cn *CategoricalNB[T]) add(x []float64, c int) {
+	for j, v := range x {
+		cat := int(v)
+		if cat >= len(cn.counts[j][c]) {
+			for cc := range cn.counts[j] {
+				grown := make([]float64, cat+1)
+				copy(grown, cn.counts[j][cc])
+				cn.counts[j][cc] = grown
+			}
+		}
+		cn.counts[j][c][cat]++
+	}
+}
+
+func (cn *CategoricalNB[T]) prepare() {
+	alpha, _ := smoothing(cn.Alpha)
+	cn.logP, cn.logNever = make([][][]float64, cn.p), make([][]float64, cn.p)
+	for j, byClass := range cn.counts {
+		cn.logP[j], cn.logNever[j] = make([][]float64, len(byClass)), make([]float64, len(byClass))
+		for c, counts := range byClass {
+			logTotal := math.Log(cn.count[c] + alpha*float64(len(counts)))
+			cn.logP[j][c] = make([]float64, len(counts))
+			for cat, n := range counts {
+				cn.logP[j][c][cat] = math.Log(n+alpha) - logTotal
+			}
+			cn.logNever[j][c] = math.Log(alpha) - logTotal
+		}
+	}
+}
+
+func (cn *CategoricalNB[T]) logLikelihood(x, out []float64) {
+	for c := range out {
+		var ll float64
+		for j, v := range x {
+			if cat := int(v); v >= 0 && cat < len(cn.logP[j][c]) {
+				ll += cn.logP[j][c][cat]
+			} else {
+				ll += cn.logNever[j][c]
+			}
+		}
+		out[c] = ll
+	}
+}
@@ -0,0 +1,234 @@
+package pa
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// naiveBayesModel is what every naive Bayes model offers.
+type naiveBayesModel interface {
+	OnlineClassifier[float64]
+	PredictLogProba(X *Matrix[float64]) (*Matrix[float64], error)
+}
+
+// countBlobs returns two classes of samples whose features are non-negative integers.
+func countBlobs() (*Matrix[float64], *Matrix[float64]) {
+	X, y := blobs([][]float64{{2, 8, 5}, {8, 2, 5}}, 50)
+	return X.Apply(func(v float64) float64 { return math.Max(0, math.Round(v)) }), y
+}
+
+func TestNaiveBayes(t *testing.T) {
+	X, y := countBlobs()
+	tests := []struct {
+		name  string
+		model func() naiveBayesModel
+	}{
+		{"gaussian", func() naiveBayesModel { return &GaussianNB[float64]{} }},
+		{"multinomial", func() naiveBayesModel { return &MultinomialNB[float64]{} }},
+		{"bernoulli", func() naiveBayesModel { return &BernoulliNB[float64]{Binarize: 5} }},
+		{"categorical", func() naiveBayesModel { return &CategoricalNB[float64]{Alpha: 0.5} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.model()
+			if err := m.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := m.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.9 {
+				t.Errorf("Score() = %v, want at least 0.9", acc)
+			}
+			proba, err := m.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			logProba, err := m.PredictLogProba(X)
+			if err != nil {
+				t.Fatalf("PredictLogProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+				for c, v := range logProba.row(i) {
+					if math.Abs(math.Exp(v)-proba.at(i, c)) > 1e-12 {
+						t.Fatalf("PredictLogProba() and PredictProba() disagree at (%d, %d)", i, c)
+					}
+				}
+			}
+
+			// learning in two batches is the same as learning all at once
+			Xs, ys := shuffled(X, y)
+			half := Xs.rows / 2
+			first, rest := make([]int, half), make([]int, Xs.rows-half)
+			for i := range first {
+				first[i] = i
+			}
+			for i := range rest {
+				rest[i] = half + i
+			}
+			online := tt.model()
+			if err := online.PartialFit(Xs.pick(first), ys.pick(first), []float64{1, 0}); err != nil {
+				t.Fatalf("PartialFit() error: %v", err)
+			}
+			if err := online.PartialFit(Xs.pick(rest), ys.pick(rest), nil); err != nil {
+				t.Fatalf("PartialFit() error: %v", err)
+			}
+			onlineLog, err := online.PredictLogProba(X)
+			if err != nil {
+				t.Fatalf("PredictLogProba() error: %v", err)
+			}
+			for i := 0; i < logProba.rows; i++ {
+				for c, v := range logProba.row(i) {
+					if math.Abs(onlineLog.at(i, c)-v) > 1e-6*math.Max(1, math.Abs(v)) {
+						t.Fatalf("PartialFit() log probability at (%d, %d) = %v, Fit() gave %v", i, c, onlineLog.at(i, c), v)
+					}
+				}
+			}
+
+			if _, err := tt.model().Predict(X); err == nil {
+				t.Error("Predict() before Fit() returned nil error")
+			}
+			if err := tt.model().PartialFit(X, y, nil); err == nil {
+				t.Error("first PartialFit() without classes returned nil error")
+			}
+			if _, err := m.Predict(Empty[float64](1, 2)); err == nil {
+				t.Error("Predict() with the wrong number of features returned nil error")
+			}
+		})
+	}
+}
+
+func TestNaiveBayesPriors(t *testing.T) {
+	X := NewMatrix([][]float64{{1, 0}, {0, 1}, {1, 0}, {0, 1}}, nil)
+	y := NewMatrix([][]float64{{0}, {1}, {0}, {1}}, nil)
+	// a sample with no features set is equally likely under either class, so the prior decides
+	blank := Empty[float64](1, 2)
+	tests := []struct {
+		name   string
+		priors []float64
+		want   float64
+	}{
+		{"favour 0", []float64{0.9, 0.1}, 0},
+		{"favour 1", []float64{0.2, 0.8}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn := &BernoulliNB[float64]{Priors: tt.priors}
+			if err := bn.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			proba, _ := bn.PredictProba(blank)
+			if math.Abs(proba.at(0, 1)-tt.priors[1]) > 1e-12 {
+				t.Errorf("PredictProba() = %v, want the priors %v", proba.row(0), tt.priors)
+			}
+			if yh, _ := bn.Predict(blank); yh.at(0, 0) != tt.want {
+				t.Errorf("Predict() = %v, want %v", yh.at(0, 0), tt.want)
+			}
+		})
+	}
+
+	for _, priors := range [][]float64{{1}, {0.5, 0.6}, {-0.5, 1.5}} {
+		if err := (&MultinomialNB[float64]{Priors: priors}).Fit(X, y); err == nil {
+			t.Errorf("Fit() with priors %v returned nil error", priors)
+		}
+	}
+}
+
+func TestNaiveBayesEstimates(t *testing.T) {
+	X := NewMatrix([][]float64{{2, 1}, {0, 0}, {0, 3}, {1, 2}}, nil)
+	y := NewMatrix([][]float64{{0}, {0}, {1}, {1}}, nil)
+
+	mn := &MultinomialNB[float64]{}
+	if err := mn.Fit(X, y); err != nil {
+		t.Fatalf("MultinomialNB.Fit() error: %v", err)
+	}
+	// feature totals are (2, 1) and (1, 5); with Laplace smoothing (3, 2)/5 and (2, 6)/8
+	assertLogs(t, "MultinomialNB.FeatureLogProb()", mn.FeatureLogProb(), [][]float64{{3.0 / 5, 2.0 / 5}, {2.0 / 8, 6.0 / 8}})
+	if err := mn.Fit(NewMatrix([][]float64{{-1, 0}, {0, 1}}, nil), NewMatrix([][]float64{{0}, {1}}, nil)); err == nil {
+		t.Error("MultinomialNB.Fit() with a negative count returned nil error")
+	}
+
+	bn := &BernoulliNB[float64]{}
+	if err := bn.Fit(X, y); err != nil {
+		t.Fatalf("BernoulliNB.Fit() error: %v", err)
+	}
+	// each feature is present in one of the two samples of class 0, and feature 1 in both of class 1:
+	// (1+1)/(2+2) and (2+1)/(2+2)
+	assertLogs(t, "BernoulliNB.FeatureLogProb()", bn.FeatureLogProb(), [][]float64{{0.5, 0.5}, {0.5, 0.75}})
+
+	g := &GaussianNB[float64]{}
+	if err := g.Fit(X, y); err != nil {
+		t.Fatalf("GaussianNB.Fit() error: %v", err)
+	}
+	if theta, want := g.Theta(), []float64{1, 0.5, 0.5, 2.5}; !reflect.DeepEqual(theta.data, want) {
+		t.Errorf("GaussianNB.Theta() = %v, want %v", theta.data, want)
+	}
+
+	cn := &CategoricalNB[float64]{}
+	if err := cn.Fit(X, y); err != nil {
+		t.Fatalf("CategoricalNB.Fit() error: %v", err)
+	}
+	// feature 0 has categories 0..2 and feature 1 has 0..3; class 0 saw (2, 1) once and (0, 0) once
+	got, err := cn.PredictLogProba(NewMatrix([][]float64{{2, 1}, {9, 9}}, nil))
+	if err != nil {
+		t.Fatalf("CategoricalNB.PredictLogProba() error: %v", err)
+	}
+	joint := [][]float64{
+		{0.5 * 2.0 / 5 * 2.0 / 6, 0.5 * 1.0 / 5 * 1.0 / 6},
+		// unseen categories get only the pseudo-count
+		{0.5 * 1.0 / 5 * 1.0 / 6, 0.5 * 1.0 / 5 * 1.0 / 6},
+	}
+	for i, row := range joint {
+		total := row[0] + row[1]
+		for c, v := range row {
+			if want := math.Log(v / total); math.Abs(got.at(i, c)-want) > 1e-12 {
+				t.Errorf("CategoricalNB.PredictLogProba() at (%d, %d) = %v, want %v", i, c, got.at(i, c), want)
+			}
+		}
+	}
+	if err := cn.PartialFit(NewMatrix([][]float64{{0.5, 1}}, nil), NewMatrix([][]float64{{0}}, nil), nil); err == nil {
+		t.Error("CategoricalNB.PartialFit() with a fractional category returned nil error")
+	}
+}
+
+// assertLogs checks that the matrix got holds the logarithms of want.
+func assertLogs(t *testing.T, name string, got *Matrix[float64], want [][]float64) {
+	t.Helper()
+	for i, row := range want {
+		for j, v := range row {
+			if math.Abs(got.at(i, j)-math.Log(v)) > 1e-12 {
+				t.Errorf("%s at (%d, %d) = %v, want log(%v)", name, i, j, got.at(i, j), v)
+			}
+		}
+	}
+}
+
+func TestBernoulliNBAgaricus(t *testing.T) {
+	// the mushroom data is one-hot encoded categorical attributes, the textbook case for BernoulliNB
+	f, err := os.Open("agaricus.txt.train")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	X, y, err := ReadLibSVM(f)
+	if err != nil {
+		t.Fatalf("ReadLibSVM() error: %v", err)
+	}
+	bn := &BernoulliNB[float64]{}
+	if err := bn.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	acc, err := bn.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if acc < 0.9 {
+		t.Errorf("Score() = %v, want at least 0.9", acc)
+	}
+}
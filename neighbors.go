@@ -0,0 +1,371 @@
+package pa
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Neighbor is a point found by a nearest neighbour search: its row in the indexed matrix
+// and its distance from the query.
+type Neighbor struct {
+	Index    int
+	Distance float64
+}
+
+// NeighborIndex answers nearest neighbour queries against the rows of a fixed matrix.
+// Both queries return, for each row of X, its neighbours ordered by increasing distance,
+// with ties broken by index.
+type NeighborIndex[T Number] interface {
+	// Query returns the k nearest indexed points to each row of X.
+	Query(X *Matrix[T], k int) ([][]Neighbor, error)
+	// RadiusQuery returns every indexed point within distance r of each row of X.
+	RadiusQuery(X *Matrix[T], r float64) ([][]Neighbor, error)
+}
+
+// leafSize is the largest number of points in a leaf of the tree indexes.
+const leafSize = 20
+
+// tree is a binary space partitioning of the rows of data. Each node owns a contiguous range of idx,
+// and lower bounds the distance from a query to any point the node owns.
+type tree[T Number] struct {
+	data   *Matrix[T]
+	metric Metric[T]
+	idx    []int
+	nodes  []node[T]
+	lower  func(nd *node[T], q Array[T]) float64
+	err    error
+}
+
+type node[T Number] struct {
+	lo, hi      int // the node owns idx[lo:hi]
+	left, right int // the children, or -1 in a leaf
+	min, max    Array[T]
+	centre      Array[T]
+	radius      float64
+}
+
+// build partitions the rows of data, splitting each node at the median of its widest coordinate
+// until it holds at most leaf points.
+func (t *tree[T]) build(data *Matrix[T], leaf int) {
+	if data.err != nil {
+		t.err = data.err
+		return
+	}
+	if data.rows == 0 {
+		t.err = fmt.Errorf("cannot index an empty matrix")
+		return
+	}
+	t.data = data
+	t.idx = make([]int, data.rows)
+	for i := range t.idx {
+		t.idx[i] = i
+	}
+	t.split(0, data.rows, leaf)
+}
+
+func (t *tree[T]) split(lo, hi, leaf int) int {
+	p := t.data.cols
+	nd := node[T]{lo: lo, hi: hi, left: -1, right: -1, min: make(Array[T], p), max: make(Array[T], p)}
+	copy(nd.min, t.data.row(t.idx[lo]))
+	copy(nd.max, t.data.row(t.idx[lo]))
+	for _, i := range t.idx[lo+1 : hi] {
+		for j, v := range t.data.row(i) {
+			if v < nd.min[j] {
+				nd.min[j] = v
+			}
+			if v > nd.max[j] {
+				nd.max[j] = v
+			}
+		}
+	}
+	at := len(t.nodes)
+	t.nodes = append(t.nodes, nd)
+	if hi-lo <= leaf {
+		return at
+	}
+	dim := 0
+	for j := range nd.min {
+		if nd.max[j]-nd.min[j] > nd.max[dim]-nd.min[dim] {
+			dim = j
+		}
+	}
+	if nd.max[dim] == nd.min[dim] {
+		// every point in the node is identical; there is nothing to split
+		return at
+	}
+	part := t.idx[lo:hi]
+	sort.Slice(part, func(a, b int) bool { return t.data.at(part[a], dim) < t.data.at(part[b], dim) })
+	mid := (lo + hi) / 2
+	left := t.split(lo, mid, leaf)
+	right := t.split(mid, hi, leaf)
+	t.nodes[at].left, t.nodes[at].right = left, right
+	return at
+}
+
+// check validates the queries in X against the index.
+func (t *tree[T]) check(X *Matrix[T]) error {
+	if t.err != nil {
+		return t.err
+	}
+	if X.err != nil {
+		return X.err
+	}
+	if X.cols != t.data.cols {
+		return fmt.Errorf("queries have %d features, index has %d", X.cols, t.data.cols)
+	}
+	return nil
+}
+
+// Query returns the k nearest indexed points to each row of X, nearest first.
+func (t *tree[T]) Query(X *Matrix[T], k int) ([][]Neighbor, error) {
+	if err := t.check(X); err != nil {
+		return nil, fmt.Errorf("Query: %w", err)
+	}
+	if k < 1 || k > t.data.rows {
+		return nil, fmt.Errorf("Query: k must be in [1, %d], got %d", t.data.rows, k)
+	}
+	out := make([][]Neighbor, X.rows)
+	for i := range out {
+		out[i] = t.nearest(X.row(i), k)
+	}
+	return out, nil
+}
+
+// RadiusQuery returns every indexed point within distance r of each row of X, nearest first.
+func (t *tree[T]) RadiusQuery(X *Matrix[T], r float64) ([][]Neighbor, error) {
+	if err := t.check(X); err != nil {
+		return nil, fmt.Errorf("RadiusQuery: %w", err)
+	}
+	if r < 0 {
+		return nil, fmt.Errorf("RadiusQuery: radius must not be negative, got %v", r)
+	}
+	out := make([][]Neighbor, X.rows)
+	for i := range out {
+		out[i] = t.within(X.row(i), r)
+	}
+	return out, nil
+}
+
+// nearest returns the k nearest points to q by depth-first search, visiting the nearer child first
+// and skipping any node that cannot hold a point closer than the kth best so far.
+func (t *tree[T]) nearest(q Array[T], k int) []Neighbor {
+	h := make(neighborHeap, 0, k)
+	var visit func(i int)
+	visit = func(i int) {
+		nd := &t.nodes[i]
+		if len(h) == k && t.lower(nd, q) > h[0].Distance {
+			return
+		}
+		if nd.left < 0 {
+			for _, j := range t.idx[nd.lo:nd.hi] {
+				n := Neighbor{j, t.metric.Distance(q, t.data.row(j))}
+				switch {
+				case len(h) < k:
+					heap.Push(&h, n)
+				case closer(n, h[0]):
+					h[0] = n
+					heap.Fix(&h, 0)
+				}
+			}
+			return
+		}
+		a, b := nd.left, nd.right
+		if t.lower(&t.nodes[b], q) < t.lower(&t.nodes[a], q) {
+			a, b = b, a
+		}
+		visit(a)
+		visit(b)
+	}
+	visit(0)
+	sort.Slice(h, func(a, b int) bool { return closer(h[a], h[b]) })
+	return h
+}
+
+// within returns the points at distance at most r from q.
+func (t *tree[T]) within(q Array[T], r float64) []Neighbor {
+	var out []Neighbor
+	var visit func(i int)
+	visit = func(i int) {
+		nd := &t.nodes[i]
+		if t.lower(nd, q) > r {
+			return
+		}
+		if nd.left < 0 {
+			for _, j := range t.idx[nd.lo:nd.hi] {
+				if d := t.metric.Distance(q, t.data.row(j)); d <= r {
+					out = append(out, Neighbor{j, d})
+				}
+			}
+			return
+		}
+		visit(nd.left)
+		visit(nd.right)
+	}
+	visit(0)
+	sort.Slice(out, func(a, b int) bool { return closer(out[a], out[b]) })
+	return out
+}
+
+func closer(a, b Neighbor) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	return a.Index < b.Index
+}
+
+// neighborHeap is a max-heap of neighbours, with the farthest at the root.
+type neighborHeap []Neighbor
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(i, j int) bool  { return closer(h[j], h[i]) }
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(Neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// KDTree indexes the rows of a matrix by recursively splitting them at the median of their widest coordinate.
+// It is fastest for low-dimensional data and works with the metrics of the Minkowski family:
+// EuclideanDistance, ManhattanDistance and MinkowskiDistance.
+type KDTree[T Number] struct {
+	tree[T]
+}
+
+// NewKDTree builds a KD-tree over the rows of m using the Euclidean distance.
+// The tree refers to m, which must not be modified while the tree is in use.
+// Any error is available from Err().
+func NewKDTree[T Number](m *Matrix[T]) *KDTree[T] {
+	t := new(KDTree[T])
+	t.build(m, leafSize)
+	return t.WithMetric(EuclideanDistance[T]{})
+}
+
+// WithMetric switches the distance used by later queries, and returns the tree.
+// Only the Minkowski family of metrics can be used with a KD-tree.
+func (t *KDTree[T]) WithMetric(metric Metric[T]) *KDTree[T] {
+	if t.err != nil {
+		return t
+	}
+	mk, ok := metric.(minkowskiMetric)
+	if !ok {
+		t.err = fmt.Errorf("KDTree: %T is not a Minkowski metric", metric)
+		return t
+	}
+	p := mk.power()
+	if p < 1 {
+		t.err = fmt.Errorf("KDTree: Minkowski metric of order %v < 1 does not satisfy the triangle inequality", p)
+		return t
+	}
+	t.metric = metric
+	// the distance to the bounding box, whose gap to q along each axis is zero where q lies within it
+	t.lower = func(nd *node[T], q Array[T]) float64 {
+		var s float64
+		for j, v := range q {
+			var gap float64
+			switch {
+			case v < nd.min[j]:
+				gap = float64(nd.min[j]) - float64(v)
+			case v > nd.max[j]:
+				gap = float64(v) - float64(nd.max[j])
+			default:
+				continue
+			}
+			switch p {
+			case 1:
+				s += gap
+			case 2:
+				s += gap * gap
+			default:
+				s += math.Pow(gap, p)
+			}
+		}
+		switch p {
+		case 1:
+			return s
+		case 2:
+			return math.Sqrt(s)
+		}
+		return math.Pow(s, 1/p)
+	}
+	return t
+}
+
+// Err returns the error that occurred while building or configuring the tree.
+func (t *KDTree[T]) Err() error {
+	return t.err
+}
+
+// BallTree indexes the rows of a matrix by a hierarchy of nested balls, each enclosing the points of its node.
+// It copes better than a KDTree with high-dimensional data, and works with any Metric that satisfies
+// the triangle inequality.
+type BallTree[T Number] struct {
+	tree[T]
+}
+
+// NewBallTree builds a ball tree over the rows of m using the Euclidean distance.
+// The tree refers to m, which must not be modified while the tree is in use.
+// Any error is available from Err().
+func NewBallTree[T Number](m *Matrix[T]) *BallTree[T] {
+	t := new(BallTree[T])
+	t.build(m, leafSize)
+	if t.err == nil {
+		for i := range t.nodes {
+			nd := &t.nodes[i]
+			nd.centre = make(Array[T], m.cols)
+			for j := range nd.centre {
+				nd.centre[j] = nd.min[j] + (nd.max[j]-nd.min[j])/2
+			}
+		}
+	}
+	return t.WithMetric(EuclideanDistance[T]{})
+}
+
+// WithMetric switches the distance used by later queries, recomputing the radii of the balls, and returns the tree.
+// CosineDistance cannot be used, since it does not satisfy the triangle inequality.
+func (t *BallTree[T]) WithMetric(metric Metric[T]) *BallTree[T] {
+	if t.err != nil {
+		return t
+	}
+	if _, ok := metric.(semimetric); ok {
+		t.err = fmt.Errorf("BallTree: %T does not satisfy the triangle inequality", metric)
+		return t
+	}
+	if mk, ok := metric.(minkowskiMetric); ok && mk.power() < 1 {
+		t.err = fmt.Errorf("BallTree: Minkowski metric of order %v < 1 does not satisfy the triangle inequality", mk.power())
+		return t
+	}
+	t.metric = metric
+	for i := range t.nodes {
+		nd := &t.nodes[i]
+		nd.radius = 0
+		for _, j := range t.idx[nd.lo:nd.hi] {
+			if d := metric.Distance(nd.centre, t.data.row(j)); d > nd.radius {
+				nd.radius = d
+			}
+		}
+	}
+	t.lower = func(nd *node[T], q Array[T]) float64 {
+		if d := metric.Distance(q, nd.centre) - nd.radius; d > 0 {
+			return d
+		}
+		return 0
+	}
+	return t
+}
+
+// Err returns the error that occurred while building or configuring the tree.
+func (t *BallTree[T]) Err() error {
+	return t.err
+}
+
+// newBruteForce returns an index that compares each query with every point, under any metric.
+func newBruteForce[T Number](m *Matrix[T], metric Metric[T]) *tree[T] {
+	t := &tree[T]{metric: metric, lower: func(*node[T], Array[T]) float64 { return 0 }}
+	t.build(m, m.rows)
+	return t
+}
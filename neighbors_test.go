@@ -0,0 +1,119 @@
+package pa
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomMatrix returns an (n x p) matrix of uniform samples, with every tenth row duplicating the one before
+// so that searches must break ties.
+func randomMatrix(n, p int, seed int64) *Matrix[float64] {
+	rng := rand.New(rand.NewSource(seed))
+	m := Empty[float64](n, p)
+	for i := 0; i < n; i++ {
+		row := m.row(i)
+		if i%10 == 9 {
+			copy(row, m.row(i-1))
+			continue
+		}
+		for j := range row {
+			row[j] = rng.Float64()
+		}
+	}
+	return m
+}
+
+func TestTreesMatchBruteForce(t *testing.T) {
+	data, queries := randomMatrix(500, 3, 1), randomMatrix(40, 3, 2)
+	tests := []struct {
+		name   string
+		metric Metric[float64]
+	}{
+		{"euclidean", EuclideanDistance[float64]{}},
+		{"manhattan", ManhattanDistance[float64]{}},
+		{"minkowski", MinkowskiDistance[float64]{P: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brute := newBruteForce(data, tt.metric)
+			want, err := brute.Query(queries, 7)
+			if err != nil {
+				t.Fatalf("brute force Query() error: %v", err)
+			}
+			wantR, _ := brute.RadiusQuery(queries, 0.2)
+			indexes := map[string]NeighborIndex[float64]{
+				"KDTree":   NewKDTree(data).WithMetric(tt.metric),
+				"BallTree": NewBallTree(data).WithMetric(tt.metric),
+			}
+			for name, index := range indexes {
+				got, err := index.Query(queries, 7)
+				if err != nil {
+					t.Fatalf("%s Query() error: %v", name, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s Query() differs from brute force", name)
+				}
+				gotR, err := index.RadiusQuery(queries, 0.2)
+				if err != nil {
+					t.Fatalf("%s RadiusQuery() error: %v", name, err)
+				}
+				if !reflect.DeepEqual(gotR, wantR) {
+					t.Errorf("%s RadiusQuery() differs from brute force", name)
+				}
+			}
+		})
+	}
+}
+
+func TestKDTreeDeduplicate(t *testing.T) {
+	m := NewMatrix([][]float64{{0, 0}, {1, 1}, {0, 0}, {5, 5}, {1, 1.0000001}}, nil)
+	tree := NewKDTree(m)
+	if err := tree.Err(); err != nil {
+		t.Fatalf("NewKDTree() error: %v", err)
+	}
+	got, err := tree.RadiusQuery(m, 1e-3)
+	if err != nil {
+		t.Fatalf("RadiusQuery() error: %v", err)
+	}
+	groups := make([][]int, len(got))
+	for i, ns := range got {
+		for _, n := range ns {
+			groups[i] = append(groups[i], n.Index)
+		}
+	}
+	want := [][]int{{0, 2}, {1, 4}, {0, 2}, {3}, {4, 1}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("RadiusQuery() groups = %v, want %v", groups, want)
+	}
+
+	nearest, err := tree.Query(NewMatrix([][]float64{{4, 4}}, nil), 2)
+	if err != nil {
+		t.Fatalf("Query() error: %v", err)
+	}
+	if nearest[0][0].Index != 3 || nearest[0][1].Index != 4 {
+		t.Errorf("Query() = %v, want indices 3 then 4", nearest[0])
+	}
+}
+
+func TestNeighborIndexErrors(t *testing.T) {
+	m := randomMatrix(10, 2, 1)
+	if err := NewKDTree(m).WithMetric(CosineDistance[float64]{}).Err(); err == nil {
+		t.Error("KDTree with CosineDistance returned nil error")
+	}
+	for _, metric := range []Metric[float64]{CosineDistance[float64]{}, &CosineDistance[float64]{}} {
+		if err := NewBallTree(m).WithMetric(metric).Err(); err == nil {
+			t.Errorf("BallTree with %T returned nil error", metric)
+		}
+	}
+	if err := NewKDTree(Empty[float64](0, 0)).Err(); err == nil {
+		t.Error("NewKDTree() of an empty matrix returned nil error")
+	}
+	tree := NewKDTree(m)
+	if _, err := tree.Query(m, 11); err == nil {
+		t.Error("Query() with k > n returned nil error")
+	}
+	if _, err := tree.Query(Empty[float64](1, 3), 1); err == nil {
+		t.Error("Query() with the wrong number of features returned nil error")
+	}
+}
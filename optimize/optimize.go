@@ -0,0 +1,364 @@
+// Package optimize minimizes functions of a matrix of parameters. It offers first-order methods
+// (gradient descent, Nesterov momentum and Adam), quasi-Newton L-BFGS, nonlinear conjugate gradient,
+// the derivative-free Nelder–Mead simplex, and a strong Wolfe line search for building others.
+//
+// The estimators of package pa use the same implementations.
+package optimize
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kipukun/pa"
+	"github.com/kipukun/pa/internal/optim"
+)
+
+// Objective returns the value at x of the function being minimized. If grad is not nil, it must also
+// write the gradient at x into grad, which has the shape of x. Methods that need no gradient,
+// such as NelderMead, always pass nil.
+type Objective func(x, grad *pa.Matrix[float64]) float64
+
+// Method is an algorithm for minimizing an Objective.
+type Method interface {
+	method() (optim.Method, error)
+}
+
+// GradientDescent steps against the gradient, scaled by Rate. If Rate is zero, 0.01 is used.
+type GradientDescent struct {
+	Rate float64
+}
+
+func (m GradientDescent) method() (optim.Method, error) {
+	rate, err := positive("Rate", m.Rate, 0.01)
+	return &optim.GradientDescent{Rate: rate}, err
+}
+
+// Nesterov is gradient descent with Nesterov's accelerated momentum. If Rate is zero, 0.01 is used;
+// if Momentum is zero, 0.9 is used.
+type Nesterov struct {
+	Rate, Momentum float64
+}
+
+func (m Nesterov) method() (optim.Method, error) {
+	rate, err := positive("Rate", m.Rate, 0.01)
+	if err != nil {
+		return nil, err
+	}
+	momentum, err := fraction("Momentum", m.Momentum, 0.9)
+	return &optim.Nesterov{Rate: rate, Momentum: momentum}, err
+}
+
+// Adam adapts the step of each parameter from running estimates of the first and second moments of its
+// gradient (Kingma and Ba, 2015). If zero, Rate is 0.001, Beta1 0.9, Beta2 0.999 and Epsilon 1e-8.
+type Adam struct {
+	Rate, Beta1, Beta2, Epsilon float64
+}
+
+func (m Adam) method() (optim.Method, error) {
+	rate, err := positive("Rate", m.Rate, 0.001)
+	if err != nil {
+		return nil, err
+	}
+	beta1, err := fraction("Beta1", m.Beta1, 0.9)
+	if err != nil {
+		return nil, err
+	}
+	beta2, err := fraction("Beta2", m.Beta2, 0.999)
+	if err != nil {
+		return nil, err
+	}
+	eps, err := positive("Epsilon", m.Epsilon, 1e-8)
+	return &optim.Adam{Rate: rate, Beta1: beta1, Beta2: beta2, Epsilon: eps}, err
+}
+
+// LBFGS is limited-memory BFGS, which builds a quasi-Newton direction from the last Memory steps.
+// If Memory is zero, 10 is used.
+type LBFGS struct {
+	Memory int
+}
+
+func (m LBFGS) method() (optim.Method, error) {
+	if m.Memory < 0 {
+		return nil, fmt.Errorf("Memory must not be negative, got %d", m.Memory)
+	}
+	return &optim.LBFGS{Memory: m.Memory}, nil
+}
+
+// ConjugateGradient is nonlinear conjugate gradient with the Polak–Ribière+ update and a strong Wolfe line search.
+type ConjugateGradient struct{}
+
+func (ConjugateGradient) method() (optim.Method, error) {
+	return optim.ConjugateGradient{}, nil
+}
+
+// NelderMead is the downhill simplex method, which needs no gradient. The initial simplex moves each
+// parameter of x0 in turn by Step·max(1, |x0|). If Step is zero, 0.05 is used.
+type NelderMead struct {
+	Step float64
+}
+
+func (m NelderMead) method() (optim.Method, error) {
+	if m.Step < 0 {
+		return nil, fmt.Errorf("Step must not be negative, got %v", m.Step)
+	}
+	return optim.NelderMead{Step: m.Step}, nil
+}
+
+func positive(name string, v, def float64) (float64, error) {
+	if v < 0 {
+		return 0, fmt.Errorf("%s must not be negative, got %v", name, v)
+	}
+	if v == 0 {
+		return def, nil
+	}
+	return v, nil
+}
+
+func fraction(name string, v, def float64) (float64, error) {
+	if v < 0 || v >= 1 {
+		return 0, fmt.Errorf("%s must be in [0, 1), got %v", name, v)
+	}
+	if v == 0 {
+		return def, nil
+	}
+	return v, nil
+}
+
+// Settings controls when Minimize stops.
+type Settings struct {
+	// GradTol stops gradient-based methods once no component of the gradient exceeds GradTol in magnitude.
+	// If zero, 1e-6 is used.
+	GradTol float64
+	// FuncTol stops a method once an iteration changes f by at most FuncTol·max(1, |f|), or for NelderMead
+	// once f varies by no more than that over the simplex. If zero, 1e-12 is used.
+	FuncTol float64
+	// StepTol stops NelderMead, together with FuncTol, once the simplex has shrunk to within StepTol·max(1, ‖x‖∞)
+	// of its best vertex. If zero, 1e-8 is used.
+	StepTol float64
+	// MaxIter bounds the number of iterations. If zero, 1000 is used.
+	MaxIter int
+	// MaxEval bounds the number of evaluations of the objective; zero leaves them unbounded.
+	MaxEval int
+}
+
+func (s *Settings) internal() (optim.Settings, error) {
+	var c Settings
+	if s != nil {
+		c = *s
+	}
+	if c.GradTol < 0 || c.FuncTol < 0 || c.StepTol < 0 {
+		return optim.Settings{}, errors.New("tolerances must not be negative")
+	}
+	if c.MaxIter < 0 || c.MaxEval < 0 {
+		return optim.Settings{}, errors.New("MaxIter and MaxEval must not be negative")
+	}
+	if c.GradTol == 0 {
+		c.GradTol = 1e-6
+	}
+	if c.FuncTol == 0 {
+		c.FuncTol = 1e-12
+	}
+	if c.StepTol == 0 {
+		c.StepTol = 1e-8
+	}
+	if c.MaxIter == 0 {
+		c.MaxIter = 1000
+	}
+	return optim.Settings{GradTol: c.GradTol, FuncTol: c.FuncTol, StepTol: c.StepTol, MaxIter: c.MaxIter, MaxEval: c.MaxEval}, nil
+}
+
+// Status records why Minimize stopped.
+type Status int
+
+const (
+	// IterationLimit means MaxIter iterations were made without meeting a tolerance.
+	IterationLimit Status = iota
+	// GradientConverged means the gradient met GradTol.
+	GradientConverged
+	// FunctionConverged means the change in the objective met FuncTol.
+	FunctionConverged
+	// StepConverged means the NelderMead simplex met both FuncTol and StepTol.
+	StepConverged
+	// EvaluationLimit means MaxEval evaluations were made without meeting a tolerance.
+	EvaluationLimit
+	// LineSearchFailed means no step along the search direction decreased the objective enough.
+	// This usually means the gradient is wrong, or that the objective cannot be decreased further at working precision.
+	LineSearchFailed
+)
+
+// Converged reports whether s means a tolerance was met.
+func (s Status) Converged() bool {
+	return optim.Status(s).Converged()
+}
+
+func (s Status) String() string {
+	switch s {
+	case IterationLimit:
+		return "iteration limit"
+	case GradientConverged:
+		return "gradient converged"
+	case FunctionConverged:
+		return "function converged"
+	case StepConverged:
+		return "step converged"
+	case EvaluationLimit:
+		return "evaluation limit"
+	case LineSearchFailed:
+		return "line search failed"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Result is the outcome of Minimize.
+type Result struct {
+	// X is the best point found, with the shape of x0, and F the objective there.
+	X *pa.Matrix[float64]
+	F float64
+	// Status records why the method stopped.
+	Status Status
+	// Iterations and Evaluations count the iterations made and the evaluations of the objective.
+	Iterations, Evaluations int
+}
+
+// Minimize minimizes f by m starting from x0, which it does not modify. If m is nil, LBFGS is used;
+// if s is nil, the defaults described by Settings are.
+//
+// If no tolerance was met, Minimize returns the best point found together with an error wrapping
+// pa.ErrNoConvergence; Result.Status tells why it stopped.
+func Minimize(f Objective, x0 *pa.Matrix[float64], m Method, s *Settings) (*Result, error) {
+	if err := x0.Err(); err != nil {
+		return nil, fmt.Errorf("optimize.Minimize: %w", err)
+	}
+	if m == nil {
+		m = LBFGS{}
+	}
+	method, err := m.method()
+	if err != nil {
+		return nil, fmt.Errorf("optimize.Minimize: %w", err)
+	}
+	settings, err := s.internal()
+	if err != nil {
+		return nil, fmt.Errorf("optimize.Minimize: %w", err)
+	}
+	r, c := x0.Size()
+	res := method.Minimize(flatten(f, r, c), toSlice(x0), settings)
+	out := &Result{X: toMatrix(res.X, r, c), F: res.F, Status: Status(res.Status), Iterations: res.Iterations, Evaluations: res.Evaluations}
+	if !out.Status.Converged() {
+		return out, fmt.Errorf("optimize.Minimize: %w: %v after %d iterations", pa.ErrNoConvergence, out.Status, out.Iterations)
+	}
+	return out, nil
+}
+
+// Wolfe is a line search for a step length α satisfying the strong Wolfe conditions
+//
+//	f(x + αd) ≤ f(x) + C1·α·∇f(x)ᵀd
+//	|∇f(x + αd)ᵀd| ≤ C2·|∇f(x)ᵀd|
+//
+// If zero, C1 is 1e-4 and C2 0.9, which suits quasi-Newton methods; conjugate gradient methods want C2 near 0.1.
+type Wolfe struct {
+	C1, C2 float64
+}
+
+// LineResult is the outcome of a line search.
+type LineResult struct {
+	// Step is the accepted step length α and X the point x + αd.
+	Step float64
+	X    *pa.Matrix[float64]
+	// F and Grad are the value and gradient of the objective at X.
+	F    float64
+	Grad *pa.Matrix[float64]
+	// Evaluations counts the evaluations of the objective.
+	Evaluations int
+}
+
+// Search looks along d from x for a step satisfying the strong Wolfe conditions, trying step first and
+// then growing or narrowing it. If step is zero, 1 is tried first.
+func (w Wolfe) Search(f Objective, x, d *pa.Matrix[float64], step float64) (*LineResult, error) {
+	if err := x.Err(); err != nil {
+		return nil, fmt.Errorf("Wolfe.Search: %w", err)
+	}
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("Wolfe.Search: %w", err)
+	}
+	r, c := x.Size()
+	if dr, dc := d.Size(); dr != r || dc != c {
+		return nil, fmt.Errorf("Wolfe.Search: direction is (%d x %d), x is (%d x %d)", dr, dc, r, c)
+	}
+	c1, c2 := w.C1, w.C2
+	if c1 == 0 {
+		c1 = 1e-4
+	}
+	if c2 == 0 {
+		c2 = 0.9
+	}
+	if c1 <= 0 || c2 <= c1 || c2 >= 1 {
+		return nil, fmt.Errorf("Wolfe.Search: need 0 < C1 < C2 < 1, got C1 = %v, C2 = %v", c1, c2)
+	}
+	if step < 0 {
+		return nil, fmt.Errorf("Wolfe.Search: step must not be negative, got %v", step)
+	}
+	if step == 0 {
+		step = 1
+	}
+	g := flatten(f, r, c)
+	xs, ds := toSlice(x), toSlice(d)
+	grad := make([]float64, len(xs))
+	fx := g(xs, grad)
+	var slope float64
+	for i := range grad {
+		slope += grad[i] * ds[i]
+	}
+	if !(slope < 0) {
+		return nil, fmt.Errorf("Wolfe.Search: d is not a descent direction at x: ∇f(x)ᵀd = %v", slope)
+	}
+	res := optim.Wolfe{C1: c1, C2: c2}.Search(g, xs, ds, fx, grad, step)
+	if !res.OK {
+		return nil, fmt.Errorf("Wolfe.Search: no acceptable step within %d evaluations", res.Evals+1)
+	}
+	return &LineResult{
+		Step:        res.Step,
+		X:           toMatrix(res.X, r, c),
+		F:           res.F,
+		Grad:        toMatrix(res.Grad, r, c),
+		Evaluations: res.Evals + 1,
+	}, nil
+}
+
+// flatten adapts f to the flat parameter vectors of package optim, for r x c parameters.
+func flatten(f Objective, r, c int) optim.Func {
+	return func(x, grad []float64) float64 {
+		xm := toMatrix(x, r, c)
+		if grad == nil {
+			return f(xm, nil)
+		}
+		gm := pa.Empty[float64](r, c)
+		v := f(xm, gm)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				grad[i*c+j] = gm.At(i, j)
+			}
+		}
+		return v
+	}
+}
+
+func toSlice(m *pa.Matrix[float64]) []float64 {
+	r, c := m.Size()
+	s := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			s = append(s, m.At(i, j))
+		}
+	}
+	return s
+}
+
+func toMatrix(s []float64, r, c int) *pa.Matrix[float64] {
+	m := pa.Empty[float64](r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, s[i*c+j])
+		}
+	}
+	return m
+}
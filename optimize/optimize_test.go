@@ -0,0 +1,147 @@
+package optimize
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/kipukun/pa"
+	"github.com/kipukun/pa/internal/optim"
+)
+
+// target is the minimizer of the objective returned by bowl.
+var target = pa.NewMatrix([][]float64{{1, -2}, {0.5, 3}}, nil)
+
+// bowl returns Σ w(x - target)²/2 over the elements, with weights growing from 1 to 4.
+func bowl() Objective {
+	return func(x, grad *pa.Matrix[float64]) float64 {
+		var f float64
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 2; j++ {
+				w := float64(2*i + j + 1)
+				d := x.At(i, j) - target.At(i, j)
+				f += w * d * d / 2
+				if grad != nil {
+					grad.Set(i, j, w*d)
+				}
+			}
+		}
+		return f
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	tests := []struct {
+		name   string
+		method Method
+		tol    float64
+	}{
+		{"default", nil, 1e-6},
+		{"gradient descent", GradientDescent{Rate: 0.2}, 1e-5},
+		{"nesterov", Nesterov{Rate: 0.05}, 1e-6},
+		{"adam", Adam{Rate: 0.05}, 1e-5},
+		{"lbfgs", LBFGS{Memory: 3}, 1e-6},
+		{"conjugate gradient", ConjugateGradient{}, 1e-6},
+		{"nelder-mead", NelderMead{Step: 0.5}, 1e-5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x0 := pa.Empty[float64](2, 2)
+			res, err := Minimize(bowl(), x0, tt.method, &Settings{MaxIter: 5000})
+			if err != nil {
+				t.Fatalf("Minimize() error: %v", err)
+			}
+			if !res.Status.Converged() {
+				t.Errorf("Status = %v, want converged", res.Status)
+			}
+			for i := 0; i < 2; i++ {
+				for j := 0; j < 2; j++ {
+					if math.Abs(res.X.At(i, j)-target.At(i, j)) > tt.tol {
+						t.Fatalf("X =\n%s\nwant\n%s", res.X, target)
+					}
+				}
+			}
+			if res.Evaluations < res.Iterations || res.Iterations == 0 {
+				t.Errorf("%d evaluations over %d iterations", res.Evaluations, res.Iterations)
+			}
+			if x0.At(0, 0) != 0 {
+				t.Error("Minimize() modified x0")
+			}
+		})
+	}
+}
+
+func TestMinimizeErrors(t *testing.T) {
+	x0 := pa.Empty[float64](2, 2)
+	res, err := Minimize(bowl(), x0, GradientDescent{Rate: 0.01}, &Settings{MaxIter: 5})
+	if !errors.Is(err, pa.ErrNoConvergence) {
+		t.Errorf("Minimize() with MaxIter 5 error = %v, want ErrNoConvergence", err)
+	}
+	if res == nil || res.Status != IterationLimit || res.Iterations != 5 {
+		t.Errorf("Minimize() with MaxIter 5 = %+v, want IterationLimit after 5 iterations", res)
+	}
+
+	tests := []struct {
+		name   string
+		method Method
+		s      *Settings
+	}{
+		{"negative rate", GradientDescent{Rate: -1}, nil},
+		{"momentum of one", Nesterov{Momentum: 1}, nil},
+		{"negative beta", Adam{Beta2: -0.5}, nil},
+		{"negative memory", LBFGS{Memory: -1}, nil},
+		{"negative tolerance", nil, &Settings{GradTol: -1}},
+		{"negative iterations", nil, &Settings{MaxIter: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Minimize(bowl(), x0, tt.method, tt.s); err == nil || errors.Is(err, pa.ErrNoConvergence) {
+				t.Errorf("Minimize() error = %v, want a configuration error", err)
+			}
+		})
+	}
+}
+
+func TestWolfe(t *testing.T) {
+	x := pa.Empty[float64](2, 2)
+	d := target.Clone()
+	for _, w := range []Wolfe{{}, {C1: 0.1, C2: 0.2}} {
+		res, err := w.Search(bowl(), x, d, 0)
+		if err != nil {
+			t.Fatalf("Search() error: %v", err)
+		}
+		// along d the objective is a parabola with its minimum at α = 1
+		if math.Abs(res.Step-1) > 0.5 || res.F >= bowl()(x, nil) {
+			t.Errorf("Search() step = %v, F = %v", res.Step, res.F)
+		}
+		if got := res.X.At(1, 1); math.Abs(got-res.Step*target.At(1, 1)) > 1e-12 {
+			t.Errorf("X(1, 1) = %v, want step·d(1, 1) = %v", got, res.Step*target.At(1, 1))
+		}
+	}
+	if _, err := (Wolfe{}).Search(bowl(), x, d.Product(-1), 1); err == nil {
+		t.Error("Search() along an ascent direction returned nil error")
+	}
+	if _, err := (Wolfe{C1: 0.5, C2: 0.1}).Search(bowl(), x, d, 1); err == nil {
+		t.Error("Search() with C2 < C1 returned nil error")
+	}
+	if _, err := (Wolfe{}).Search(bowl(), x, pa.Empty[float64](1, 4), 1); err == nil {
+		t.Error("Search() with a mismatched direction returned nil error")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	// the public statuses mirror the internal ones by value
+	pairs := map[Status]optim.Status{
+		IterationLimit:    optim.IterationLimit,
+		GradientConverged: optim.GradientConverged,
+		FunctionConverged: optim.FunctionConverged,
+		StepConverged:     optim.StepConverged,
+		EvaluationLimit:   optim.EvaluationLimit,
+		LineSearchFailed:  optim.LineSearchFailed,
+	}
+	for s, o := range pairs {
+		if int(s) != int(o) {
+			t.Errorf("%v = %d, internal status is %d", s, s, o)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package pa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PAVariant selects how aggressively a passive-aggressive learner corrects a violated sample.
+type PAVariant int
+
+const (
+	// PAI caps the step size at C, so a single noisy sample cannot move the weights far.
+	PAI PAVariant = iota
+	// PAII shrinks the step by 1/2C, a softer cap that grows with the loss.
+	PAII
+	// PA takes the smallest step that removes the loss entirely. It suits separable data only.
+	PA
+)
+
+// paStep returns the step size for a sample with loss l and squared norm sq, following Crammer et al. (2006).
+func paStep(variant PAVariant, c, l, sq float64) float64 {
+	switch variant {
+	case PAII:
+		return l / (sq + 1/(2*c))
+	case PA:
+		return l / sq
+	}
+	return math.Min(c, l/sq)
+}
+
+// paOptions holds the settings shared by the passive-aggressive learners.
+type paOptions struct {
+	variant PAVariant
+	c       float64
+}
+
+func newPAOptions(variant PAVariant, c float64) (paOptions, error) {
+	if variant < PAI || variant > PA {
+		return paOptions{}, fmt.Errorf("unknown variant %d", variant)
+	}
+	if c < 0 {
+		return paOptions{}, fmt.Errorf("C must not be negative, got %v", c)
+	}
+	if c == 0 {
+		c = 1
+	}
+	return paOptions{variant, c}, nil
+}
+
+// epochs runs passes of update over n samples until the mean loss stops improving by more than tol
+// for five consecutive passes, or maxIter passes have been made.
+func epochs(n int, tol float64, maxIter int, update func(i int) float64) (int, bool) {
+	if tol == 0 {
+		tol = defaultTolerance
+	}
+	if maxIter == 0 {
+		maxIter = defaultMaxIter
+	}
+	best, stale := math.Inf(1), 0
+	for iter := 1; iter <= maxIter; iter++ {
+		var loss float64
+		for i := 0; i < n; i++ {
+			loss += update(i)
+		}
+		loss /= float64(n)
+		if loss > best-tol {
+			stale++
+		} else {
+			stale = 0
+		}
+		best = math.Min(best, loss)
+		if stale >= 5 {
+			return iter, true
+		}
+	}
+	return maxIter, false
+}
+
+// PassiveAggressiveClassifier is an online linear classifier. Each sample that is misclassified,
+// or classified with a margin below one, moves the weights just far enough to correct it,
+// subject to the aggressiveness parameter C. More than two classes are handled one-vs-rest.
+type PassiveAggressiveClassifier[T Float] struct {
+	// Variant selects the step size rule. The zero value is PAI.
+	Variant PAVariant
+	// C bounds the aggressiveness of each update. If zero, 1 is used.
+	C float64
+	// Tol stops Fit once the mean hinge loss of a pass has not improved by Tol for five passes.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 1000 is used.
+	MaxIter int
+
+	classes []T
+	coef    *Matrix[T]
+	iters   int
+}
+
+// Fit fits the model from scratch, making repeated passes over X and y.
+func (pac *PassiveAggressiveClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if y.Err() != nil {
+		return y.Err()
+	}
+	classes, _ := encodeLabels(y)
+	pac.classes, pac.coef = nil, nil
+	xa, labels, opts, err := pac.prepare(X, y, classes)
+	if err != nil {
+		return fmt.Errorf("PassiveAggressiveClassifier.Fit: %w", err)
+	}
+	iters, converged := epochs(xa.rows, pac.Tol, pac.MaxIter, func(i int) float64 {
+		return pac.update(opts, xa.row(i), labels[i])
+	})
+	pac.iters = iters
+	if !converged {
+		return fmt.Errorf("PassiveAggressiveClassifier.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (pac *PassiveAggressiveClassifier[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	xa, labels, opts, err := pac.prepare(X, y, classes)
+	if err != nil {
+		return fmt.Errorf("PassiveAggressiveClassifier.PartialFit: %w", err)
+	}
+	for i := 0; i < xa.rows; i++ {
+		pac.update(opts, xa.row(i), labels[i])
+	}
+	return nil
+}
+
+// prepare validates a batch, initializing the weights on first use,
+// and returns the batch with an intercept column, its labels as indices into the classes and the step options.
+func (pac *PassiveAggressiveClassifier[T]) prepare(X, y *Matrix[T], classes []T) (*Matrix[float64], []int, paOptions, error) {
+	var opts paOptions
+	if X.Err() != nil {
+		return nil, nil, opts, X.Err()
+	}
+	if y.Err() != nil {
+		return nil, nil, opts, y.Err()
+	}
+	opts, err := newPAOptions(pac.Variant, pac.C)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+	if pac.coef == nil {
+		cs, err := initClasses(classes)
+		if err != nil {
+			return nil, nil, opts, err
+		}
+		k := len(cs)
+		if k == 2 {
+			k = 1
+		}
+		pac.classes, pac.coef = cs, Empty[T](X.cols+1, k)
+	}
+	labels, err := indexLabels(X, y, pac.classes, pac.coef.rows-1)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+	return convert[float64](withIntercept(X)), labels, opts, nil
+}
+
+// update applies one passive-aggressive step for the sample x with the given label,
+// returning its hinge loss summed over the one-vs-rest problems.
+func (pac *PassiveAggressiveClassifier[T]) update(opts paOptions, x []float64, label int) float64 {
+	sq := dotf(x, x)
+	var total float64
+	for c := 0; c < pac.coef.cols; c++ {
+		// a binary model has one column, whose positive class is the second
+		positive := c
+		if pac.coef.cols == 1 {
+			positive = 1
+		}
+		y := -1.0
+		if label == positive {
+			y = 1
+		}
+		var margin float64
+		for j, v := range x {
+			margin += v * float64(pac.coef.at(j, c))
+		}
+		loss := math.Max(0, 1-y*margin)
+		if loss == 0 {
+			continue
+		}
+		total += loss
+		tau := paStep(opts.variant, opts.c, loss, sq)
+		for j, v := range x {
+			pac.coef.set(j, c, pac.coef.at(j, c)+T(tau*y*v))
+		}
+	}
+	return total
+}
+
+// DecisionFunction returns the signed margin of each row of X: a column vector for a binary problem,
+// where positive values favour the second class, otherwise one column per class.
+func (pac *PassiveAggressiveClassifier[T]) DecisionFunction(X *Matrix[T]) (*Matrix[T], error) {
+	d := withIntercept(X).Mul(pac.coef)
+	if d.Err() != nil {
+		return nil, d.Err()
+	}
+	return d, nil
+}
+
+func (pac *PassiveAggressiveClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	d, err := pac.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLabels(binaryScores(d), pac.classes), nil
+}
+
+// PredictProba returns one-hot rows for the predicted classes.
+// Passive-aggressive learners do not model probabilities, so all the mass goes to the predicted class.
+func (pac *PassiveAggressiveClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	d, err := pac.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return oneHot(binaryScores(d)), nil
+}
+
+func (pac *PassiveAggressiveClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := pac.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (pac *PassiveAggressiveClassifier[T]) Classes() []T {
+	return pac.classes
+}
+
+// Coefficients returns the (p+1) x k weights, with the intercepts in the first row.
+// For a binary problem k is 1.
+func (pac *PassiveAggressiveClassifier[T]) Coefficients() *Matrix[T] {
+	return pac.coef
+}
+
+// Iterations returns the number of passes made by the last call to Fit.
+func (pac *PassiveAggressiveClassifier[T]) Iterations() int {
+	return pac.iters
+}
+
+// PassiveAggressiveRegressor is an online linear regressor. Each sample predicted with an error
+// larger than Epsilon moves the weights just far enough to bring it within Epsilon,
+// subject to the aggressiveness parameter C.
+type PassiveAggressiveRegressor[T Float] struct {
+	// Variant selects the step size rule. The zero value is PAI.
+	Variant PAVariant
+	// C bounds the aggressiveness of each update. If zero, 1 is used.
+	C float64
+	// Epsilon is the width of the insensitive zone: errors smaller than Epsilon cause no update.
+	Epsilon float64
+	// Tol stops Fit once the mean loss of a pass has not improved by Tol for five passes.
+	// If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 1000 is used.
+	MaxIter int
+
+	coef  *Matrix[T]
+	iters int
+}
+
+// Fit fits the model from scratch, making repeated passes over X and y.
+func (par *PassiveAggressiveRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	par.coef = nil
+	xa, opts, err := par.prepare(X, y)
+	if err != nil {
+		return fmt.Errorf("PassiveAggressiveRegressor.Fit: %w", err)
+	}
+	iters, converged := epochs(xa.rows, par.Tol, par.MaxIter, func(i int) float64 {
+		return par.update(opts, xa.row(i), float64(y.at(i, 0)))
+	})
+	par.iters = iters
+	if !converged {
+		return fmt.Errorf("PassiveAggressiveRegressor.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+func (par *PassiveAggressiveRegressor[T]) PartialFit(X, y *Matrix[T]) (err error) {
+	xa, opts, err := par.prepare(X, y)
+	if err != nil {
+		return fmt.Errorf("PassiveAggressiveRegressor.PartialFit: %w", err)
+	}
+	for i := 0; i < xa.rows; i++ {
+		par.update(opts, xa.row(i), float64(y.at(i, 0)))
+	}
+	return nil
+}
+
+// prepare validates a batch, initializing the weights on first use,
+// and returns the batch with an intercept column and the step options.
+func (par *PassiveAggressiveRegressor[T]) prepare(X, y *Matrix[T]) (*Matrix[float64], paOptions, error) {
+	var opts paOptions
+	if X.Err() != nil {
+		return nil, opts, X.Err()
+	}
+	if y.Err() != nil {
+		return nil, opts, y.Err()
+	}
+	opts, err := newPAOptions(par.Variant, par.C)
+	if err != nil {
+		return nil, opts, err
+	}
+	if y.rows != X.rows || y.cols != 1 {
+		return nil, opts, fmt.Errorf("y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	if par.coef == nil {
+		par.coef = Empty[T](X.cols+1, 1)
+	}
+	if X.cols != par.coef.rows-1 {
+		return nil, opts, fmt.Errorf("X has %d features, model has %d", X.cols, par.coef.rows-1)
+	}
+	return convert[float64](withIntercept(X)), opts, nil
+}
+
+// update applies one passive-aggressive step for the sample x with target y, returning its ε-insensitive loss.
+func (par *PassiveAggressiveRegressor[T]) update(opts paOptions, x []float64, y float64) float64 {
+	var yh float64
+	for j, v := range x {
+		yh += v * float64(par.coef.at(j, 0))
+	}
+	loss := math.Max(0, math.Abs(y-yh)-par.Epsilon)
+	if loss == 0 {
+		return 0
+	}
+	tau := math.Copysign(paStep(opts.variant, opts.c, loss, dotf(x, x)), y-yh)
+	for j, v := range x {
+		par.coef.set(j, 0, par.coef.at(j, 0)+T(tau*v))
+	}
+	return loss
+}
+
+func (par *PassiveAggressiveRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	yh := withIntercept(X).Mul(par.coef)
+	if yh.Err() != nil {
+		return nil, yh.Err()
+	}
+	return yh, nil
+}
+
+func (par *PassiveAggressiveRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := par.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// Coefficients returns the weights, with the intercept first.
+func (par *PassiveAggressiveRegressor[T]) Coefficients() *Matrix[T] {
+	return par.coef
+}
+
+// Iterations returns the number of passes made by the last call to Fit.
+func (par *PassiveAggressiveRegressor[T]) Iterations() int {
+	return par.iters
+}
+
+// initClasses returns a sorted copy of the classes given to the first call of PartialFit.
+func initClasses[T Number](classes []T) ([]T, error) {
+	if classes == nil {
+		return nil, errors.New("classes must be given on the first call")
+	}
+	cs := append([]T(nil), classes...)
+	sort.Slice(cs, func(i, j int) bool { return cs[i] < cs[j] })
+	for i := 1; i < len(cs); i++ {
+		if cs[i] == cs[i-1] {
+			return nil, fmt.Errorf("duplicate class %v", cs[i])
+		}
+	}
+	if len(cs) < 2 {
+		return nil, fmt.Errorf("need at least two classes, got %d", len(cs))
+	}
+	return cs, nil
+}
+
+// indexLabels checks that X has p features and y is a matching column vector,
+// and returns the index of each label of y into classes.
+func indexLabels[T Number](X, y *Matrix[T], classes []T, p int) ([]int, error) {
+	if y.rows != X.rows || y.cols != 1 {
+		return nil, fmt.Errorf("y must be a (%d x 1) column vector, got (%d x %d)", X.rows, y.rows, y.cols)
+	}
+	if X.cols != p {
+		return nil, fmt.Errorf("X has %d features, model has %d", X.cols, p)
+	}
+	labels := make([]int, y.rows)
+	for i := range labels {
+		v := y.at(i, 0)
+		c := sort.Search(len(classes), func(c int) bool { return classes[c] >= v })
+		if c == len(classes) || classes[c] != v {
+			return nil, fmt.Errorf("label %v of sample %d is not one of the classes %v", v, i, classes)
+		}
+		labels[i] = c
+	}
+	return labels, nil
+}
+
+// binaryScores expands the single column of decision values of a binary model into two columns,
+// so the second class wins exactly when the value is positive. Wider matrices are returned unchanged.
+func binaryScores[T Number](d *Matrix[T]) *Matrix[T] {
+	if d.cols != 1 {
+		return d
+	}
+	s := Empty[T](d.rows, 2)
+	for i := 0; i < d.rows; i++ {
+		s.set(i, 1, d.at(i, 0))
+	}
+	return s
+}
+
+// oneHot returns a matrix the shape of scores, with a one at the largest score of each row.
+func oneHot[T Number](scores *Matrix[T]) *Matrix[T] {
+	out := Empty[T](scores.rows, scores.cols)
+	for i := 0; i < scores.rows; i++ {
+		row := scores.row(i)
+		best := 0
+		for c, v := range row {
+			if v > row[best] {
+				best = c
+			}
+		}
+		out.set(i, best, 1)
+	}
+	return out
+}
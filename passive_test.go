@@ -0,0 +1,153 @@
+package pa
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPAStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant PAVariant
+		c, l    float64
+		want    float64
+	}{
+		{"PA", PA, 0.1, 4, 2},
+		{"PA-I below C", PAI, 5, 4, 2},
+		{"PA-I capped", PAI, 0.5, 4, 0.5},
+		{"PA-II", PAII, 0.25, 4, 4.0 / 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paStep(tt.variant, tt.c, tt.l, 2); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("paStep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// shuffled returns the rows of X and y in a random order.
+func shuffled(X, y *Matrix[float64]) (*Matrix[float64], *Matrix[float64]) {
+	rng := rand.New(rand.NewSource(2))
+	xs, ys := Empty[float64](X.rows, X.cols), Empty[float64](y.rows, 1)
+	for i, k := range rng.Perm(X.rows) {
+		copy(xs.row(i), X.row(k))
+		ys.set(i, 0, y.at(k, 0))
+	}
+	return xs, ys
+}
+
+func TestPassiveAggressiveClassifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant PAVariant
+		centres [][]float64
+	}{
+		{"PA-I binary", PAI, [][]float64{{0, 0}, {5, 5}}},
+		{"PA-II binary", PAII, [][]float64{{0, 0}, {5, 5}}},
+		{"PA-I multiclass", PAI, [][]float64{{0, 0}, {8, 0}, {0, 8}}},
+		{"PA-II multiclass", PAII, [][]float64{{0, 0}, {8, 0}, {0, 8}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := shuffled(blobs(tt.centres, 40))
+			pac := &PassiveAggressiveClassifier[float64]{Variant: tt.variant, C: 0.1}
+			if err := pac.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := pac.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.95 {
+				t.Errorf("Score() = %v, want at least 0.95", acc)
+			}
+			proba, err := pac.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			if _, c := proba.Size(); c != len(tt.centres) {
+				t.Errorf("PredictProba() has %d columns, want %d", c, len(tt.centres))
+			}
+		})
+	}
+}
+
+func TestPassiveAggressiveClassifierPartialFit(t *testing.T) {
+	X, y := shuffled(blobs([][]float64{{0, 0}, {8, 0}, {0, 8}}, 40))
+	pac := new(PassiveAggressiveClassifier[float64])
+	if err := pac.PartialFit(X.Slice(0, 10, 0, 2), y.Slice(0, 10, 0, 1), nil); err == nil {
+		t.Fatal("first PartialFit() without classes returned nil error")
+	}
+	for i := 0; i < X.rows; i += 10 {
+		classes := []float64{2, 0, 1}
+		if i > 0 {
+			classes = nil
+		}
+		if err := pac.PartialFit(X.Slice(i, i+10, 0, 2), y.Slice(i, i+10, 0, 1), classes); err != nil {
+			t.Fatalf("PartialFit() on batch %d error: %v", i/10, err)
+		}
+	}
+	if got := pac.Classes(); len(got) != 3 || got[0] != 0 || got[2] != 2 {
+		t.Errorf("Classes() = %v, want [0 1 2]", got)
+	}
+	acc, err := pac.Score(X, y)
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if acc < 0.9 {
+		t.Errorf("Score() after one pass = %v, want at least 0.9", acc)
+	}
+	bad := NewMatrix([][]float64{{7}}, nil)
+	if err := pac.PartialFit(X.Slice(0, 1, 0, 2), bad, nil); err == nil {
+		t.Error("PartialFit() with an unseen label returned nil error")
+	}
+}
+
+func TestPassiveAggressiveRegressor(t *testing.T) {
+	// y = 1 + 2a - b exactly, so every variant should recover the coefficients
+	X, y := Empty[float64](50, 2), Empty[float64](50, 1)
+	for i := 0; i < 50; i++ {
+		a, b := math.Sin(float64(i)), math.Cos(float64(3*i))
+		X.set(i, 0, a)
+		X.set(i, 1, b)
+		y.set(i, 0, 1+2*a-b)
+	}
+	want := NewMatrix([][]float64{{1}, {2}, {-1}}, nil)
+	tests := []struct {
+		name    string
+		variant PAVariant
+	}{
+		{"PA", PA},
+		{"PA-I", PAI},
+		{"PA-II", PAII},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			par := &PassiveAggressiveRegressor[float64]{Variant: tt.variant, Tol: 1e-12}
+			if err := par.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			if got := par.Coefficients(); !approxEqual(got, want, 1e-3) {
+				t.Errorf("\nCoefficients():\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	par := &PassiveAggressiveRegressor[float64]{Epsilon: 0.1}
+	for pass := 0; pass < 20; pass++ {
+		for i := 0; i < X.rows; i += 10 {
+			if err := par.PartialFit(X.Slice(i, i+10, 0, 2), y.Slice(i, i+10, 0, 1)); err != nil {
+				t.Fatalf("PartialFit() error: %v", err)
+			}
+		}
+	}
+	yh, _ := par.Predict(X)
+	for i := 0; i < X.rows; i++ {
+		// no sample may be left outside the insensitive zone by much
+		if d := math.Abs(yh.at(i, 0) - y.at(i, 0)); d > 0.2 {
+			t.Errorf("sample %d predicted within %v, want within 0.2", i, d)
+		}
+	}
+}
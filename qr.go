@@ -0,0 +1,196 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+)
+
+// qrDecomp is a Householder QR decomposition A = QR.
+// The Householder vectors are packed on and below the diagonal of qr, and the strictly upper
+// triangle holds R; the diagonal of R is kept separately in rdiag.
+type qrDecomp[T Number] struct {
+	qr      *Matrix[T]
+	rdiag   []T
+	reflect int
+}
+
+func (d *qrDecomp[T]) factor(m *Matrix[T]) error {
+	if m.err != nil {
+		return m.err
+	}
+	a := m.Clone()
+	rows, cols := a.Size()
+	k := cols
+	if rows < k {
+		k = rows
+	}
+	d.qr = a
+	d.rdiag = make([]T, k)
+	for c := 0; c < k; c++ {
+		var nrm T
+		for i := c; i < rows; i++ {
+			nrm += a.at(i, c) * a.at(i, c)
+		}
+		nrm = T(math.Sqrt(float64(nrm)))
+		if nrm != 0 {
+			// reflect onto -sign(a_cc)·‖a‖ e_c so that v_c = 1 + |a_cc|/‖a‖ never cancels
+			if a.at(c, c) < 0 {
+				nrm = -nrm
+			}
+			for i := c; i < rows; i++ {
+				a.set(i, c, a.at(i, c)/nrm)
+			}
+			a.set(c, c, a.at(c, c)+1)
+			for j := c + 1; j < cols; j++ {
+				d.apply(c, a, j)
+			}
+			d.reflect++
+		}
+		d.rdiag[c] = -nrm
+	}
+	return nil
+}
+
+// apply applies the cth Householder reflection to column j of b, in place.
+func (d *qrDecomp[T]) apply(c int, b *Matrix[T], j int) {
+	v := d.qr
+	if v.at(c, c) == 0 {
+		return
+	}
+	var s T
+	for i := c; i < v.rows; i++ {
+		s += v.at(i, c) * b.at(i, j)
+	}
+	s = -s / v.at(c, c)
+	for i := c; i < v.rows; i++ {
+		b.set(i, j, b.at(i, j)+s*v.at(i, c))
+	}
+}
+
+// FactorQR computes the Householder QR decomposition of m.
+// Solve on the result returns the least squares solution when m has more rows than columns.
+func FactorQR[T Number](m *Matrix[T]) (factored[T], error) {
+	d := new(qrDecomp[T])
+	if err := d.factor(m); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// QR returns the reduced QR decomposition of the r x c matrix m: Q is r x k with orthonormal columns
+// and R is k x c upper triangular, where k = min(r, c).
+func QR[T Number](m *Matrix[T]) (q, r *Matrix[T]) {
+	d := new(qrDecomp[T])
+	if err := d.factor(m); err != nil {
+		return errored[T](err), errored[T](err)
+	}
+	return d.Q(), d.R()
+}
+
+// Q returns the orthonormal factor.
+func (d *qrDecomp[T]) Q() *Matrix[T] {
+	rows, k := d.qr.rows, len(d.rdiag)
+	q := Empty[T](rows, k)
+	for c := k - 1; c >= 0; c-- {
+		q.set(c, c, 1)
+		for j := c; j < k; j++ {
+			d.apply(c, q, j)
+		}
+	}
+	return q
+}
+
+// R returns the upper triangular factor.
+func (d *qrDecomp[T]) R() *Matrix[T] {
+	k, cols := len(d.rdiag), d.qr.cols
+	r := Empty[T](k, cols)
+	for i := 0; i < k; i++ {
+		r.set(i, i, d.rdiag[i])
+		for j := i + 1; j < cols; j++ {
+			r.set(i, j, d.qr.at(i, j))
+		}
+	}
+	return r
+}
+
+// fullRank reports whether R has no diagonal element that is zero to working precision.
+func (d *qrDecomp[T]) fullRank() bool {
+	var mx T
+	for _, v := range d.rdiag {
+		if abs(v) > mx {
+			mx = abs(v)
+		}
+	}
+	n := d.qr.rows
+	if d.qr.cols > n {
+		n = d.qr.cols
+	}
+	tol := epsilon[T]() * float64(n) * float64(mx)
+	for _, v := range d.rdiag {
+		if float64(abs(v)) <= tol || v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *qrDecomp[T]) Det() T {
+	var det T = 1
+	for _, v := range d.rdiag {
+		det *= v
+	}
+	if d.reflect%2 == 0 {
+		return det
+	}
+	return -det
+}
+
+// Solve returns x minimising ‖Ax - b‖, where A is the factored matrix. b may have several columns.
+// A must have at least as many rows as columns and full column rank.
+func (d *qrDecomp[T]) Solve(b *Matrix[T]) *Matrix[T] {
+	if b.err != nil {
+		return b
+	}
+	rows, cols := d.qr.Size()
+	if b.rows != rows {
+		return errored[T](fmt.Errorf("Solve: right hand side has %d rows, want %d", b.rows, rows))
+	}
+	if rows < cols {
+		return errored[T](fmt.Errorf("Solve: least squares on an underdetermined (%d x %d) system is undefined", rows, cols))
+	}
+	if !d.fullRank() {
+		return errored[T](fmt.Errorf("Solve: %w: matrix is rank deficient", ErrSingular))
+	}
+
+	// compute Qᵀb, then back substitute with R
+	x := b.Clone()
+	for j := 0; j < x.cols; j++ {
+		for c := 0; c < cols; c++ {
+			d.apply(c, x, j)
+		}
+		for c := cols - 1; c >= 0; c-- {
+			x.set(c, j, x.at(c, j)/d.rdiag[c])
+			for i := 0; i < c; i++ {
+				x.set(i, j, x.at(i, j)-x.at(c, j)*d.qr.at(i, c))
+			}
+		}
+	}
+	return x.Slice(0, cols, 0, x.cols).Clone()
+}
+
+func (d *qrDecomp[T]) Inverse(m *Matrix[T]) *Matrix[T] {
+	return d.Solve(NewIdentity[T](d.qr.rows))
+}
+
+// LeastSquares returns x minimising ‖Ax - b‖ using a QR decomposition of A, without forming AᵀA.
+// A must have at least as many rows as columns and full column rank.
+func LeastSquares[T Number](A, b *Matrix[T]) *Matrix[T] {
+	if A.err != nil {
+		return A
+	}
+	d := new(qrDecomp[T])
+	if err := d.factor(A); err != nil {
+		return errored[T](err)
+	}
+	return d.Solve(b)
+}
@@ -0,0 +1,72 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestQR(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix[float64]
+	}{
+		{"square", NewMatrix([][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}}, nil)},
+		{"tall", NewMatrix([][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}}, nil)},
+		{"wide", NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}}, nil)},
+		{"zero column", NewMatrix([][]float64{{0, 1}, {0, 2}, {0, 3}}, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, r := QR(tt.m)
+			if q.Err() != nil || r.Err() != nil {
+				t.Fatalf("QR() error: %v, %v", q.Err(), r.Err())
+			}
+			if got := q.Mul(r); !approxEqual(got, tt.m, 1e-9) {
+				t.Errorf("\nQR:\n%s\nwant:\n%s", got, tt.m)
+			}
+			for i := 0; i < r.rows; i++ {
+				for j := 0; j < i && j < r.cols; j++ {
+					if r.at(i, j) != 0 {
+						t.Errorf("R(%d, %d) = %v, want 0", i, j, r.at(i, j))
+					}
+				}
+			}
+			if tt.name == "zero column" {
+				return
+			}
+			if got := q.T().Mul(q); !approxEqual(got, NewIdentity[float64](q.cols), 1e-9) {
+				t.Errorf("\nQᵀQ:\n%s\nwant identity", got)
+			}
+		})
+	}
+}
+
+func TestFactorQR(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2, 3, 4}, {2, 1, 0, 1}, {0, 1, 4, 2}, {3, 0, 1, 5}}, nil)
+	f, err := FactorQR(m)
+	if err != nil {
+		t.Fatalf("FactorQR() error: %v", err)
+	}
+	if got := f.Det(); math.Abs(got+46) > 1e-9 {
+		t.Errorf("Det() = %v, want -46", got)
+	}
+	if got := m.Mul(f.Inverse(m)); !approxEqual(got, NewIdentity[float64](4), 1e-9) {
+		t.Errorf("\nm.Mul(Inverse()):\n%s\nwant identity", got)
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	// fit y = a + bx through (0, 6), (1, 0), (2, 0); the least squares line is y = 5 - 3x
+	A := NewMatrix([][]float64{{1, 0}, {1, 1}, {1, 2}}, nil)
+	b := NewMatrix([][]float64{{6}, {0}, {0}}, nil)
+	want := NewMatrix([][]float64{{5}, {-3}}, nil)
+	if got := LeastSquares(A, b); !approxEqual(got, want, 1e-9) {
+		t.Errorf("\nLeastSquares(A, b):\n%s\nwant:\n%s", got, want)
+	}
+
+	collinear := NewMatrix([][]float64{{1, 2}, {2, 4}, {3, 6}}, nil)
+	if err := LeastSquares(collinear, b).Err(); !errors.Is(err, ErrSingular) {
+		t.Errorf("LeastSquares() of rank deficient matrix error = %v, want ErrSingular", err)
+	}
+}
@@ -0,0 +1,49 @@
+package pa
+
+import "fmt"
+
+// Ridge is linear least squares with an L2 penalty, minimising ‖y - Xb‖² + Alpha‖b‖².
+// The intercept is not penalised.
+type Ridge[T Number] struct {
+	// Alpha is the strength of the penalty. It must not be negative; zero gives ordinary least squares.
+	Alpha T
+	// Solver is the method used by Fit. The zero value is SolveCholesky.
+	Solver Solver
+
+	bhat *Matrix[T]
+}
+
+func (r *Ridge[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	if r.Alpha < 0 {
+		return fmt.Errorf("Ridge.Fit: Alpha must not be negative, got %v", r.Alpha)
+	}
+	r.bhat, err = solveLinear(withIntercept(X), y, r.Alpha, r.Solver)
+	return err
+}
+
+func (r *Ridge[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	yh := withIntercept(X).Mul(r.bhat)
+	if yh.Err() != nil {
+		return nil, yh.Err()
+	}
+	return yh, nil
+}
+
+// Coefficients returns the fitted coefficients, with the intercept first.
+func (r *Ridge[T]) Coefficients() *Matrix[T] {
+	return r.bhat
+}
+
+func (r *Ridge[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := r.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
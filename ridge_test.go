@@ -0,0 +1,47 @@
+package pa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRidge(t *testing.T) {
+	// with a centred feature the intercept is mean(y) = 3 and the slope is Σxy/(Σx² + α) = 5/(2 + 3) = 1;
+	// a penalised intercept would be shrunk below 3
+	X := NewMatrix([][]float64{{-1}, {0}, {1}}, nil)
+	y := NewMatrix([][]float64{{1}, {2}, {6}}, nil)
+	want := NewMatrix([][]float64{{3}, {1}}, nil)
+
+	for _, solver := range []Solver{SolveCholesky, SolveQR, SolveSVD} {
+		r := &Ridge[float64]{Alpha: 3, Solver: solver}
+		if err := r.Fit(X, y); err != nil {
+			t.Fatalf("solver %d: Fit() error: %v", solver, err)
+		}
+		if got := r.Coefficients(); !approxEqual(got, want, 1e-9) {
+			t.Errorf("solver %d:\nCoefficients():\n%s\nwant:\n%s", solver, got, want)
+		}
+	}
+
+	// the penalty makes XᵀX + αI invertible even when features are duplicated
+	dup := NewMatrix([][]float64{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, nil)
+	r := &Ridge[float64]{Alpha: 1}
+	if err := r.Fit(dup, NewMatrix([][]float64{{1}, {5}, {9}, {13}}, nil)); err != nil {
+		t.Errorf("Fit() on duplicated features error: %v", err)
+	}
+	if err := (&Ridge[float64]{Alpha: -1}).Fit(X, y); err == nil {
+		t.Error("Fit() with negative Alpha returned nil error")
+	}
+
+	// an errored X is reported as is, not as a failed factorization of an empty design
+	bad := X.Mul(X)
+	for _, solver := range []Solver{SolveCholesky, SolveQR, SolveSVD} {
+		if err := (&Ridge[float64]{Alpha: 1, Solver: solver}).Fit(bad, y); err == nil || errors.Is(err, ErrNotPositiveDefinite) {
+			t.Errorf("solver %d: Fit() with errored X error = %v, want X's error", solver, err)
+		} else if err != bad.Err() {
+			t.Errorf("solver %d: Fit() error = %v, want %v", solver, err, bad.Err())
+		}
+	}
+	if _, err := r.Predict(bad); err != bad.Err() {
+		t.Errorf("Predict() with errored X error = %v, want %v", err, bad.Err())
+	}
+}
@@ -0,0 +1,80 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+)
+
+// StandardScaler standardizes each feature to zero mean and unit variance.
+// Features with zero variance are centred but not scaled.
+type StandardScaler[T Float] struct {
+	mean, scale []T
+}
+
+func (s *StandardScaler[T]) Fit(X *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if X.rows == 0 {
+		return fmt.Errorf("StandardScaler.Fit: no samples")
+	}
+	n, p := X.Size()
+	mu := X.Mean(Column)
+	s.mean = mu.row(0)
+	s.scale = make([]T, p)
+	for i := 0; i < n; i++ {
+		for j, v := range X.row(i) {
+			d := v - s.mean[j]
+			s.scale[j] += d * d
+		}
+	}
+	for j, ss := range s.scale {
+		s.scale[j] = T(math.Sqrt(float64(ss / T(n))))
+		if s.scale[j] == 0 {
+			s.scale[j] = 1
+		}
+	}
+	return nil
+}
+
+func (s *StandardScaler[T]) Transform(X *Matrix[T]) (*Matrix[T], error) {
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != len(s.mean) {
+		return nil, fmt.Errorf("StandardScaler.Transform: X has %d features, fitted on %d", X.cols, len(s.mean))
+	}
+	out := X.Clone()
+	for i := 0; i < out.rows; i++ {
+		row := out.row(i)
+		for j := range row {
+			row[j] = (row[j] - s.mean[j]) / s.scale[j]
+		}
+	}
+	return out, nil
+}
+
+func (s *StandardScaler[T]) FitTransform(X *Matrix[T]) (*Matrix[T], error) {
+	if err := s.Fit(X); err != nil {
+		return nil, err
+	}
+	return s.Transform(X)
+}
+
+// InverseTransform maps standardized samples back to the original scale.
+func (s *StandardScaler[T]) InverseTransform(X *Matrix[T]) (*Matrix[T], error) {
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if X.cols != len(s.mean) {
+		return nil, fmt.Errorf("StandardScaler.InverseTransform: X has %d features, fitted on %d", X.cols, len(s.mean))
+	}
+	out := X.Clone()
+	for i := 0; i < out.rows; i++ {
+		row := out.row(i)
+		for j := range row {
+			row[j] = row[j]*s.scale[j] + s.mean[j]
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,28 @@
+package pa
+
+import "testing"
+
+func TestStandardScaler(t *testing.T) {
+	X := NewMatrix([][]float64{{1, 5}, {3, 5}, {5, 5}}, nil)
+	s := new(StandardScaler[float64])
+	got, err := s.FitTransform(X)
+	if err != nil {
+		t.Fatalf("FitTransform() error: %v", err)
+	}
+	// the first column has mean 3 and standard deviation √(8/3); the second is constant
+	k := 1 / 1.632993161855452
+	want := NewMatrix([][]float64{{-2 * k, 0}, {0, 0}, {2 * k, 0}}, nil)
+	if !approxEqual(got, want, 1e-12) {
+		t.Errorf("\nFitTransform(X):\n%s\nwant:\n%s", got, want)
+	}
+	back, err := s.InverseTransform(got)
+	if err != nil {
+		t.Fatalf("InverseTransform() error: %v", err)
+	}
+	if !approxEqual(back, X, 1e-12) {
+		t.Errorf("\nInverseTransform(FitTransform(X)):\n%s\nwant:\n%s", back, X)
+	}
+	if _, err := s.Transform(NewMatrix([][]float64{{1}}, nil)); err == nil {
+		t.Error("Transform() with the wrong number of features returned nil error")
+	}
+}
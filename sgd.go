@@ -0,0 +1,634 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// LearningRate selects the step size schedule of the stochastic gradient descent models.
+type LearningRate int
+
+const (
+	// LearningRateInvScaling decays the step as Eta0/t^PowerT after t samples.
+	LearningRateInvScaling LearningRate = iota
+	// LearningRateConstant keeps the step at Eta0.
+	LearningRateConstant
+	// LearningRateOptimal decays the step as 1/(Alpha(t₀ + t)), with t₀ chosen by Bottou's heuristic.
+	// It requires a positive Alpha.
+	LearningRateOptimal
+	// LearningRateAdaptive keeps the step at Eta0 while the loss improves, and divides it by five
+	// each time it stalls for NIterNoChange passes. Fitting stops once the step falls below 1e-6.
+	LearningRateAdaptive
+)
+
+// sgdConfig holds the validated settings of a stochastic gradient descent model, with defaults filled in.
+type sgdConfig struct {
+	loss           Loss
+	alpha, l1Ratio float64
+	schedule       LearningRate
+	eta0, powerT   float64
+	tol            float64
+	maxIter        int
+	patience       int
+	shuffle        bool
+	seed           int64
+	earlyStopping  bool
+	validation     float64
+}
+
+func (c sgdConfig) withDefaults(penalty Penalty) (sgdConfig, error) {
+	if c.alpha < 0 {
+		return c, fmt.Errorf("Alpha must not be negative, got %v", c.alpha)
+	}
+	switch penalty {
+	case PenaltyL2:
+		c.l1Ratio = 0
+	case PenaltyL1:
+		c.l1Ratio = 1
+	case PenaltyElasticNet:
+		if c.l1Ratio < 0 || c.l1Ratio > 1 {
+			return c, fmt.Errorf("L1Ratio must be in [0, 1], got %v", c.l1Ratio)
+		}
+	default:
+		return c, fmt.Errorf("unknown penalty %d", penalty)
+	}
+	if c.schedule < LearningRateInvScaling || c.schedule > LearningRateAdaptive {
+		return c, fmt.Errorf("unknown learning rate schedule %d", c.schedule)
+	}
+	if c.schedule == LearningRateOptimal && c.alpha == 0 {
+		return c, fmt.Errorf("LearningRateOptimal requires a positive Alpha")
+	}
+	if c.eta0 < 0 {
+		return c, fmt.Errorf("Eta0 must not be negative, got %v", c.eta0)
+	}
+	if c.eta0 == 0 {
+		c.eta0 = 0.01
+	}
+	if c.powerT == 0 {
+		c.powerT = 0.25
+	}
+	if c.tol == 0 {
+		c.tol = defaultTolerance
+	}
+	if c.maxIter == 0 {
+		c.maxIter = defaultMaxIter
+	}
+	if c.patience == 0 {
+		c.patience = 5
+	}
+	if c.validation == 0 {
+		c.validation = 0.1
+	}
+	if c.validation < 0 || c.validation >= 1 {
+		return c, fmt.Errorf("ValidationFraction must be in (0, 1), got %v", c.validation)
+	}
+	return c, nil
+}
+
+// sgdState holds k linear outputs trained by stochastic gradient descent, along with everything
+// needed to resume training on the next call to PartialFit.
+type sgdState struct {
+	w, q [][]float64 // the weights of each output, and the L1 penalty already applied to each weight
+	b    []float64
+	u    float64 // the total L1 penalty any weight could have received so far
+	t    float64 // one more than the number of samples seen
+	eta  float64 // the current step of LearningRateAdaptive
+	t0   float64 // the offset of LearningRateOptimal
+	rng  *rand.Rand
+}
+
+func newSGDState(cfg sgdConfig, p, k int) *sgdState {
+	s := &sgdState{
+		w:   make([][]float64, k),
+		q:   make([][]float64, k),
+		b:   make([]float64, k),
+		t:   1,
+		eta: cfg.eta0,
+		rng: rand.New(rand.NewSource(cfg.seed)),
+	}
+	for c := range s.w {
+		s.w[c], s.q[c] = make([]float64, p), make([]float64, p)
+	}
+	if cfg.schedule == LearningRateOptimal {
+		// Bottou's heuristic: start with the step that suits weights of typical size 1/√α
+		typw := math.Sqrt(1 / math.Sqrt(cfg.alpha))
+		eta0 := typw / math.Max(1, cfg.loss.Derivative(-typw, 1))
+		s.t0 = 1 / (eta0 * cfg.alpha)
+	}
+	return s
+}
+
+// rate returns the step size for the current sample.
+func (s *sgdState) rate(cfg sgdConfig) float64 {
+	switch cfg.schedule {
+	case LearningRateConstant:
+		return cfg.eta0
+	case LearningRateOptimal:
+		return 1 / (cfg.alpha * (s.t0 + s.t - 1))
+	case LearningRateAdaptive:
+		return s.eta
+	}
+	return cfg.eta0 / math.Pow(s.t, cfg.powerT)
+}
+
+// step takes one gradient step on the sample x with one target per output, returning its loss before the step.
+// The L1 part of the penalty uses the cumulative truncation of Tsuruoka et al. (2009),
+// which leaves unhelpful weights at exactly zero.
+func (s *sgdState) step(cfg sgdConfig, x, y []float64) float64 {
+	eta := s.rate(cfg)
+	l1, l2 := cfg.alpha*cfg.l1Ratio, cfg.alpha*(1-cfg.l1Ratio)
+	shrink := math.Max(0, 1-eta*l2)
+	s.u += eta * l1
+	var loss float64
+	for c, w := range s.w {
+		p := dotf(w, x) + s.b[c]
+		loss += cfg.loss.Loss(p, y[c])
+		d := cfg.loss.Derivative(p, y[c])
+		if shrink != 1 {
+			scale(shrink, w)
+		}
+		if d != 0 {
+			axpy(-eta*d, x, w)
+			s.b[c] -= eta * d
+		}
+		if l1 > 0 {
+			q := s.q[c]
+			for j, z := range w {
+				switch {
+				case z > 0:
+					w[j] = math.Max(0, z-(s.u+q[j]))
+				case z < 0:
+					w[j] = math.Min(0, z+(s.u-q[j]))
+				}
+				q[j] += w[j] - z
+			}
+		}
+	}
+	s.t++
+	return loss
+}
+
+// epoch makes one pass over the rows of xs listed in order, shuffling order first if asked to,
+// and returns the mean loss.
+func (s *sgdState) epoch(cfg sgdConfig, xs, ys *Matrix[float64], order []int) float64 {
+	if cfg.shuffle {
+		s.rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	var loss float64
+	for _, i := range order {
+		loss += s.step(cfg, xs.row(i), ys.row(i))
+	}
+	return loss / float64(len(order))
+}
+
+// fit makes passes over xs and ys until the training loss, or with early stopping the score of the
+// outputs on a held-out part of the data, has not improved by more than tol for patience passes.
+// It returns the number of passes made and whether it stopped before maxIter.
+func (s *sgdState) fit(cfg sgdConfig, xs, ys *Matrix[float64], score func(d, y *Matrix[float64]) float64) (int, bool, error) {
+	var xv, yv *Matrix[float64]
+	if cfg.earlyStopping {
+		n := xs.rows
+		idx := rand.New(rand.NewSource(cfg.seed)).Perm(n)
+		nv := int(math.Ceil(cfg.validation * float64(n)))
+		if n-nv < 1 {
+			return 0, false, fmt.Errorf("too few samples (%d) to hold out a validation fraction of %v", n, cfg.validation)
+		}
+		xv, yv = xs.pick(idx[:nv]), ys.pick(idx[:nv])
+		xs, ys = xs.pick(idx[nv:]), ys.pick(idx[nv:])
+	}
+	order := make([]int, xs.rows)
+	for i := range order {
+		order[i] = i
+	}
+	best, stale := math.Inf(1), 0
+	for iter := 1; iter <= cfg.maxIter; iter++ {
+		cur := s.epoch(cfg, xs, ys, order)
+		if xv != nil {
+			cur = -score(s.decision(xv), yv)
+		}
+		if cur > best-cfg.tol {
+			stale++
+		} else {
+			stale = 0
+		}
+		best = math.Min(best, cur)
+		if stale < cfg.patience {
+			continue
+		}
+		if cfg.schedule != LearningRateAdaptive || s.eta < 1e-6 {
+			return iter, true, nil
+		}
+		s.eta /= 5
+		stale = 0
+	}
+	return cfg.maxIter, false, nil
+}
+
+// decision returns the n x k outputs for the rows of xs.
+func (s *sgdState) decision(xs *Matrix[float64]) *Matrix[float64] {
+	d := Empty[float64](xs.rows, len(s.w))
+	for i := 0; i < xs.rows; i++ {
+		x := xs.row(i)
+		for c, w := range s.w {
+			d.set(i, c, dotf(w, x)+s.b[c])
+		}
+	}
+	return d
+}
+
+// sgdCoefficients returns the (p+1) x k weights of s, with the intercepts in the first row.
+func sgdCoefficients[T Float](s *sgdState) *Matrix[T] {
+	coef := Empty[T](len(s.w[0])+1, len(s.w))
+	for c, w := range s.w {
+		coef.set(0, c, T(s.b[c]))
+		for j, v := range w {
+			coef.set(j+1, c, T(v))
+		}
+	}
+	return coef
+}
+
+// ovrTargets returns the ±1 targets of the one-vs-rest problems for labels drawn from k classes.
+// With two classes there is a single problem, whose positive class is the second.
+func ovrTargets(labels []int, k int) *Matrix[float64] {
+	if k == 2 {
+		k = 1
+	}
+	ys := Filled[float64](len(labels), k, -1)
+	for i, l := range labels {
+		switch {
+		case k > 1:
+			ys.set(i, l, 1)
+		case l == 1:
+			ys.set(i, 0, 1)
+		}
+	}
+	return ys
+}
+
+// ovrAccuracy returns the fraction of rows in which the largest output of d picks the positive target in ys.
+func ovrAccuracy(d, ys *Matrix[float64]) float64 {
+	d, ys = binaryScores(d), binaryScores(ys)
+	classes := make([]float64, d.cols)
+	for c := range classes {
+		classes[c] = float64(c)
+	}
+	return accuracy(decodeLabels(ys, classes), decodeLabels(d, classes))
+}
+
+// SGDClassifier is a linear classifier trained by stochastic gradient descent, one sample at a time.
+// The choice of Loss gives a linear support vector machine (HingeLoss, the default),
+// logistic regression (LogisticLoss), and so on. More than two classes are handled one-vs-rest.
+// Because each step touches a single sample, it scales to data too large for the closed-form solvers.
+type SGDClassifier[T Float] struct {
+	// Loss is the loss minimised. If nil, HingeLoss is used.
+	Loss Loss
+	// Penalty selects the regularization term. The zero value is PenaltyL2.
+	Penalty Penalty
+	// Alpha is the strength of the penalty; zero fits an unpenalised model.
+	Alpha T
+	// L1Ratio mixes the penalties under PenaltyElasticNet: 1 is L1, 0 is L2.
+	L1Ratio T
+	// LearningRate is the step size schedule. The zero value is LearningRateInvScaling.
+	LearningRate LearningRate
+	// Eta0 is the initial step size. If zero, 0.01 is used.
+	Eta0 float64
+	// PowerT is the exponent of LearningRateInvScaling. If zero, 0.25 is used.
+	PowerT float64
+	// Tol stops Fit once the training loss, or the validation accuracy under EarlyStopping,
+	// has not improved by Tol for NIterNoChange passes. If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 1000 is used.
+	MaxIter int
+	// NIterNoChange is the number of passes without improvement that stops Fit. If zero, 5 is used.
+	NIterNoChange int
+	// Shuffle reorders the samples before each pass, using a source seeded by Seed.
+	Shuffle bool
+	// Seed seeds shuffling and the validation split, so fits are reproducible.
+	Seed int64
+	// EarlyStopping holds out ValidationFraction of the samples passed to Fit, and stops
+	// once the accuracy on them stops improving rather than the training loss.
+	EarlyStopping bool
+	// ValidationFraction is the fraction held out by EarlyStopping. If zero, 0.1 is used.
+	ValidationFraction float64
+
+	classes []T
+	state   *sgdState
+	coef    *Matrix[T]
+	iters   int
+}
+
+func (sc *SGDClassifier[T]) config() (sgdConfig, error) {
+	loss := sc.Loss
+	if loss == nil {
+		loss = HingeLoss{}
+	}
+	return sgdConfig{
+		loss:          loss,
+		alpha:         float64(sc.Alpha),
+		l1Ratio:       float64(sc.L1Ratio),
+		schedule:      sc.LearningRate,
+		eta0:          sc.Eta0,
+		powerT:        sc.PowerT,
+		tol:           sc.Tol,
+		maxIter:       sc.MaxIter,
+		patience:      sc.NIterNoChange,
+		shuffle:       sc.Shuffle,
+		seed:          sc.Seed,
+		earlyStopping: sc.EarlyStopping,
+		validation:    sc.ValidationFraction,
+	}.withDefaults(sc.Penalty)
+}
+
+// Fit fits the model from scratch, making repeated passes over X and y.
+func (sc *SGDClassifier[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("SGDClassifier.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	cfg, err := sc.config()
+	if err != nil {
+		return fmt.Errorf("SGDClassifier.Fit: %w", err)
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("SGDClassifier.Fit: need samples of at least two classes, got %d", len(classes))
+	}
+	ys := ovrTargets(labels, len(classes))
+	sc.classes, sc.state = classes, newSGDState(cfg, p, ys.cols)
+	iters, converged, err := sc.state.fit(cfg, convert[float64](X), ys, ovrAccuracy)
+	sc.coef, sc.iters = sgdCoefficients[T](sc.state), iters
+	if err != nil {
+		return fmt.Errorf("SGDClassifier.Fit: %w", err)
+	}
+	if !converged {
+		return fmt.Errorf("SGDClassifier.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+// classes lists every label the model will ever see; it is required on the first call and ignored afterwards.
+func (sc *SGDClassifier[T]) PartialFit(X, y *Matrix[T], classes []T) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	cfg, err := sc.config()
+	if err != nil {
+		return fmt.Errorf("SGDClassifier.PartialFit: %w", err)
+	}
+	if sc.state == nil {
+		cs, err := initClasses(classes)
+		if err != nil {
+			return fmt.Errorf("SGDClassifier.PartialFit: %w", err)
+		}
+		k := len(cs)
+		if k == 2 {
+			k = 1
+		}
+		sc.classes, sc.state = cs, newSGDState(cfg, X.cols, k)
+	}
+	labels, err := indexLabels(X, y, sc.classes, len(sc.state.w[0]))
+	if err != nil {
+		return fmt.Errorf("SGDClassifier.PartialFit: %w", err)
+	}
+	order := make([]int, X.rows)
+	for i := range order {
+		order[i] = i
+	}
+	sc.state.epoch(cfg, convert[float64](X), ovrTargets(labels, len(sc.classes)), order)
+	sc.coef = sgdCoefficients[T](sc.state)
+	return nil
+}
+
+// DecisionFunction returns the signed margin of each row of X: a column vector for a binary problem,
+// where positive values favour the second class, otherwise one column per class.
+func (sc *SGDClassifier[T]) DecisionFunction(X *Matrix[T]) (*Matrix[T], error) {
+	d := withIntercept(X).Mul(sc.coef)
+	if d.Err() != nil {
+		return nil, d.Err()
+	}
+	return d, nil
+}
+
+func (sc *SGDClassifier[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	d, err := sc.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLabels(binaryScores(d), sc.classes), nil
+}
+
+// PredictProba returns the class probabilities for the rows of X. Only LogisticLoss, or a loss embedding it, models probabilities:
+// the one-vs-rest sigmoids are normalized to sum to one. Under any other loss all the mass goes to the predicted class.
+func (sc *SGDClassifier[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	d, err := sc.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sc.Loss.(probabilistic); !ok {
+		return oneHot(binaryScores(d)), nil
+	}
+	proba := Empty[T](d.rows, len(sc.classes))
+	for i := 0; i < d.rows; i++ {
+		out := proba.row(i)
+		if d.cols == 1 {
+			p := sigmoid(float64(d.at(i, 0)))
+			out[0], out[1] = T(1-p), T(p)
+			continue
+		}
+		var total float64
+		for c, v := range d.row(i) {
+			p := sigmoid(float64(v))
+			out[c] = T(p)
+			total += p
+		}
+		for c := range out {
+			out[c] /= T(total)
+		}
+	}
+	return proba, nil
+}
+
+func (sc *SGDClassifier[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := sc.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (sc *SGDClassifier[T]) Classes() []T {
+	return sc.classes
+}
+
+// Coefficients returns the (p+1) x k weights, with the intercepts in the first row.
+// For a binary problem k is 1.
+func (sc *SGDClassifier[T]) Coefficients() *Matrix[T] {
+	return sc.coef
+}
+
+// Iterations returns the number of passes made by the last call to Fit.
+func (sc *SGDClassifier[T]) Iterations() int {
+	return sc.iters
+}
+
+// SGDRegressor is a linear regressor trained by stochastic gradient descent, one sample at a time.
+// The choice of Loss gives least squares (SquaredLoss, the default), robust regression (HuberLoss),
+// or linear support vector regression (EpsilonInsensitiveLoss).
+// Because each step touches a single sample, it scales to data too large for the closed-form solvers.
+type SGDRegressor[T Float] struct {
+	// Loss is the loss minimised. If nil, SquaredLoss is used.
+	Loss Loss
+	// Penalty selects the regularization term. The zero value is PenaltyL2.
+	Penalty Penalty
+	// Alpha is the strength of the penalty; zero fits an unpenalised model.
+	Alpha T
+	// L1Ratio mixes the penalties under PenaltyElasticNet: 1 is L1, 0 is L2.
+	L1Ratio T
+	// LearningRate is the step size schedule. The zero value is LearningRateInvScaling.
+	LearningRate LearningRate
+	// Eta0 is the initial step size. If zero, 0.01 is used.
+	Eta0 float64
+	// PowerT is the exponent of LearningRateInvScaling. If zero, 0.25 is used.
+	PowerT float64
+	// Tol stops Fit once the training loss, or the validation R² under EarlyStopping,
+	// has not improved by Tol for NIterNoChange passes. If zero, 1e-4 is used.
+	Tol float64
+	// MaxIter bounds the number of passes over the data made by Fit. If zero, 1000 is used.
+	MaxIter int
+	// NIterNoChange is the number of passes without improvement that stops Fit. If zero, 5 is used.
+	NIterNoChange int
+	// Shuffle reorders the samples before each pass, using a source seeded by Seed.
+	Shuffle bool
+	// Seed seeds shuffling and the validation split, so fits are reproducible.
+	Seed int64
+	// EarlyStopping holds out ValidationFraction of the samples passed to Fit, and stops
+	// once the R² on them stops improving rather than the training loss.
+	EarlyStopping bool
+	// ValidationFraction is the fraction held out by EarlyStopping. If zero, 0.1 is used.
+	ValidationFraction float64
+
+	state *sgdState
+	coef  *Matrix[T]
+	iters int
+}
+
+func (sr *SGDRegressor[T]) config() (sgdConfig, error) {
+	loss := sr.Loss
+	if loss == nil {
+		loss = SquaredLoss{}
+	}
+	return sgdConfig{
+		loss:          loss,
+		alpha:         float64(sr.Alpha),
+		l1Ratio:       float64(sr.L1Ratio),
+		schedule:      sr.LearningRate,
+		eta0:          sr.Eta0,
+		powerT:        sr.PowerT,
+		tol:           sr.Tol,
+		maxIter:       sr.MaxIter,
+		patience:      sr.NIterNoChange,
+		shuffle:       sr.Shuffle,
+		seed:          sr.Seed,
+		earlyStopping: sr.EarlyStopping,
+		validation:    sr.ValidationFraction,
+	}.withDefaults(sr.Penalty)
+}
+
+// Fit fits the model from scratch, making repeated passes over X and y.
+func (sr *SGDRegressor[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("SGDRegressor.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	cfg, err := sr.config()
+	if err != nil {
+		return fmt.Errorf("SGDRegressor.Fit: %w", err)
+	}
+	sr.state = newSGDState(cfg, p, 1)
+	iters, converged, err := sr.state.fit(cfg, convert[float64](X), convert[float64](y), func(d, y *Matrix[float64]) float64 {
+		return r2Score(y, d)
+	})
+	sr.coef, sr.iters = sgdCoefficients[T](sr.state), iters
+	if err != nil {
+		return fmt.Errorf("SGDRegressor.Fit: %w", err)
+	}
+	if !converged {
+		return fmt.Errorf("SGDRegressor.Fit: %w after %d passes", ErrNoConvergence, iters)
+	}
+	return nil
+}
+
+// PartialFit makes a single pass over the mini-batch X and y, updating the current weights.
+func (sr *SGDRegressor[T]) PartialFit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("SGDRegressor.PartialFit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	cfg, err := sr.config()
+	if err != nil {
+		return fmt.Errorf("SGDRegressor.PartialFit: %w", err)
+	}
+	if sr.state == nil {
+		sr.state = newSGDState(cfg, p, 1)
+	}
+	if p != len(sr.state.w[0]) {
+		return fmt.Errorf("SGDRegressor.PartialFit: X has %d features, model has %d", p, len(sr.state.w[0]))
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sr.state.epoch(cfg, convert[float64](X), convert[float64](y), order)
+	sr.coef = sgdCoefficients[T](sr.state)
+	return nil
+}
+
+func (sr *SGDRegressor[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	yh := withIntercept(X).Mul(sr.coef)
+	if yh.Err() != nil {
+		return nil, yh.Err()
+	}
+	return yh, nil
+}
+
+func (sr *SGDRegressor[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := sr.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return r2Score(y, yh), nil
+}
+
+// Coefficients returns the weights, with the intercept first.
+func (sr *SGDRegressor[T]) Coefficients() *Matrix[T] {
+	return sr.coef
+}
+
+// Iterations returns the number of passes made by the last call to Fit.
+func (sr *SGDRegressor[T]) Iterations() int {
+	return sr.iters
+}
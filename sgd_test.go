@@ -0,0 +1,217 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSGDClassifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		sc      *SGDClassifier[float64]
+		centres [][]float64
+		min     float64
+	}{
+		{"hinge binary", &SGDClassifier[float64]{Shuffle: true}, [][]float64{{0, 0}, {4, 4}}, 0.95},
+		{"logistic binary", &SGDClassifier[float64]{Loss: LogisticLoss{}, Shuffle: true}, [][]float64{{0, 0}, {4, 4}}, 0.95},
+		{"hinge multiclass optimal", &SGDClassifier[float64]{Alpha: 1e-3, LearningRate: LearningRateOptimal, Shuffle: true},
+			[][]float64{{0, 0}, {8, 0}, {0, 8}}, 0.95},
+		{"logistic multiclass adaptive", &SGDClassifier[float64]{Loss: LogisticLoss{}, LearningRate: LearningRateAdaptive, Shuffle: true},
+			[][]float64{{0, 0}, {8, 0}, {0, 8}}, 0.95},
+		// early stopping gives up some training accuracy for fewer passes
+		{"early stopping", &SGDClassifier[float64]{EarlyStopping: true, Shuffle: true, Seed: 3}, [][]float64{{0, 0}, {4, 4}}, 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := blobs(tt.centres, 40)
+			if err := tt.sc.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := tt.sc.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < tt.min {
+				t.Errorf("Score() = %v, want at least %v", acc, tt.min)
+			}
+			proba, err := tt.sc.PredictProba(X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if s := sum(proba.row(i)); math.Abs(s-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, s)
+				}
+			}
+		})
+	}
+}
+
+// wrappedLoss is a user-defined loss that extends LogisticLoss, as a caller instrumenting training might.
+type wrappedLoss struct {
+	LogisticLoss
+	calls int
+}
+
+func (w *wrappedLoss) Derivative(p, y float64) float64 {
+	w.calls++
+	return w.LogisticLoss.Derivative(p, y)
+}
+
+func TestSGDClassifierLossForms(t *testing.T) {
+	// any loss built on LogisticLoss is as probabilistic as LogisticLoss itself
+	X, y := blobs([][]float64{{0, 0}, {2, 2}}, 30)
+	byValue := &SGDClassifier[float64]{Loss: LogisticLoss{}}
+	if err := byValue.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	want, _ := byValue.PredictProba(X)
+	wrapped := &wrappedLoss{}
+	for _, loss := range []Loss{&LogisticLoss{}, wrapped} {
+		sc := &SGDClassifier[float64]{Loss: loss}
+		if err := sc.Fit(X, y); err != nil {
+			t.Fatalf("%T: Fit() error: %v", loss, err)
+		}
+		got, err := sc.PredictProba(X)
+		if err != nil {
+			t.Fatalf("%T: PredictProba() error: %v", loss, err)
+		}
+		if !approxEqual(got, want, 1e-12) {
+			t.Errorf("PredictProba() with %T:\n%s\nwant as with LogisticLoss:\n%s", loss, got, want)
+		}
+	}
+	if wrapped.calls == 0 {
+		t.Error("Fit() did not train through the wrapped loss")
+	}
+}
+
+func TestSGDClassifierPartialFit(t *testing.T) {
+	X, y := shuffled(blobs([][]float64{{0, 0}, {4, 4}}, 50))
+	sc := &SGDClassifier[float64]{Loss: LogisticLoss{}, LearningRate: LearningRateConstant, Eta0: 0.1}
+	for pass := 0; pass < 5; pass++ {
+		for i := 0; i < X.rows; i += 20 {
+			if err := sc.PartialFit(X.Slice(i, i+20, 0, 2), y.Slice(i, i+20, 0, 1), []float64{0, 1}); err != nil {
+				t.Fatalf("PartialFit() error: %v", err)
+			}
+		}
+	}
+	acc, _ := sc.Score(X, y)
+	if acc < 0.95 {
+		t.Errorf("Score() = %v, want at least 0.95", acc)
+	}
+}
+
+func TestSGDRegressor(t *testing.T) {
+	// y = 1 + 2a - b exactly, so every loss should recover the coefficients
+	X, y := Empty[float64](100, 2), Empty[float64](100, 1)
+	for i := 0; i < 100; i++ {
+		a, b := math.Sin(float64(i)), math.Cos(float64(3*i))
+		X.set(i, 0, a)
+		X.set(i, 1, b)
+		y.set(i, 0, 1+2*a-b)
+	}
+	want := NewMatrix([][]float64{{1}, {2}, {-1}}, nil)
+	tests := []struct {
+		name string
+		sr   *SGDRegressor[float64]
+		tol  float64
+	}{
+		{"squared", &SGDRegressor[float64]{Shuffle: true, Tol: 1e-8}, 1e-2},
+		{"squared constant", &SGDRegressor[float64]{LearningRate: LearningRateConstant, Eta0: 0.05, Tol: 1e-10}, 1e-3},
+		{"huber", &SGDRegressor[float64]{Loss: HuberLoss{Epsilon: 1}, Shuffle: true, Tol: 1e-8}, 1e-2},
+		{"epsilon-insensitive", &SGDRegressor[float64]{Loss: EpsilonInsensitiveLoss{}, LearningRate: LearningRateAdaptive, Eta0: 0.1}, 5e-2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sr.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			if got := tt.sr.Coefficients(); !approxEqual(got, want, tt.tol) {
+				t.Errorf("\nCoefficients():\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSGDRegressorPenalty(t *testing.T) {
+	// only the first of four features is informative
+	X, y := Empty[float64](100, 4), Empty[float64](100, 1)
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 4; j++ {
+			X.set(i, j, math.Sin(float64(i*(j+2))))
+		}
+		y.set(i, 0, 3*X.at(i, 0))
+	}
+	tests := []struct {
+		name    string
+		penalty Penalty
+		zeros   bool
+	}{
+		{"L2", PenaltyL2, false},
+		{"L1", PenaltyL1, true},
+		{"elastic net", PenaltyElasticNet, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &SGDRegressor[float64]{Penalty: tt.penalty, Alpha: 0.05, L1Ratio: 0.8, Shuffle: true}
+			if err := sr.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			w := sr.Coefficients()
+			if w.at(1, 0) < 1 {
+				t.Errorf("informative coefficient = %v, want above 1", w.at(1, 0))
+			}
+			var zeros int
+			for j := 2; j < 5; j++ {
+				if w.at(j, 0) == 0 {
+					zeros++
+				}
+			}
+			if tt.zeros && zeros != 3 {
+				t.Errorf("\nCoefficients():\n%s\nwant the noise coefficients exactly zero", w)
+			}
+			if !tt.zeros && zeros != 0 {
+				t.Errorf("\nCoefficients():\n%s\nwant no coefficient exactly zero", w)
+			}
+		})
+	}
+}
+
+func TestSGDSeed(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {3, 3}}, 30)
+	fit := func(seed int64) *Matrix[float64] {
+		sc := &SGDClassifier[float64]{Shuffle: true, Seed: seed, MaxIter: 3}
+		if err := sc.Fit(X, y); err != nil && !errors.Is(err, ErrNoConvergence) {
+			t.Fatalf("Fit() error: %v", err)
+		}
+		return sc.Coefficients()
+	}
+	a, b, c := fit(7), fit(7), fit(8)
+	if !approxEqual(a, b, 0) {
+		t.Errorf("fits with the same seed differ:\n%s\n%s", a, b)
+	}
+	if approxEqual(a, c, 0) {
+		t.Error("fits with different seeds are identical")
+	}
+}
+
+func TestSGDErrors(t *testing.T) {
+	X, y := blobs([][]float64{{0, 0}, {3, 3}}, 5)
+	tests := []struct {
+		name string
+		sc   *SGDClassifier[float64]
+	}{
+		{"optimal without alpha", &SGDClassifier[float64]{LearningRate: LearningRateOptimal}},
+		{"L1Ratio out of range", &SGDClassifier[float64]{Penalty: PenaltyElasticNet, L1Ratio: 2}},
+		{"validation fraction too large", &SGDClassifier[float64]{EarlyStopping: true, ValidationFraction: 1}},
+		{"negative alpha", &SGDClassifier[float64]{Alpha: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sc.Fit(X, y); err == nil {
+				t.Error("Fit() returned nil error")
+			}
+		})
+	}
+}
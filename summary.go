@@ -0,0 +1,195 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RegressionSummary holds the classical inference for an ordinary least squares fit,
+// under the assumption of independent, normally distributed errors with constant variance.
+// The per-coefficient slices are in the order of Coefficients, intercept first.
+type RegressionSummary struct {
+	// Names labels the coefficients: "intercept", then the column names of X, or x1, x2, ... if it has none.
+	Names []string
+	// Coef holds the estimates, StdErr their standard errors and TStat their ratio.
+	Coef, StdErr, TStat []float64
+	// PValue holds the two-sided p-values of the t-tests that each coefficient is zero.
+	PValue []float64
+	// Lower and Upper bound the 95% confidence intervals of the coefficients.
+	Lower, Upper []float64
+
+	// NObs is the number of samples; DFModel and DFResid are the model and residual degrees of freedom.
+	NObs, DFModel, DFResid int
+	// RSquared is the coefficient of determination, and AdjRSquared its adjustment for DFModel.
+	RSquared, AdjRSquared float64
+	// FStat tests that every coefficient but the intercept is zero, and FPValue is its p-value.
+	FStat, FPValue float64
+	// LogLikelihood is the maximized Gaussian log-likelihood, and AIC and BIC the information criteria built on it.
+	// They count the coefficients but not the error variance as parameters, so R's AIC is 2 higher.
+	LogLikelihood, AIC, BIC float64
+
+	// DurbinWatson tests the residuals for first-order autocorrelation in row order.
+	// It is near 2 for uncorrelated residuals, and towards 0 or 4 for positive or negative correlation.
+	DurbinWatson float64
+	// Skew and Kurtosis are the moment estimates of the residual distribution; a normal one has 0 and 3.
+	Skew, Kurtosis float64
+	// JarqueBera tests the residuals for normality from Skew and Kurtosis, and JBPValue is its p-value.
+	JarqueBera, JBPValue float64
+}
+
+// linearFit is what a least squares fit keeps for its RegressionSummary.
+type linearFit[T Number] struct {
+	// resid holds the residuals y - Xb in sample order, and tss the sum of squares of y about its mean.
+	resid []float64
+	tss   float64
+	// factor is the Cholesky factorization of XᵀX if the fit made one; otherwise inner is XᵀX.
+	factor factored[T]
+	inner  *Matrix[T]
+	// names labels the columns of X after the intercept.
+	names []string
+}
+
+// newLinearFit keeps what summarize needs of the fit of bhat to X, with a leading intercept column, and y,
+// a single column. factor is the Cholesky factorization of XᵀX, or nil if the solver made none.
+func newLinearFit[T Number](X, y, bhat *Matrix[T], factor factored[T], names []string) *linearFit[T] {
+	f := &linearFit[T]{resid: make([]float64, X.rows), factor: factor, names: names}
+	if factor == nil {
+		f.inner = X.T().Mul(X)
+	}
+	b := convert[float64](bhat)
+	row := make([]float64, X.cols)
+	var ybar float64
+	for i := range f.resid {
+		for j, v := range X.row(i) {
+			row[j] = float64(v)
+		}
+		f.resid[i] = float64(y.at(i, 0)) - dotf(row, b.data)
+		ybar += float64(y.at(i, 0))
+	}
+	ybar /= float64(X.rows)
+	for i := 0; i < y.rows; i++ {
+		d := float64(y.at(i, 0)) - ybar
+		f.tss += d * d
+	}
+	return f
+}
+
+// summarize computes the RegressionSummary of the least squares coefficients bhat of the fit.
+func (f *linearFit[T]) summarize(bhat *Matrix[T]) (*RegressionSummary, error) {
+	n, p := len(f.resid), bhat.rows
+	if n <= p {
+		return nil, fmt.Errorf("need more samples than the %d coefficients, got %d", p, n)
+	}
+	factor := f.factor
+	if factor == nil {
+		var err error
+		if factor, err = FactorCholesky(f.inner); err != nil {
+			// only SolveQR and SolveSVD get this far with collinear features
+			return nil, fmt.Errorf("coefficients are not identified: %w", err)
+		}
+	}
+	cov := convert[float64](factor.Solve(NewIdentity[T](p)))
+	if cov.Err() != nil {
+		return nil, cov.Err()
+	}
+	b := convert[float64](bhat)
+	resid, tss := f.resid, f.tss
+	var rss float64
+	for _, e := range resid {
+		rss += e * e
+	}
+
+	s := &RegressionSummary{
+		Names:   make([]string, p),
+		Coef:    make([]float64, p),
+		StdErr:  make([]float64, p),
+		TStat:   make([]float64, p),
+		PValue:  make([]float64, p),
+		Lower:   make([]float64, p),
+		Upper:   make([]float64, p),
+		NObs:    n,
+		DFModel: p - 1,
+		DFResid: n - p,
+	}
+	df := float64(s.DFResid)
+	sigma2 := rss / df
+	q := studentTISF(0.025, df)
+	for j := 0; j < p; j++ {
+		switch {
+		case j == 0:
+			s.Names[j] = "intercept"
+		case len(f.names) == p-1:
+			s.Names[j] = f.names[j-1]
+		default:
+			s.Names[j] = fmt.Sprintf("x%d", j)
+		}
+		s.Coef[j] = b.at(j, 0)
+		s.StdErr[j] = math.Sqrt(sigma2 * cov.at(j, j))
+		s.TStat[j] = s.Coef[j] / s.StdErr[j]
+		s.PValue[j] = 2 * studentTSF(math.Abs(s.TStat[j]), df)
+		s.Lower[j] = s.Coef[j] - q*s.StdErr[j]
+		s.Upper[j] = s.Coef[j] + q*s.StdErr[j]
+	}
+
+	s.RSquared = 1 - rss/tss
+	s.AdjRSquared = 1 - (1-s.RSquared)*float64(n-1)/df
+	s.FStat, s.FPValue = math.NaN(), math.NaN()
+	if s.DFModel > 0 {
+		s.FStat = (tss - rss) / float64(s.DFModel) / sigma2
+		s.FPValue = fisherSF(s.FStat, float64(s.DFModel), df)
+	}
+	s.LogLikelihood = -float64(n) / 2 * (math.Log(2*math.Pi*rss/float64(n)) + 1)
+	s.AIC = -2*s.LogLikelihood + 2*float64(p)
+	s.BIC = -2*s.LogLikelihood + math.Log(float64(n))*float64(p)
+
+	var diff float64
+	for i := 1; i < n; i++ {
+		d := resid[i] - resid[i-1]
+		diff += d * d
+	}
+	s.DurbinWatson = diff / rss
+	// the residuals of a model with an intercept have mean zero, but centre them anyway
+	m := mean(resid)
+	var m2, m3, m4 float64
+	for _, e := range resid {
+		d := e - m
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	m2, m3, m4 = m2/float64(n), m3/float64(n), m4/float64(n)
+	s.Skew = m3 / math.Pow(m2, 1.5)
+	s.Kurtosis = m4 / (m2 * m2)
+	s.JarqueBera = float64(n) / 6 * (s.Skew*s.Skew + (s.Kurtosis-3)*(s.Kurtosis-3)/4)
+	// the statistic is asymptotically χ² with two degrees of freedom, whose tail is exp(-x/2)
+	s.JBPValue = math.Exp(-s.JarqueBera / 2)
+	return s, nil
+}
+
+// String formats the summary as a report: the fit statistics, the coefficient table and the residual diagnostics.
+func (s *RegressionSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-16s %12d    %-16s %12.4f\n", "Observations:", s.NObs, "R-squared:", s.RSquared)
+	fmt.Fprintf(&b, "%-16s %12d    %-16s %12.4f\n", "Df model:", s.DFModel, "Adj. R-squared:", s.AdjRSquared)
+	fmt.Fprintf(&b, "%-16s %12d    %-16s %12.4g\n", "Df residuals:", s.DFResid, "F-statistic:", s.FStat)
+	fmt.Fprintf(&b, "%-16s %12.4g    %-16s %12.4g\n", "Log-likelihood:", s.LogLikelihood, "Prob(F):", s.FPValue)
+	fmt.Fprintf(&b, "%-16s %12.4g    %-16s %12.4g\n\n", "AIC:", s.AIC, "BIC:", s.BIC)
+
+	width := len("intercept")
+	for _, name := range s.Names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	fmt.Fprintf(&b, "%-*s %12s %12s %10s %10s %12s %12s\n", width, "", "coef", "std err", "t", "P>|t|", "[0.025", "0.975]")
+	for j, name := range s.Names {
+		fmt.Fprintf(&b, "%-*s %12.4g %12.4g %10.3f %10.4f %12.4g %12.4g\n",
+			width, name, s.Coef[j], s.StdErr[j], s.TStat[j], s.PValue[j], s.Lower[j], s.Upper[j])
+	}
+
+	fmt.Fprintf(&b, "\n%-16s %12.4f    %-16s %12.4f\n", "Durbin-Watson:", s.DurbinWatson, "Jarque-Bera:", s.JarqueBera)
+	fmt.Fprintf(&b, "%-16s %12.4f    %-16s %12.4g\n", "Skew:", s.Skew, "Prob(JB):", s.JBPValue)
+	fmt.Fprintf(&b, "%-16s %12.4f\n", "Kurtosis:", s.Kurtosis)
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package pa
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinearRegressionSummary(t *testing.T) {
+	// the plant weights of R's ?lm (Dobson, 1990): weight ~ group, with the treatment group coded 1
+	ctl := []float64{4.17, 5.58, 5.18, 6.11, 4.50, 4.61, 5.17, 4.53, 5.33, 5.14}
+	trt := []float64{4.81, 4.17, 4.41, 3.59, 5.87, 3.83, 6.03, 4.89, 4.32, 4.69}
+	var rows, ys [][]float64
+	for i, w := range append(ctl, trt...) {
+		rows = append(rows, []float64{float64(i / len(ctl))})
+		ys = append(ys, []float64{w})
+	}
+	X, y := NewMatrix(rows, []string{"groupTrt"}), NewMatrix(ys, nil)
+
+	lr := new(LinearRegression[float64])
+	if err := lr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	s, err := lr.Summary()
+	if err != nil {
+		t.Fatalf("Summary() error: %v", err)
+	}
+	// the estimates R reports, to the digits it prints
+	tests := []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{"intercept", s.Coef[0], 5.0320, 5e-5},
+		{"groupTrt", s.Coef[1], -0.3710, 5e-5},
+		{"intercept std err", s.StdErr[0], 0.2202, 5e-5},
+		{"groupTrt std err", s.StdErr[1], 0.3114, 5e-5},
+		{"intercept t", s.TStat[0], 22.850, 5e-4},
+		{"groupTrt t", s.TStat[1], -1.191, 5e-4},
+		{"intercept p", s.PValue[0], 9.55e-15, 5e-17},
+		{"groupTrt p", s.PValue[1], 0.249, 5e-4},
+		{"intercept lower", s.Lower[0], 4.56934, 5e-6},
+		{"intercept upper", s.Upper[0], 5.49466, 5e-6},
+		{"groupTrt lower", s.Lower[1], -1.0253, 5e-5},
+		{"groupTrt upper", s.Upper[1], 0.2833, 5e-5},
+		{"R²", s.RSquared, 0.07308, 5e-6},
+		{"adjusted R²", s.AdjRSquared, 0.02158, 5e-6},
+		{"F", s.FStat, 1.419, 5e-4},
+		{"F p-value", s.FPValue, 0.249, 5e-4},
+		{"log-likelihood", s.LogLikelihood, -20.08824, 5e-6},
+		{"AIC", s.AIC, 46.17648 - 2, 5e-6},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > tt.tol {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if s.NObs != 20 || s.DFModel != 1 || s.DFResid != 18 {
+		t.Errorf("NObs, DFModel, DFResid = %d, %d, %d, want 20, 1, 18", s.NObs, s.DFModel, s.DFResid)
+	}
+	// with a single feature the F-test is the square of its t-test
+	if math.Abs(s.FStat-s.TStat[1]*s.TStat[1]) > 1e-9 || math.Abs(s.FPValue-s.PValue[1]) > 1e-9 {
+		t.Errorf("F = %v (p %v), want t² = %v (p %v)", s.FStat, s.FPValue, s.TStat[1]*s.TStat[1], s.PValue[1])
+	}
+	if r2, _ := lr.Score(X, y); math.Abs(r2-s.RSquared) > 1e-12 {
+		t.Errorf("RSquared = %v, want Score() = %v", s.RSquared, r2)
+	}
+	if want := s.AIC + (math.Log(20)-2)*2; math.Abs(s.BIC-want) > 1e-9 {
+		t.Errorf("BIC = %v, want %v", s.BIC, want)
+	}
+	if s.JBPValue <= 0 || s.JBPValue > 1 || s.DurbinWatson <= 0 || s.DurbinWatson >= 4 {
+		t.Errorf("JBPValue = %v, DurbinWatson = %v", s.JBPValue, s.DurbinWatson)
+	}
+	// SolveCholesky hands its factorization to Summary; SolveQR makes Summary factor XᵀX itself
+	qr := &LinearRegression[float64]{Solver: SolveQR}
+	if err := qr.Fit(X, y); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if sq, err := qr.Summary(); err != nil {
+		t.Errorf("SolveQR Summary() error: %v", err)
+	} else {
+		for j := range s.StdErr {
+			if math.Abs(sq.StdErr[j]-s.StdErr[j]) > 1e-12 {
+				t.Errorf("SolveQR standard error %d = %v, want %v", j, sq.StdErr[j], s.StdErr[j])
+			}
+		}
+	}
+	// the summary depends only on what Fit kept, not on the training data afterwards
+	Xc, yc := X.Clone(), y.Clone()
+	kept := new(LinearRegression[float64])
+	if err := kept.Fit(Xc, yc); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	for i := 0; i < yc.rows; i++ {
+		Xc.set(i, 0, 0)
+		yc.set(i, 0, 0)
+	}
+	if sk, err := kept.Summary(); err != nil || !reflect.DeepEqual(sk, s) {
+		t.Errorf("Summary() after overwriting the training data = %+v, %v, want %+v", sk, err, s)
+	}
+	if again, _ := lr.Summary(); again != s {
+		t.Error("Summary() recomputed the summary of an unchanged fit")
+	}
+
+	report := s.String()
+	for _, want := range []string{"intercept", "groupTrt", "R-squared:", "Durbin-Watson:", "0.2202"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("String() does not mention %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestSummaryDiagnostics(t *testing.T) {
+	// y = 1 + 2x plus a residual pattern orthogonal to the intercept and x
+	pattern := func(f func(i int) float64) *RegressionSummary {
+		n := 40
+		X, y := Empty[float64](n, 1), Empty[float64](n, 1)
+		for i := 0; i < n; i++ {
+			X.set(i, 0, float64(i))
+			y.set(i, 0, 1+2*float64(i)+f(i))
+		}
+		lr := new(LinearRegression[float64])
+		if err := lr.Fit(X, y); err != nil {
+			t.Fatalf("Fit() error: %v", err)
+		}
+		s, err := lr.Summary()
+		if err != nil {
+			t.Fatalf("Summary() error: %v", err)
+		}
+		return s
+	}
+
+	// alternating residuals are perfectly negatively autocorrelated, and symmetric with kurtosis 1
+	s := pattern(func(i int) float64 { return float64(1 - 2*(i%2)) })
+	if s.DurbinWatson < 3.8 {
+		t.Errorf("alternating residuals: DurbinWatson = %v, want near 4", s.DurbinWatson)
+	}
+	if math.Abs(s.Skew) > 0.05 || math.Abs(s.Kurtosis-1) > 0.05 {
+		t.Errorf("alternating residuals: Skew, Kurtosis = %v, %v, want 0, 1", s.Skew, s.Kurtosis)
+	}
+	if want := 40.0 / 6 * (s.Skew*s.Skew + (s.Kurtosis-3)*(s.Kurtosis-3)/4); math.Abs(s.JarqueBera-want) > 1e-9 {
+		t.Errorf("JarqueBera = %v, want %v", s.JarqueBera, want)
+	}
+	if s.JBPValue > 0.05 {
+		t.Errorf("alternating residuals: JBPValue = %v, want normality rejected", s.JBPValue)
+	}
+
+	// a slow wave is strongly positively autocorrelated
+	s = pattern(func(i int) float64 { return math.Sin(float64(i) / 3) })
+	if s.DurbinWatson > 0.5 {
+		t.Errorf("slow wave: DurbinWatson = %v, want near 0", s.DurbinWatson)
+	}
+}
+
+func TestLinearRegressionSummaryErrors(t *testing.T) {
+	if _, err := new(LinearRegression[float64]).Summary(); err == nil {
+		t.Error("Summary() before Fit returned nil error")
+	}
+
+	tests := []struct {
+		name string
+		lr   *LinearRegression[float64]
+		X, y *Matrix[float64]
+	}{
+		{
+			name: "collinear features",
+			lr:   &LinearRegression[float64]{Solver: SolveSVD},
+			X:    NewMatrix([][]float64{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}}, nil),
+			y:    NewMatrix([][]float64{{1}, {5}, {9}, {14}, {17}}, nil),
+		},
+		{
+			name: "as many samples as coefficients",
+			lr:   new(LinearRegression[float64]),
+			X:    NewMatrix([][]float64{{0}, {1}}, nil),
+			y:    NewMatrix([][]float64{{1}, {3}}, nil),
+		},
+		{
+			name: "several targets",
+			lr:   new(LinearRegression[float64]),
+			X:    NewMatrix([][]float64{{0}, {1}, {2}, {3}}, nil),
+			y:    NewMatrix([][]float64{{1, 0}, {3, 1}, {4, 3}, {7, 2}}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the fit itself still succeeds
+			if err := tt.lr.Fit(tt.X, tt.y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			if _, err := tt.lr.Summary(); err == nil {
+				t.Error("Summary() returned nil error")
+			}
+		})
+	}
+
+	// the error from an undefined summary says why
+	lr := &LinearRegression[float64]{Solver: SolveSVD}
+	_ = lr.Fit(tests[0].X, tests[0].y)
+	if _, err := lr.Summary(); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("collinear Summary() error = %v, want ErrNotPositiveDefinite", err)
+	}
+}
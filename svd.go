@@ -0,0 +1,242 @@
+package pa
+
+import (
+	"math"
+	"sort"
+)
+
+// maxSweeps bounds the number of Jacobi sweeps. Convergence is quadratic, so in practice
+// fewer than a dozen sweeps are needed even for large matrices.
+const maxSweeps = 60
+
+// SVD returns the thin singular value decomposition m = UΣVᵀ of the r x c matrix m,
+// computed by one-sided Jacobi rotations. With k = min(r, c), U is r x k with orthonormal columns,
+// Σ is the k x k diagonal matrix of singular values in descending order and Vᵀ is k x c.
+func SVD[T Float](m *Matrix[T]) (u, s, vt *Matrix[T]) {
+	if m.err != nil {
+		return m, m, m
+	}
+	if m.rows == 0 || m.cols == 0 {
+		return Empty[T](m.rows, 0), Empty[T](0, 0), Empty[T](0, m.cols)
+	}
+	if m.rows < m.cols {
+		// factor the transpose, whose columns are the rows of m
+		v, s, ut := SVD(m.T())
+		return ut.T(), s, v.T()
+	}
+	sigma, w, vt := jacobiSVD(m)
+	k := len(sigma)
+	s = Empty[T](k, k)
+	for i, v := range sigma {
+		s.set(i, i, v)
+	}
+	return w.T(), s, vt
+}
+
+// jacobiSVD factors m, which must have at least as many rows as columns.
+// It returns the singular values in descending order, Uᵀ and Vᵀ.
+func jacobiSVD[T Float](m *Matrix[T]) ([]T, *Matrix[T], *Matrix[T]) {
+	// work on the rows of Aᵀ so every rotation touches contiguous memory;
+	// rotating rows of the identity alongside accumulates Vᵀ
+	w := m.T().Clone()
+	n := w.rows
+	vt := NewIdentity[T](n)
+	eps := epsilon[T]()
+
+	for sweep := 0; sweep < maxSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				wp, wq := w.row(p), w.row(q)
+				var alpha, beta, gamma float64
+				for i := range wp {
+					alpha += float64(wp[i]) * float64(wp[i])
+					beta += float64(wq[i]) * float64(wq[i])
+					gamma += float64(wp[i]) * float64(wq[i])
+				}
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				rotate(wp, wq, T(c), T(c*t))
+				rotate(vt.row(p), vt.row(q), T(c), T(c*t))
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	sigma := make([]T, n)
+	for j := 0; j < n; j++ {
+		sigma[j] = T(math.Sqrt(float64(sumSquares(w.row(j)))))
+	}
+
+	// sort rows of Uᵀ and Vᵀ by descending singular value
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return sigma[order[a]] > sigma[order[b]] })
+	ut, vts := Empty[T](n, w.cols), Empty[T](n, n)
+	sorted := make([]T, n)
+	for i, j := range order {
+		sorted[i] = sigma[j]
+		copy(ut.row(i), w.row(j))
+		copy(vts.row(i), vt.row(j))
+	}
+
+	tol := eps * float64(w.cols) * float64(sorted[0])
+	for i, s := range sorted {
+		row := ut.row(i)
+		if float64(s) > tol && s != 0 {
+			for j := range row {
+				row[j] /= s
+			}
+			continue
+		}
+		// the left singular vector of a zero singular value is arbitrary;
+		// complete Uᵀ with a unit vector orthogonal to the rows before it
+		completeBasis(ut, i)
+	}
+	return sorted, ut, vts
+}
+
+// rotate applies the plane rotation [c s; -s c] to the pair of vectors (x, y) in place.
+func rotate[T Number](x, y []T, c, s T) {
+	for i := range x {
+		xi, yi := x[i], y[i]
+		x[i] = c*xi - s*yi
+		y[i] = s*xi + c*yi
+	}
+}
+
+func sumSquares[T Number](a []T) T {
+	var s T
+	for _, v := range a {
+		s += v * v
+	}
+	return s
+}
+
+// completeBasis overwrites row i of m with a unit vector orthogonal to rows 0 through i-1,
+// which must already be orthonormal.
+func completeBasis[T Float](m *Matrix[T], i int) {
+	row := m.row(i)
+	for e := 0; e < len(row); e++ {
+		for j := range row {
+			row[j] = 0
+		}
+		row[e] = 1
+		// orthogonalize twice; one pass of classical Gram-Schmidt loses orthogonality
+		for pass := 0; pass < 2; pass++ {
+			for k := 0; k < i; k++ {
+				prev := m.row(k)
+				var d T
+				for j := range row {
+					d += row[j] * prev[j]
+				}
+				for j := range row {
+					row[j] -= d * prev[j]
+				}
+			}
+		}
+		nrm := T(math.Sqrt(float64(sumSquares(row))))
+		if nrm > 0.5 {
+			for j := range row {
+				row[j] /= nrm
+			}
+			return
+		}
+	}
+}
+
+// singularValues returns the singular values of m in descending order, computed in float64.
+func singularValues[T Number](m *Matrix[T]) []float64 {
+	a := convert[float64](m)
+	if a.rows < a.cols {
+		a = a.T()
+	}
+	sigma, _, _ := jacobiSVD(a)
+	return sigma
+}
+
+// defaultTol is the tolerance below which singular values are treated as zero:
+// σmax · max(r, c) · ε, with ε the machine epsilon of T.
+func defaultTol[T Number](m *Matrix[T], sigma []float64) float64 {
+	n := m.rows
+	if m.cols > n {
+		n = m.cols
+	}
+	eps := epsilon[T]()
+	if eps == 0 {
+		eps = epsilon[float64]()
+	}
+	return sigma[0] * float64(n) * eps
+}
+
+// Pinv returns the Moore-Penrose pseudoinverse of m, computed from its SVD.
+// Singular values below the default tolerance of Rank are treated as zero.
+func (m *Matrix[T]) Pinv() *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	if m.rows == 0 || m.cols == 0 {
+		return Empty[T](m.cols, m.rows)
+	}
+	u, s, vt := SVD(convert[float64](m))
+	sigma := make([]float64, s.rows)
+	for i := range sigma {
+		sigma[i] = s.at(i, i)
+	}
+	tol := defaultTol(m, sigma)
+	// VΣ⁺Uᵀ: scale the columns of V by the reciprocal singular values, then multiply by Uᵀ
+	v := vt.T()
+	for i := 0; i < v.rows; i++ {
+		row := v.row(i)
+		for j, sv := range sigma {
+			if sv > tol {
+				row[j] /= sv
+			} else {
+				row[j] = 0
+			}
+		}
+	}
+	return convert[T](v.Mul(u.T()))
+}
+
+// Rank returns the number of singular values of m greater than tol.
+// If tol is not positive, σmax · max(r, c) · ε is used, with ε the machine epsilon of T.
+func (m *Matrix[T]) Rank(tol float64) int {
+	if m.err != nil || m.rows == 0 || m.cols == 0 {
+		return 0
+	}
+	sigma := singularValues(m)
+	if tol <= 0 {
+		tol = defaultTol(m, sigma)
+	}
+	rank := 0
+	for _, s := range sigma {
+		if s > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+// Cond returns the 2-norm condition number of m, the ratio of its largest to smallest singular value.
+// It is +Inf for singular matrices.
+func (m *Matrix[T]) Cond() float64 {
+	if m.err != nil || m.rows == 0 || m.cols == 0 {
+		return math.NaN()
+	}
+	sigma := singularValues(m)
+	smin := sigma[len(sigma)-1]
+	if smin == 0 {
+		return math.Inf(1)
+	}
+	return sigma[0] / smin
+}
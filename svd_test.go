@@ -0,0 +1,99 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSVD(t *testing.T) {
+	tests := []struct {
+		name  string
+		m     *Matrix[float64]
+		sigma []float64
+	}{
+		{"wide", NewMatrix([][]float64{{3, 2, 2}, {2, 3, -2}}, nil), []float64{5, 3}},
+		{"tall", NewMatrix([][]float64{{3, 2}, {2, 3}, {2, -2}}, nil), []float64{5, 3}},
+		{"diagonal", NewMatrix([][]float64{{1, 0, 0}, {0, -4, 0}, {0, 0, 2}}, nil), []float64{4, 2, 1}},
+		{"rank one", NewMatrix([][]float64{{1, 2}, {2, 4}, {3, 6}}, nil), []float64{math.Sqrt(70), 0}},
+		{"unit", NewMatrix([][]float64{{-2}}, nil), []float64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, s, vt := SVD(tt.m)
+			for i, want := range tt.sigma {
+				if got := s.at(i, i); math.Abs(got-want) > 1e-9 {
+					t.Errorf("σ%d = %v, want %v", i, got, want)
+				}
+			}
+			if got := u.Mul(s).Mul(vt); !approxEqual(got, tt.m, 1e-9) {
+				t.Errorf("\nUΣVᵀ:\n%s\nwant:\n%s", got, tt.m)
+			}
+			k := len(tt.sigma)
+			if got := u.T().Mul(u); !approxEqual(got, NewIdentity[float64](k), 1e-9) {
+				t.Errorf("\nUᵀU:\n%s\nwant identity", got)
+			}
+			if got := vt.Mul(vt.T()); !approxEqual(got, NewIdentity[float64](k), 1e-9) {
+				t.Errorf("\nVᵀV:\n%s\nwant identity", got)
+			}
+		})
+	}
+}
+
+func TestMatrix_Pinv(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix[float64]
+		want *Matrix[float64]
+	}{
+		{
+			name: "invertible",
+			m:    NewMatrix([][]float64{{2, 3}, {2, 2}}, nil),
+			want: NewMatrix([][]float64{{-1, 3.0 / 2.0}, {1, -1}}, nil),
+		},
+		{
+			name: "rank one",
+			m:    NewMatrix([][]float64{{1, 1}, {1, 1}}, nil),
+			want: NewMatrix([][]float64{{0.25, 0.25}, {0.25, 0.25}}, nil),
+		},
+		{
+			name: "row",
+			m:    NewMatrix([][]float64{{1, 2, 2}}, nil),
+			want: NewMatrix([][]float64{{1.0 / 9}, {2.0 / 9}, {2.0 / 9}}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Pinv(); !approxEqual(got, tt.want, 1e-9) {
+				t.Errorf("\nPinv():\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrix_RankCond(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Matrix[float64]
+		rank int
+		cond float64
+	}{
+		{"identity", NewIdentity[float64](3), 3, 1},
+		{"diagonal", NewMatrix([][]float64{{1, 0}, {0, -4}}, nil), 2, 4},
+		{"rank two", NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, nil), 2, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Rank(0); got != tt.rank {
+				t.Errorf("Rank(0) = %d, want %d", got, tt.rank)
+			}
+			got := tt.m.Cond()
+			if math.IsInf(tt.cond, 1) {
+				if got < 1e15 {
+					t.Errorf("Cond() = %v, want > 1e15", got)
+				}
+			} else if math.Abs(got-tt.cond) > 1e-9 {
+				t.Errorf("Cond() = %v, want %v", got, tt.cond)
+			}
+		})
+	}
+}
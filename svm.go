@@ -0,0 +1,551 @@
+package pa
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SVC is a kernel support vector classifier. Each pair of classes is separated by the
+// maximum-margin boundary in the feature space of Kernel, found by sequential minimal optimization
+// of the dual problem; a sample is assigned the class that wins the most pairwise contests.
+type SVC[T Float] struct {
+	// Kernel is the similarity between samples. If nil, RBFKernel with the default Gamma is used.
+	Kernel Kernel[T]
+	// C trades margin width against training errors; larger values fit the training data more closely.
+	// If zero, 1 is used.
+	C float64
+	// Tol stops optimization once the most violating pair of samples violates the optimality
+	// conditions by less than Tol. If zero, 1e-3 is used.
+	Tol float64
+	// MaxIter bounds the number of optimization steps for each pair of classes.
+	// If zero, max(100000, 100n) is used for n samples.
+	MaxIter int
+
+	classes  []T
+	kernel   Kernel[T]
+	support  *Matrix[T]
+	machines []svcMachine
+}
+
+// svcMachine is the binary classifier between classes a and b, whose decision function
+// Σ coef[i]·K(support[sv[i]], x) - rho is positive in favour of b.
+type svcMachine struct {
+	a, b int
+	sv   []int
+	coef []float64
+	rho  float64
+}
+
+// Fit fits one binary machine for each pair of classes in y.
+func (s *SVC[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n := X.rows
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("SVC.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	c := s.C
+	if c < 0 {
+		return fmt.Errorf("SVC.Fit: C must not be negative, got %v", c)
+	}
+	if c == 0 {
+		c = 1
+	}
+	tol := s.Tol
+	if tol == 0 {
+		tol = 1e-3
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("SVC.Fit: need samples of at least two classes, got %d", len(classes))
+	}
+	s.kernel = s.Kernel
+	if s.kernel == nil {
+		s.kernel = RBFKernel[T]{}
+	}
+
+	s.classes, s.machines = classes, nil
+	support := map[int]int{}
+	var supportRows []int
+	for a := range classes {
+		for b := a + 1; b < len(classes); b++ {
+			var rows []Array[T]
+			var idx []int
+			var ys []float64
+			for i, l := range labels {
+				switch l {
+				case a:
+					ys = append(ys, -1)
+				case b:
+					ys = append(ys, 1)
+				default:
+					continue
+				}
+				rows = append(rows, X.row(i))
+				idx = append(idx, i)
+			}
+			maxIter := s.MaxIter
+			if maxIter == 0 {
+				maxIter = 100 * len(rows)
+				if maxIter < 100000 {
+					maxIter = 100000
+				}
+			}
+			alpha, rho, converged := smo(ys, newGram(s.kernel, rows), c, tol, maxIter)
+			if !converged {
+				return fmt.Errorf("SVC.Fit: classes %v and %v: %w after %d iterations", classes[a], classes[b], ErrNoConvergence, maxIter)
+			}
+			m := svcMachine{a: a, b: b, rho: rho}
+			for t, al := range alpha {
+				if al == 0 {
+					continue
+				}
+				k, ok := support[idx[t]]
+				if !ok {
+					k = len(supportRows)
+					support[idx[t]] = k
+					supportRows = append(supportRows, idx[t])
+				}
+				m.sv = append(m.sv, k)
+				m.coef = append(m.coef, al*ys[t])
+			}
+			s.machines = append(s.machines, m)
+		}
+	}
+	s.support = X.pick(supportRows)
+	return nil
+}
+
+// gram is a kernel matrix whose rows are computed on first use.
+type gram[T Number] struct {
+	kernel Kernel[T]
+	x      []Array[T]
+	rows   [][]float64
+}
+
+func newGram[T Number](kernel Kernel[T], x []Array[T]) *gram[T] {
+	return &gram[T]{kernel: kernel, x: x, rows: make([][]float64, len(x))}
+}
+
+func (g *gram[T]) row(i int) []float64 {
+	if g.rows[i] == nil {
+		r := make([]float64, len(g.x))
+		for j, x := range g.x {
+			r[j] = g.kernel.Eval(g.x[i], x)
+		}
+		g.rows[i] = r
+	}
+	return g.rows[i]
+}
+
+func (g *gram[T]) diag(i int) float64 {
+	if g.rows[i] != nil {
+		return g.rows[i][i]
+	}
+	return g.kernel.Eval(g.x[i], g.x[i])
+}
+
+// smo minimises αᵀQα/2 - Σα subject to 0 ≤ α ≤ c and yᵀα = 0, where Q[i][j] = y[i]y[j]K[i][j],
+// by sequential minimal optimization with the second-order working set selection of Fan, Chen and Lin (2005).
+// It returns α and the offset ρ of the decision function Σ α[i]y[i]K(x[i], x) - ρ, and whether it converged.
+func smo[T Number](y []float64, k *gram[T], c, tol float64, maxIter int) ([]float64, float64, bool) {
+	const tau = 1e-12
+	n := len(y)
+	alpha, grad, diag := make([]float64, n), make([]float64, n), make([]float64, n)
+	for t := range grad {
+		grad[t] = -1
+		diag[t] = k.diag(t)
+	}
+	up := func(t int) bool { return (y[t] > 0 && alpha[t] < c) || (y[t] < 0 && alpha[t] > 0) }
+	low := func(t int) bool { return (y[t] > 0 && alpha[t] > 0) || (y[t] < 0 && alpha[t] < c) }
+
+	converged := false
+	for iter := 0; iter < maxIter; iter++ {
+		// i is the sample that most violates the optimality conditions
+		i, gmax := -1, math.Inf(-1)
+		for t := range y {
+			if up(t) && -y[t]*grad[t] >= gmax {
+				i, gmax = t, -y[t]*grad[t]
+			}
+		}
+		if i < 0 {
+			converged = true
+			break
+		}
+		// j is the partner that gives the largest decrease in the objective
+		ki := k.row(i)
+		j, gmin, best := -1, math.Inf(-1), math.Inf(1)
+		for t := range y {
+			if !low(t) {
+				continue
+			}
+			v := y[t] * grad[t]
+			gmin = math.Max(gmin, v)
+			if b := gmax + v; b > 0 {
+				a := diag[i] + diag[t] - 2*ki[t]
+				if a <= 0 {
+					a = tau
+				}
+				if obj := -b * b / a; obj <= best {
+					j, best = t, obj
+				}
+			}
+		}
+		if gmax+gmin < tol || j < 0 {
+			converged = true
+			break
+		}
+
+		// solve the two-variable subproblem analytically, then clip to the box
+		kj := k.row(j)
+		qij := y[i] * y[j] * ki[j]
+		ai, aj := alpha[i], alpha[j]
+		if y[i] != y[j] {
+			quad := diag[i] + diag[j] + 2*qij
+			if quad <= 0 {
+				quad = tau
+			}
+			delta := (-grad[i] - grad[j]) / quad
+			diff := ai - aj
+			alpha[i] += delta
+			alpha[j] += delta
+			if diff > 0 {
+				if alpha[j] < 0 {
+					alpha[j], alpha[i] = 0, diff
+				}
+			} else if alpha[i] < 0 {
+				alpha[i], alpha[j] = 0, -diff
+			}
+			if diff > 0 {
+				if alpha[i] > c {
+					alpha[i], alpha[j] = c, c-diff
+				}
+			} else if alpha[j] > c {
+				alpha[j], alpha[i] = c, c+diff
+			}
+		} else {
+			quad := diag[i] + diag[j] - 2*qij
+			if quad <= 0 {
+				quad = tau
+			}
+			delta := (grad[i] - grad[j]) / quad
+			sum := ai + aj
+			alpha[i] -= delta
+			alpha[j] += delta
+			if sum > c {
+				if alpha[i] > c {
+					alpha[i], alpha[j] = c, sum-c
+				}
+			} else if alpha[j] < 0 {
+				alpha[j], alpha[i] = 0, sum
+			}
+			if sum > c {
+				if alpha[j] > c {
+					alpha[j], alpha[i] = c, sum-c
+				}
+			} else if alpha[i] < 0 {
+				alpha[i], alpha[j] = 0, sum
+			}
+		}
+		di, dj := alpha[i]-ai, alpha[j]-aj
+		for t := range grad {
+			grad[t] += y[t] * (y[i]*ki[t]*di + y[j]*kj[t]*dj)
+		}
+	}
+
+	// ρ is the mean of y·∇ over the free samples, or the middle of its feasible range if there are none
+	ub, lb := math.Inf(1), math.Inf(-1)
+	var free int
+	var sumFree float64
+	for t := range y {
+		yg := y[t] * grad[t]
+		switch {
+		case alpha[t] >= c && y[t] < 0, alpha[t] <= 0 && y[t] > 0:
+			ub = math.Min(ub, yg)
+		case alpha[t] >= c, alpha[t] <= 0:
+			lb = math.Max(lb, yg)
+		default:
+			free++
+			sumFree += yg
+		}
+	}
+	rho := (ub + lb) / 2
+	if free > 0 {
+		rho = sumFree / float64(free)
+	}
+	return alpha, rho, converged
+}
+
+// DecisionFunction returns the decision value of each binary machine for the rows of X:
+// a column vector for a binary problem, where positive values favour the second class, otherwise one column
+// per pair of classes in the order (0, 1), (0, 2), ..., (1, 2), ..., each positive in favour of the later class.
+func (s *SVC[T]) DecisionFunction(X *Matrix[T]) (*Matrix[T], error) {
+	if X.Err() != nil {
+		return nil, X.Err()
+	}
+	if s.support == nil {
+		return nil, fmt.Errorf("SVC.DecisionFunction: model is not fitted")
+	}
+	if X.cols != s.support.cols {
+		return nil, fmt.Errorf("SVC.DecisionFunction: X has %d features, model has %d", X.cols, s.support.cols)
+	}
+	d := Empty[T](X.rows, len(s.machines))
+	kv := make([]float64, s.support.rows)
+	for i := 0; i < X.rows; i++ {
+		x := X.row(i)
+		for j := range kv {
+			kv[j] = s.kernel.Eval(x, s.support.row(j))
+		}
+		for c, m := range s.machines {
+			f := -m.rho
+			for t, sv := range m.sv {
+				f += m.coef[t] * kv[sv]
+			}
+			d.set(i, c, T(f))
+		}
+	}
+	return d, nil
+}
+
+// votes returns the number of pairwise contests each class wins for the rows of X.
+func (s *SVC[T]) votes(X *Matrix[T]) (*Matrix[T], error) {
+	d, err := s.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	v := Empty[T](X.rows, len(s.classes))
+	for i := 0; i < d.rows; i++ {
+		for c, m := range s.machines {
+			winner := m.a
+			if d.at(i, c) > 0 {
+				winner = m.b
+			}
+			v.set(i, winner, v.at(i, winner)+1)
+		}
+	}
+	return v, nil
+}
+
+// Predict returns the class winning the most pairwise contests for each row of X, breaking ties in favour of the smaller class.
+func (s *SVC[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	v, err := s.votes(X)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLabels(v, s.classes), nil
+}
+
+// PredictProba returns the fraction of pairwise contests each class wins for the rows of X.
+// These are not calibrated probabilities; with two classes all the mass goes to the predicted class.
+func (s *SVC[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	v, err := s.votes(X)
+	if err != nil {
+		return nil, err
+	}
+	return v.Product(1 / T(len(s.machines))), nil
+}
+
+func (s *SVC[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := s.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (s *SVC[T]) Classes() []T {
+	return s.classes
+}
+
+// SupportVectors returns the training samples with non-zero dual coefficients in any of the machines.
+// Only these are needed to make predictions.
+func (s *SVC[T]) SupportVectors() *Matrix[T] {
+	return s.support
+}
+
+// LinearSVC is a linear support vector classifier fitted by the dual coordinate descent method of
+// Hsieh et al. (2008). It scales to far more samples than SVC with a LinearKernel.
+// The intercept is learned as the weight of a constant feature, so unlike in SVC it is regularized.
+// More than two classes are handled one-vs-rest.
+type LinearSVC[T Float] struct {
+	// Loss is HingeLoss or SquaredHingeLoss. If nil, SquaredHingeLoss is used.
+	Loss Loss
+	// C trades margin width against training errors; larger values fit the training data more closely.
+	// If zero, 1 is used.
+	C float64
+	// Tol stops descent once the spread of the projected gradient of the dual is at most Tol.
+	// If zero, 0.1 is used, as in LIBLINEAR.
+	Tol float64
+	// MaxIter bounds the number of passes over the samples. If zero, 1000 is used.
+	MaxIter int
+	// Seed seeds the order in which samples are visited, so fits are reproducible.
+	Seed int64
+
+	classes []T
+	coef    *Matrix[T]
+	iters   int
+}
+
+// Fit fits one linear machine per class, or a single machine for a binary problem.
+func (ls *LinearSVC[T]) Fit(X, y *Matrix[T]) (err error) {
+	if X.Err() != nil {
+		return X.Err()
+	}
+	if y.Err() != nil {
+		return y.Err()
+	}
+	n, p := X.Size()
+	if y.rows != n || y.cols != 1 {
+		return fmt.Errorf("LinearSVC.Fit: y must be a (%d x 1) column vector, got (%d x %d)", n, y.rows, y.cols)
+	}
+	squared := true
+	if ls.Loss != nil {
+		h, ok := ls.Loss.(hinge)
+		if !ok {
+			return fmt.Errorf("LinearSVC.Fit: unsupported loss %T", ls.Loss)
+		}
+		squared = h.hinge()
+	}
+	c := ls.C
+	if c < 0 {
+		return fmt.Errorf("LinearSVC.Fit: C must not be negative, got %v", c)
+	}
+	if c == 0 {
+		c = 1
+	}
+	tol, maxIter := ls.Tol, ls.MaxIter
+	if tol == 0 {
+		tol = 0.1
+	}
+	if maxIter == 0 {
+		maxIter = defaultMaxIter
+	}
+	classes, labels := encodeLabels(y)
+	if len(classes) < 2 {
+		return fmt.Errorf("LinearSVC.Fit: need samples of at least two classes, got %d", len(classes))
+	}
+
+	xa := convert[float64](withIntercept(X))
+	ys := ovrTargets(labels, len(classes))
+	rng := rand.New(rand.NewSource(ls.Seed))
+	ls.classes, ls.coef, ls.iters = classes, Empty[T](p+1, ys.cols), 0
+	var failed bool
+	for k := 0; k < ys.cols; k++ {
+		yk := make([]float64, n)
+		for i := range yk {
+			yk[i] = ys.at(i, k)
+		}
+		w, iters, converged := dualCoordinateDescent(xa, yk, c, squared, tol, maxIter, rng)
+		for j, v := range w {
+			ls.coef.set(j, k, T(v))
+		}
+		if iters > ls.iters {
+			ls.iters = iters
+		}
+		failed = failed || !converged
+	}
+	if failed {
+		return fmt.Errorf("LinearSVC.Fit: %w after %d passes", ErrNoConvergence, maxIter)
+	}
+	return nil
+}
+
+// dualCoordinateDescent fits the weights of a linear SVM with the hinge loss, or with the squared hinge loss
+// if squared is set, to the rows of xa with ±1 labels y, by coordinate descent on the dual.
+// It returns the weights, the number of passes made and whether it converged.
+func dualCoordinateDescent(xa *Matrix[float64], y []float64, c float64, squared bool, tol float64, maxIter int, rng *rand.Rand) ([]float64, int, bool) {
+	n, p := xa.Size()
+	// the squared hinge loss moves the box constraint onto the diagonal of Q
+	d, u := 0.0, c
+	if squared {
+		d, u = 1/(2*c), math.Inf(1)
+	}
+	qd := make([]float64, n)
+	for i := range qd {
+		x := xa.row(i)
+		qd[i] = dotf(x, x) + d
+	}
+	alpha, w := make([]float64, n), make([]float64, p)
+	order := rng.Perm(n)
+	for iter := 1; iter <= maxIter; iter++ {
+		rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		pgmax, pgmin := math.Inf(-1), math.Inf(1)
+		for _, i := range order {
+			x := xa.row(i)
+			g := y[i]*dotf(w, x) - 1 + d*alpha[i]
+			pg := g
+			switch {
+			case alpha[i] == 0:
+				pg = math.Min(g, 0)
+			case alpha[i] == u:
+				pg = math.Max(g, 0)
+			}
+			pgmax, pgmin = math.Max(pgmax, pg), math.Min(pgmin, pg)
+			if pg != 0 {
+				old := alpha[i]
+				alpha[i] = math.Min(math.Max(old-g/qd[i], 0), u)
+				axpy((alpha[i]-old)*y[i], x, w)
+			}
+		}
+		if pgmax-pgmin <= tol {
+			return w, iter, true
+		}
+	}
+	return w, maxIter, false
+}
+
+// DecisionFunction returns the signed margin of each row of X: a column vector for a binary problem,
+// where positive values favour the second class, otherwise one column per class.
+func (ls *LinearSVC[T]) DecisionFunction(X *Matrix[T]) (*Matrix[T], error) {
+	d := withIntercept(X).Mul(ls.coef)
+	if d.Err() != nil {
+		return nil, d.Err()
+	}
+	return d, nil
+}
+
+func (ls *LinearSVC[T]) Predict(X *Matrix[T]) (y_hat *Matrix[T], err error) {
+	d, err := ls.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLabels(binaryScores(d), ls.classes), nil
+}
+
+// PredictProba returns one-hot rows for the predicted classes.
+// Support vector machines do not model probabilities, so all the mass goes to the predicted class.
+func (ls *LinearSVC[T]) PredictProba(X *Matrix[T]) (*Matrix[T], error) {
+	d, err := ls.DecisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return oneHot(binaryScores(d)), nil
+}
+
+func (ls *LinearSVC[T]) Score(X, y *Matrix[T]) (float64, error) {
+	yh, err := ls.Predict(X)
+	if err != nil {
+		return -1, err
+	}
+	return accuracy(y, yh), nil
+}
+
+func (ls *LinearSVC[T]) Classes() []T {
+	return ls.classes
+}
+
+// Coefficients returns the (p+1) x k weights, with the intercepts in the first row.
+// For a binary problem k is 1.
+func (ls *LinearSVC[T]) Coefficients() *Matrix[T] {
+	return ls.coef
+}
+
+// Iterations returns the largest number of passes made for any class by the last call to Fit.
+func (ls *LinearSVC[T]) Iterations() int {
+	return ls.iters
+}
@@ -0,0 +1,150 @@
+package pa
+
+import (
+	"math"
+	"testing"
+)
+
+// circles returns n samples on each of two concentric circles of radius 1 and 3, labelled 0 and 1.
+// No line separates them.
+func circles(n int) (*Matrix[float64], *Matrix[float64]) {
+	X, y := Empty[float64](2*n, 2), Empty[float64](2*n, 1)
+	for i := 0; i < 2*n; i++ {
+		r, theta := 1.0, 2*math.Pi*float64(i)/float64(n)
+		if i >= n {
+			r = 3
+			y.set(i, 0, 1)
+		}
+		X.set(i, 0, r*math.Cos(theta))
+		X.set(i, 1, r*math.Sin(theta))
+	}
+	return X, y
+}
+
+// scaledKernel is a custom kernel, showing that SVC accepts any Kernel.
+type scaledKernel struct{ s float64 }
+
+func (k scaledKernel) Eval(x, y Array[float64]) float64 {
+	return k.s * LinearKernel[float64]{}.Eval(x, y)
+}
+
+func TestSVCHardMargin(t *testing.T) {
+	// the maximum-margin boundary between 0 and 2 is x = 1, with decision function x - 1
+	X := NewMatrix([][]float64{{0}, {2}}, nil)
+	y := NewMatrix([][]float64{{0}, {1}}, nil)
+	for _, k := range []Kernel[float64]{LinearKernel[float64]{}, scaledKernel{4}} {
+		s := &SVC[float64]{Kernel: k, C: 100, Tol: 1e-9}
+		if err := s.Fit(X, y); err != nil {
+			t.Fatalf("Fit() error: %v", err)
+		}
+		got, err := s.DecisionFunction(NewMatrix([][]float64{{0}, {1}, {3}}, nil))
+		if err != nil {
+			t.Fatalf("DecisionFunction() error: %v", err)
+		}
+		want := NewMatrix([][]float64{{-1}, {0}, {2}}, nil)
+		if !approxEqual(got, want, 1e-9) {
+			t.Errorf("%T: \nDecisionFunction():\n%s\nwant:\n%s", k, got, want)
+		}
+		if r, _ := s.SupportVectors().Size(); r != 2 {
+			t.Errorf("%T: %d support vectors, want 2", k, r)
+		}
+	}
+}
+
+func TestSVC(t *testing.T) {
+	circleX, circleY := circles(30)
+	blobX, blobY := blobs([][]float64{{0, 0}, {6, 0}, {0, 6}}, 30)
+	tests := []struct {
+		name   string
+		kernel Kernel[float64]
+		X, y   *Matrix[float64]
+		min    float64
+	}{
+		{"rbf circles", nil, circleX, circleY, 1},
+		{"poly circles", PolyKernel[float64]{Degree: 2, Coef0: 1}, circleX, circleY, 1},
+		{"linear blobs", LinearKernel[float64]{}, blobX, blobY, 0.95},
+		{"rbf blobs", RBFKernel[float64]{Gamma: 0.5}, blobX, blobY, 0.95},
+		{"sigmoid blobs", SigmoidKernel[float64]{Gamma: 0.01}, blobX, blobY, 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SVC[float64]{Kernel: tt.kernel, C: 10}
+			if err := s.Fit(tt.X, tt.y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := s.Score(tt.X, tt.y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < tt.min {
+				t.Errorf("Score() = %v, want at least %v", acc, tt.min)
+			}
+			proba, err := s.PredictProba(tt.X)
+			if err != nil {
+				t.Fatalf("PredictProba() error: %v", err)
+			}
+			for i := 0; i < proba.rows; i++ {
+				if v := sum(proba.row(i)); math.Abs(v-1) > 1e-12 {
+					t.Fatalf("row %d probabilities sum to %v", i, v)
+				}
+			}
+		})
+	}
+
+	// no linear boundary separates the circles
+	s := &SVC[float64]{Kernel: LinearKernel[float64]{}}
+	if err := s.Fit(circleX, circleY); err != nil {
+		t.Fatalf("Fit() error: %v", err)
+	}
+	if acc, _ := s.Score(circleX, circleY); acc > 0.8 {
+		t.Errorf("linear Score() on circles = %v, want at most 0.8", acc)
+	}
+}
+
+func TestLinearSVC(t *testing.T) {
+	tests := []struct {
+		name    string
+		loss    Loss
+		centres [][]float64
+	}{
+		{"squared hinge binary", nil, [][]float64{{0, 0}, {4, 4}}},
+		{"hinge binary", HingeLoss{}, [][]float64{{0, 0}, {4, 4}}},
+		{"squared hinge multiclass", nil, [][]float64{{0, 0}, {8, 0}, {0, 8}}},
+		{"hinge multiclass", HingeLoss{}, [][]float64{{0, 0}, {8, 0}, {0, 8}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			X, y := blobs(tt.centres, 40)
+			ls := &LinearSVC[float64]{Loss: tt.loss}
+			if err := ls.Fit(X, y); err != nil {
+				t.Fatalf("Fit() error: %v", err)
+			}
+			acc, err := ls.Score(X, y)
+			if err != nil {
+				t.Fatalf("Score() error: %v", err)
+			}
+			if acc < 0.95 {
+				t.Errorf("Score() = %v, want at least 0.95", acc)
+			}
+		})
+	}
+
+	X, y := blobs([][]float64{{0, 0}, {4, 4}}, 10)
+	if err := (&LinearSVC[float64]{Loss: LogisticLoss{}}).Fit(X, y); err == nil {
+		t.Error("Fit() with LogisticLoss returned nil error")
+	}
+
+	// pointers to the hinge losses solve the same problem as the values, as they do for SGDClassifier
+	for _, pair := range [][2]Loss{{HingeLoss{}, &HingeLoss{}}, {SquaredHingeLoss{}, &SquaredHingeLoss{}}} {
+		byValue, byPointer := &LinearSVC[float64]{Loss: pair[0]}, &LinearSVC[float64]{Loss: pair[1]}
+		if err := byValue.Fit(X, y); err != nil {
+			t.Fatalf("Fit() with %T error: %v", pair[0], err)
+		}
+		if err := byPointer.Fit(X, y); err != nil {
+			t.Fatalf("Fit() with %T error: %v", pair[1], err)
+		}
+		if got, want := byPointer.Coefficients(), byValue.Coefficients(); !approxEqual(got, want, 1e-12) {
+			t.Errorf("Coefficients() with %T:\n%s\nwant as with %T:\n%s", pair[1], got, pair[0], want)
+		}
+	}
+}
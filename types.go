@@ -31,3 +31,9 @@ func (a Array[T]) Sub(b Array[T]) Array[T] {
 
 	return r
 }
+
+// Float is the set of element types for which decompositions that need real arithmetic,
+// such as the SVD and eigendecompositions, are defined.
+type Float interface {
+	constraints.Float
+}
@@ -16,3 +16,75 @@ func mean[T Number](a []T) T {
 	}
 	return sum / count
 }
+
+func abs[T Number](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// epsilon returns the machine epsilon of T, or 0 for integer types.
+func epsilon[T Number]() float64 {
+	switch any(T(0)).(type) {
+	case float32:
+		return 0x1p-23
+	case float64:
+		return 0x1p-52
+	}
+	return 0
+}
+
+// maxAbs returns the largest absolute value of any element of m.
+func maxAbs[T Number](m *Matrix[T]) T {
+	var mx T
+	for i := 0; i < m.rows; i++ {
+		for _, v := range m.row(i) {
+			if abs(v) > mx {
+				mx = abs(v)
+			}
+		}
+	}
+	return mx
+}
+
+func maxT[T Number](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// argmax returns the index of the largest element of a, the first one if there are several.
+func argmax[T Number](a []T) int {
+	best := 0
+	for i, v := range a {
+		if v > a[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// dotf returns the dot product of a and b, which must have the same length.
+func dotf(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// axpy computes y += a·x.
+func axpy(a float64, x, y []float64) {
+	for i := range x {
+		y[i] += a * x[i]
+	}
+}
+
+// scale computes x *= a.
+func scale(a float64, x []float64) {
+	for i := range x {
+		x[i] *= a
+	}
+}
@@ -0,0 +1,107 @@
+package pa
+
+import "fmt"
+
+// At returns the element at row i and column j. It panics if i or j is out of range.
+func (m *Matrix[T]) At(i, j int) T {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("index (%d, %d) out of range for (%d x %d) matrix", i, j, m.rows, m.cols))
+	}
+	return m.at(i, j)
+}
+
+// Set stores v at row i and column j. It panics if i or j is out of range.
+// If m is a view, the write is visible through its parent.
+func (m *Matrix[T]) Set(i, j int, v T) {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("index (%d, %d) out of range for (%d x %d) matrix", i, j, m.rows, m.cols))
+	}
+	m.set(i, j, v)
+}
+
+// view returns the rows r0, r0+step, ... below r1 and columns [c0, c1) of m, sharing m's storage.
+func (m *Matrix[T]) view(r0, r1, c0, c1, step int) *Matrix[T] {
+	r := (r1 - r0 + step - 1) / step
+	c := c1 - c0
+	var data []T
+	if r > 0 {
+		off := r0*m.stride + c0
+		data = m.data[off : off+(r-1)*step*m.stride+c]
+	}
+	var columns []string
+	if len(m.columns) == m.cols {
+		columns = m.columns[c0:c1:c1]
+	}
+	v := newMatrix(r, c, data, columns)
+	v.stride = step * m.stride
+	return v
+}
+
+// Slice returns a view of rows [r0, r1) and columns [c0, c1) of m.
+// The view shares storage with m, so writes through either are visible in both; use Clone for a copy.
+func (m *Matrix[T]) Slice(r0, r1, c0, c1 int) *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	if r0 < 0 || r1 > m.rows || r0 > r1 || c0 < 0 || c1 > m.cols || c0 > c1 {
+		m.err = fmt.Errorf("m.Slice: [%d:%d, %d:%d] out of bounds for (%d x %d) matrix", r0, r1, c0, c1, m.rows, m.cols)
+		return m
+	}
+	return m.view(r0, r1, c0, c1, 1)
+}
+
+// Row returns a 1 x n view of the ith row of m.
+func (m *Matrix[T]) Row(i int) *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	if i < 0 || i >= m.rows {
+		m.err = fmt.Errorf("m.Row: row number out of bounds: %d >= %d", i, m.rows)
+		return m
+	}
+	return m.view(i, i+1, 0, m.cols, 1)
+}
+
+// Col returns an m x 1 view of the jth column of m.
+func (m *Matrix[T]) Col(j int) *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	if j < 0 || j >= m.cols {
+		m.err = fmt.Errorf("m.Col: column number out of bounds: %d >= %d", j, m.cols)
+		return m
+	}
+	return m.view(0, m.rows, j, j+1, 1)
+}
+
+// Strided returns a view of every step-th row of m, starting at row r0.
+func (m *Matrix[T]) Strided(r0, step int) *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	if step < 1 {
+		m.err = fmt.Errorf("m.Strided: step must be positive, got %d", step)
+		return m
+	}
+	if r0 < 0 || r0 > m.rows {
+		m.err = fmt.Errorf("m.Strided: row number out of bounds: %d > %d", r0, m.rows)
+		return m
+	}
+	return m.view(r0, m.rows, 0, m.cols, step)
+}
+
+// Clone returns a copy of m backed by its own contiguous storage.
+func (m *Matrix[T]) Clone() *Matrix[T] {
+	if m.err != nil {
+		return m
+	}
+	data := make([]T, 0, m.rows*m.cols)
+	for i := 0; i < m.rows; i++ {
+		data = append(data, m.row(i)...)
+	}
+	var columns []string
+	if m.columns != nil {
+		columns = append([]string{}, m.columns...)
+	}
+	return newMatrix(m.rows, m.cols, data, columns)
+}
@@ -0,0 +1,71 @@
+package pa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatrix_Slice(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, nil)
+	tests := []struct {
+		name string
+		got  *Matrix[float64]
+		want *Matrix[float64]
+	}{
+		{
+			name: "block",
+			got:  m.Slice(1, 3, 0, 2),
+			want: NewMatrix([][]float64{{4, 5}, {7, 8}}, nil),
+		},
+		{
+			name: "row",
+			got:  m.Row(1),
+			want: NewMatrix([][]float64{{4, 5, 6}}, nil),
+		},
+		{
+			name: "col",
+			got:  m.Col(2),
+			want: NewMatrix([][]float64{{3}, {6}, {9}}, nil),
+		},
+		{
+			name: "strided",
+			got:  m.Strided(0, 2),
+			want: NewMatrix([][]float64{{1, 2, 3}, {7, 8, 9}}, nil),
+		},
+		{
+			name: "nested",
+			got:  m.Slice(0, 3, 1, 3).Col(1).Slice(1, 3, 0, 1),
+			want: NewMatrix([][]float64{{6}, {9}}, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got.Clone(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\nview:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatrix_SliceShares(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2}, {3, 4}}, nil)
+	m.Col(1).Set(1, 0, 10)
+	if got := m.At(1, 1); got != 10 {
+		t.Errorf("write through view not visible in parent: got %v, want 10", got)
+	}
+	c := m.Clone()
+	c.Set(0, 0, -1)
+	if got := m.At(0, 0); got != 1 {
+		t.Errorf("write to clone visible in parent: got %v, want 1", got)
+	}
+}
+
+func TestMatrix_SliceErr(t *testing.T) {
+	m := NewMatrix([][]float64{{1, 2}, {3, 4}}, nil)
+	if err := m.Slice(0, 3, 0, 1).T().Err(); err == nil {
+		t.Error("expected out of bounds error, got nil")
+	}
+	if err := NewMatrix([][]float64{{1, 2}}, nil).Col(2).Err(); err == nil {
+		t.Error("expected out of bounds error, got nil")
+	}
+}
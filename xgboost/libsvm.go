@@ -9,25 +9,31 @@ import (
 	"strings"
 )
 
+// DMatrixFromLibSVM reads samples in the sparse libsvm text format into rows of a label followed by
+// the features at their 1-based indices, so feature j of a row is at position j. Rows end at their
+// last feature, so they may have different lengths.
 func DMatrixFromLibSVM(r io.Reader) ([][]float64, error) {
 	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanWords)
 	run := 0
 	mat := [][]float64{}
-	working := []float64{}
+	var working []float64
 	for scanner.Scan() {
 		cur := scanner.Text()
 		if !strings.Contains(cur, ":") { // at the beginning of a line
-			run = 0
-			mat = append(mat, working)
-			working = []float64{}
+			if working != nil {
+				mat = append(mat, working)
+			}
 			f, err := strconv.ParseFloat(cur, 64)
 			if err != nil {
 				return nil, fmt.Errorf("error parsing float: %w", err)
 			}
-			working = append(working, f)
-			run++
+			// run is the index of the last feature placed; the label takes position 0
+			working, run = []float64{f}, 0
 		} else {
+			if working == nil {
+				return nil, errors.New("feature found before any label")
+			}
 			split := strings.Split(cur, ":")
 			idx, err := strconv.Atoi(split[0])
 			if err != nil {
@@ -38,20 +44,19 @@ func DMatrixFromLibSVM(r io.Reader) ([][]float64, error) {
 				return nil, err
 			}
 			length := idx - run
-			if length == 0 {
-				working = append(working, val)
-			} else if length < 0 {
-				return nil, errors.New("negative index found relative to file")
-			} else {
-				working = append(working, append(make([]float64, length-1), val)...)
+			if length <= 0 {
+				return nil, errors.New("feature indices must be positive and increasing within a line")
 			}
+			working = append(working, append(make([]float64, length-1), val)...)
 			run = idx
-
 		}
 	}
 	err := scanner.Err()
 	if err != nil {
 		return nil, fmt.Errorf("error scanning from reader: %w", err)
 	}
+	if working != nil {
+		mat = append(mat, working)
+	}
 	return mat, nil
 }
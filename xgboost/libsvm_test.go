@@ -1,10 +1,11 @@
 package xgboost
 
 import (
-	"encoding/json"
-	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/kipukun/pa"
 )
 
 const (
@@ -12,10 +13,55 @@ const (
 )
 
 func TestDMatrixFromLibSVM(t *testing.T) {
-	matrix, err := DMatrixFromLibSVM(strings.NewReader(svm1))
-	if err != nil {
-		t.Errorf("error creating matrix: %s", err.Error())
+	tests := []struct {
+		name    string
+		input   string
+		want    [][]float64
+		wantErr bool
+	}{
+		{
+			name:  "ragged",
+			input: "0 1:5 5:27.1\n1 2:5 3:98\n",
+			want:  [][]float64{{0, 5, 0, 0, 0, 27.1}, {1, 0, 5, 98}},
+		},
+		{name: "single line", input: "1 2:5 3:98\n", want: [][]float64{{1, 0, 5, 98}}},
+		{name: "no trailing newline", input: svm1, want: [][]float64{
+			append(append([]float64{0, 5, 0, 0, 0, 27.1}, make([]float64, 14)...), 52.5),
+			append(append([]float64{1, 0, 5, 0, 0, 98}, make([]float64, 47)...), 5),
+		}},
+		{name: "label only", input: "1\n0 1:2\n", want: [][]float64{{1}, {0, 2}}},
+		{name: "empty", input: "", want: [][]float64{}},
+		{name: "zero index", input: "1 0:1\n", wantErr: true},
+		{name: "decreasing index", input: "1 3:1 2:1\n", wantErr: true},
+		{name: "feature before label", input: "1:1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DMatrixFromLibSVM(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DMatrixFromLibSVM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DMatrixFromLibSVM() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDMatrixFromLibSVMMatchesReadLibSVM(t *testing.T) {
+	// pa.FromLibSVM of what DMatrixFromLibSVM returns is what pa.ReadLibSVM reads from the same text
+	for _, input := range []string{svm1, "0 1:5 5:27.1\n1 2:5 3:98\n", "1 2:5 3:98\n"} {
+		rows, err := DMatrixFromLibSVM(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("DMatrixFromLibSVM(%q) error: %v", input, err)
+		}
+		X, y := pa.FromLibSVM(rows)
+		wantX, wantY, err := pa.ReadLibSVM(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ReadLibSVM(%q) error: %v", input, err)
+		}
+		if !reflect.DeepEqual(X, wantX) || !reflect.DeepEqual(y, wantY) {
+			t.Errorf("FromLibSVM(DMatrixFromLibSVM(%q)) = %v, %v, want %v, %v", input, X, y, wantX, wantY)
+		}
 	}
-	b, _ := json.Marshal(matrix)
-	fmt.Println(string(b))
 }